@@ -2,6 +2,7 @@ package ast
 
 import (
 	"github.com/carlcui/expressive/symbolTable"
+	"github.com/carlcui/expressive/token"
 	"github.com/carlcui/expressive/typing"
 )
 
@@ -10,6 +11,7 @@ type Node interface {
 	Accept(visitor Visitor)
 	VisitChildren(visitor Visitor)
 
+	GetToken() *token.Token
 	GetLocation() string
 
 	SetParent(node Node)
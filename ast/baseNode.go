@@ -19,6 +19,10 @@ func CreateBaseNode(tok *token.Token, parent Node) *BaseNode {
 	return &BaseNode{Tok: tok, Parent: parent}
 }
 
+func (node *BaseNode) GetToken() *token.Token {
+	return node.Tok
+}
+
 func (node *BaseNode) GetLocation() string {
 	if node.Tok == nil {
 		return ""
@@ -0,0 +1,83 @@
+package ast
+
+import (
+	"encoding/json"
+
+	"github.com/carlcui/expressive/token"
+	"github.com/carlcui/expressive/typing"
+)
+
+// CallNode represents a call of a function by name, either as an expression or as a statement
+// discarding its result
+type CallNode struct {
+	*BaseNode
+	Callee Node // the identifier naming the function
+	Args   []Node
+}
+
+// Accept is part of visitor pattern.
+func (node *CallNode) Accept(visitor Visitor) {
+	visitor.VisitEnterCallNode(node)
+	node.VisitChildren(visitor)
+	visitor.VisitLeaveCallNode(node)
+}
+
+// VisitChildren is part of visitor pattern. Visit the arguments in order. The callee is not a
+// value, and is resolved by the visitors needing it.
+func (node *CallNode) VisitChildren(visitor Visitor) {
+	for _, arg := range node.Args {
+		Accept(arg, visitor)
+	}
+}
+
+// Name is the name of the function called
+func (node *CallNode) Name() string {
+	return node.Callee.GetToken().Raw
+}
+
+// IsStatement tells if the result of the call is discarded: the call is a statement of a
+// program or a block, or the initialization or iteration statement of a for
+func (node *CallNode) IsStatement() bool {
+	switch parent := node.Parent.(type) {
+	case *ProgramNode, *BlockNode:
+		return true
+	case *ForStmtNode:
+		return parent.InitializationStmt == node || parent.IterationStmt == node
+	default:
+		return false
+	}
+}
+
+func (node *CallNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		NodeType string
+		Token    *token.Token
+		Callee   Node
+		Args     []Node
+		Typing   typing.Typing
+	}{
+		NodeType: "call",
+		Token:    node.BaseNode.Tok,
+		Callee:   node.Callee,
+		Args:     node.Args,
+		Typing:   node.Typing,
+	})
+}
+
+// CreateCallNode creates a call of callee, whose token locates the call
+func CreateCallNode(callee Node) *CallNode {
+	var node CallNode
+	node.BaseNode = CreateBaseNode(callee.GetToken(), nil)
+	node.Callee = callee
+	node.Args = make([]Node, 0)
+
+	callee.SetParent(&node)
+
+	return &node
+}
+
+// AddArg appends an argument to the call
+func (node *CallNode) AddArg(arg Node) {
+	arg.SetParent(node)
+	node.Args = append(node.Args, arg)
+}
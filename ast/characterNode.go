@@ -37,42 +37,7 @@ func (node *CharacterNode) StringValue() string {
 func (node *CharacterNode) Init(tok *token.Token) {
 	node.BaseNode = CreateBaseNode(tok, nil)
 
-	raw := tok.Raw
-
-	_, totalSize := utf8.DecodeRuneInString(raw)
-
-	firstRune, size := utf8.DecodeRuneInString(raw[totalSize:])
-
-	totalSize += size
-
-	if firstRune == '\\' {
-		secondRune, _ := utf8.DecodeRuneInString(raw[totalSize:])
-
-		switch secondRune {
-		case 'n':
-			node.Val = '\n'
-			break
-		case 't':
-			node.Val = '\t'
-			break
-		case '0':
-			node.Val = '\x00'
-			break
-		case '\\':
-			node.Val = '\\'
-			break
-		case '\'':
-			node.Val = '\''
-			break
-		case '"':
-			node.Val = '"'
-			break
-		default:
-			panic(tok.GetLocation() + ": illegal escape sequence")
-		}
-	} else {
-		node.Val = firstRune
-	}
+	node.Val, _ = utf8.DecodeRuneInString(token.Unescape(literalContent(tok.Raw)))
 }
 
 func (node *CharacterNode) MarshalJSON() ([]byte, error) {
@@ -0,0 +1,69 @@
+package ast
+
+import (
+	"encoding/json"
+
+	"github.com/carlcui/expressive/token"
+)
+
+// ExternNode represents the declaration of a function defined outside of the program, e.g. in
+// the C library, like `extern func puts(s: string) -> int;`
+type ExternNode struct {
+	*BaseNode
+	Identifier Node   // the name of the function
+	ParamNames []Node // the identifiers naming the parameters, for documentation only
+	ParamTypes []Node // the type literals of the parameters
+	Variadic   bool   // whether any number of arguments follow the parameters, as with `...`
+	ResultType Node   // nil for a function returning nothing
+}
+
+// Accept is part of visitor pattern.
+func (node *ExternNode) Accept(visitor Visitor) {
+	visitor.VisitEnterExternNode(node)
+	node.VisitChildren(visitor)
+	visitor.VisitLeaveExternNode(node)
+}
+
+// VisitChildren is part of visitor pattern. Visit the types of the parameters, then the type of
+// the result. The identifiers do not name values, and are resolved by the visitors needing them.
+func (node *ExternNode) VisitChildren(visitor Visitor) {
+	for _, paramType := range node.ParamTypes {
+		Accept(paramType, visitor)
+	}
+
+	Accept(node.ResultType, visitor)
+}
+
+// Name is the name of the function declared
+func (node *ExternNode) Name() string {
+	return node.Identifier.GetToken().Raw
+}
+
+// AddParam appends a parameter named by name, of the type literal paramType
+func (node *ExternNode) AddParam(name Node, paramType Node) {
+	name.SetParent(node)
+	paramType.SetParent(node)
+
+	node.ParamNames = append(node.ParamNames, name)
+	node.ParamTypes = append(node.ParamTypes, paramType)
+}
+
+func (node *ExternNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		NodeType   string
+		Token      *token.Token
+		Identifier Node
+		ParamNames []Node
+		ParamTypes []Node
+		Variadic   bool
+		ResultType Node
+	}{
+		NodeType:   "extern",
+		Token:      node.BaseNode.Tok,
+		Identifier: node.Identifier,
+		ParamNames: node.ParamNames,
+		ParamTypes: node.ParamTypes,
+		Variadic:   node.Variadic,
+		ResultType: node.ResultType,
+	})
+}
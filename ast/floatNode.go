@@ -2,7 +2,9 @@ package ast
 
 import (
 	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/carlcui/expressive/token"
 	"github.com/carlcui/expressive/typing"
@@ -24,16 +26,22 @@ func (node *FloatNode) VisitChildren(visitor Visitor) {
 
 }
 
-func (node *FloatNode) Init(tok *token.Token) {
+// Init initializes a float node with a token. It returns an error if the literal does not fit in
+// a float.
+func (node *FloatNode) Init(tok *token.Token) error {
 	node.BaseNode = CreateBaseNode(tok, nil)
 
-	val, err := strconv.ParseFloat(tok.Raw, 32)
+	val, err := strconv.ParseFloat(strings.Replace(tok.Raw, "_", "", -1), 32)
 
-	if err != nil {
+	if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+		return fmt.Errorf("float literal %v does not fit in float", tok.Raw)
+	} else if err != nil {
 		panic(tok.GetLocation() + ": error parsing float")
 	}
 
 	node.Val = float32(val)
+
+	return nil
 }
 
 func (node *FloatNode) MarshalJSON() ([]byte, error) {
@@ -28,13 +28,27 @@ func (node *IdentifierNode) IsBeingDeclared() bool {
 		return false
 	}
 
-	declarationNode, ok := node.Parent.(*VariableDeclarationNode)
-
-	if !ok {
+	switch declarationNode := node.Parent.(type) {
+	case *VariableDeclarationNode:
+		return declarationNode.Identifier == node
+	case *ExternNode:
+		return declarationNode.Identifier == node
+	default:
 		return false
 	}
+}
 
-	return declarationNode.Identifier == node
+// IsBeingAssigned tells if the identifier is the left-hand side of an assignment, a compound
+// assignment, an increment or a decrement
+func (node *IdentifierNode) IsBeingAssigned() bool {
+	switch parent := node.Parent.(type) {
+	case *AssignmentNode:
+		return parent.LHS == node
+	case *IncDecNode:
+		return parent.LHS == node
+	default:
+		return false
+	}
 }
 
 func (node *IdentifierNode) FindDeclarationScope() *symbolTable.Scope {
@@ -2,7 +2,10 @@ package ast
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	"strconv"
+	"strings"
 
 	"github.com/carlcui/expressive/token"
 	"github.com/carlcui/expressive/typing"
@@ -24,17 +27,52 @@ func (node *IntegerNode) VisitChildren(visitor Visitor) {
 
 }
 
-// Init initializes an integer node with a token
-func (node *IntegerNode) Init(tok *token.Token) {
+// Init initializes an integer node with a token. It returns an error if the literal does not
+// fit in an int.
+func (node *IntegerNode) Init(tok *token.Token) error {
 	node.BaseNode = CreateBaseNode(tok, nil)
 
-	val, err := strconv.Atoi(tok.Raw)
+	val, err := parseInt(tok.Raw)
 
-	if err != nil {
+	if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+		return fmt.Errorf("int literal %v does not fit in int, from %v to %v", tok.Raw, math.MinInt32, math.MaxInt32)
+	} else if err != nil {
 		panic(tok.GetLocation() + ": error parsing int")
 	}
 
-	node.Val = val
+	node.Val = int(val)
+
+	return nil
+}
+
+// parseInt parses an int literal, with digits of any base separated by underscores. A decimal
+// literal may start with zeros, unlike in Go where it would be octal.
+func parseInt(raw string) (int64, error) {
+	digits := strings.Replace(raw, "_", "", -1)
+	sign := ""
+
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+
+	base := 10
+
+	if len(digits) > 1 && digits[0] == '0' {
+		switch strings.ToLower(digits[:2]) {
+		case "0x":
+			base = 16
+		case "0b":
+			base = 2
+		case "0o":
+			base = 8
+		}
+	}
+
+	if base != 10 {
+		digits = digits[2:]
+	}
+
+	return strconv.ParseInt(sign+digits, base, 32)
 }
 
 func (node *IntegerNode) MarshalJSON() ([]byte, error) {
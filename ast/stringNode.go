@@ -2,17 +2,16 @@ package ast
 
 import (
 	"encoding/json"
-	"strings"
 	"unicode/utf8"
 
 	"github.com/carlcui/expressive/token"
 	"github.com/carlcui/expressive/typing"
 )
 
-// StringNode represents an integer constant node.
+// StringNode represents a string constant node.
 type StringNode struct {
 	*BaseNode
-	Val string
+	Val string // escape sequences decoded
 }
 
 // Accept is part of visitor pattern.
@@ -25,20 +24,23 @@ func (node *StringNode) VisitChildren(visitor Visitor) {
 
 }
 
-// Init initializes an integer node with a token
+// Init initializes a string node with a token
 func (node *StringNode) Init(tok *token.Token) {
 	node.BaseNode = CreateBaseNode(tok, nil)
 
-	_, start := utf8.DecodeRuneInString(tok.Raw)
-	_, lastSize := utf8.DecodeLastRuneInString(tok.Raw)
-
-	node.Val = tok.Raw[start : len(tok.Raw)-lastSize]
+	node.Val = token.Unescape(literalContent(tok.Raw))
 }
 
 func (node *StringNode) StringValue() string {
-	value := node.Val + "\x00" // append terminating character
+	return node.Val + "\x00" // append terminating character
+}
+
+// literalContent returns the content of a string or character literal between its quotes
+func literalContent(raw string) string {
+	_, start := utf8.DecodeRuneInString(raw)
+	_, lastSize := utf8.DecodeLastRuneInString(raw)
 
-	return strings.Replace(value, "\\n", "\n", -1)
+	return raw[start : len(raw)-lastSize]
 }
 
 func (node *StringNode) MarshalJSON() ([]byte, error) {
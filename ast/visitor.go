@@ -37,6 +37,12 @@ type Visitor interface {
 	VisitEnterPrintNode(node *PrintNode)
 	VisitLeavePrintNode(node *PrintNode)
 
+	VisitEnterCallNode(node *CallNode)
+	VisitLeaveCallNode(node *CallNode)
+
+	VisitEnterExternNode(node *ExternNode)
+	VisitLeaveExternNode(node *ExternNode)
+
 	// exprs
 
 	VisitEnterTernaryOperatorNode(node *TernaryOperatorNode)
@@ -0,0 +1,33 @@
+package builtin
+
+// PowInt raises base to exponent, wrapping around at 32 bits. A negative exponent truncates the
+// result towards zero, as integer division does.
+func PowInt(base int32, exponent int32) int32 {
+	if exponent < 0 {
+		switch base {
+		case 1:
+			return 1
+		case -1:
+			if exponent%2 == 0 {
+				return 1
+			}
+
+			return -1
+		default:
+			return 0
+		}
+	}
+
+	result := int32(1)
+
+	for exponent > 0 {
+		if exponent&1 == 1 {
+			result *= base
+		}
+
+		base *= base
+		exponent >>= 1
+	}
+
+	return result
+}
@@ -0,0 +1,248 @@
+// Package builtin holds the run time support shared by the interpreter and the bytecode vm
+package builtin
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// conversion is one conversion specification of a printf format, e.g. %-8.3f
+type conversion struct {
+	flags     string
+	width     string
+	precision string // including the dot, empty if not given
+	verb      byte
+}
+
+// goFormat returns the fmt format for the conversion, using verb in place of the C one
+func (spec *conversion) goFormat(verb byte) string {
+	return "%" + spec.flags + spec.width + spec.precision + string(verb)
+}
+
+const printfFlags = "-+ #0"
+const printfLengthModifiers = "hlLqjzt"
+
+// Printf writes args formatted the way C printf does. Ints are int32, floats are float64, bools
+// print as 0 or 1 and chars are strings. Arguments that do not match their conversion are
+// reported as errors rather than printing garbage.
+func Printf(out io.Writer, format string, args []interface{}) error {
+	argIndex := 0
+
+	nextArg := func(verb byte) (interface{}, error) {
+		if argIndex >= len(args) {
+			return nil, fmt.Errorf("missing argument for %%%c in format %q", verb, format)
+		}
+
+		arg := args[argIndex]
+		argIndex++
+
+		return arg, nil
+	}
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			if _, err := out.Write([]byte{format[i]}); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		i++
+
+		var spec conversion
+		start := i
+
+		for i < len(format) && strings.IndexByte(printfFlags, format[i]) >= 0 {
+			i++
+		}
+		spec.flags = format[start:i]
+
+		start = i
+		for i < len(format) && (format[i] == '*' || isDigit(format[i])) {
+			i++
+		}
+		spec.width = format[start:i]
+
+		if i < len(format) && format[i] == '.' {
+			start = i
+			i++
+			for i < len(format) && (format[i] == '*' || isDigit(format[i])) {
+				i++
+			}
+			spec.precision = format[start:i]
+		}
+
+		for i < len(format) && strings.IndexByte(printfLengthModifiers, format[i]) >= 0 {
+			i++
+		}
+
+		if i >= len(format) {
+			return fmt.Errorf("incomplete conversion at the end of format %q", format)
+		}
+
+		spec.verb = format[i]
+
+		if spec.verb == '%' {
+			if _, err := io.WriteString(out, "%"); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		// a width or precision given as * is taken from the arguments
+		for _, field := range []*string{&spec.width, &spec.precision} {
+			if !strings.Contains(*field, "*") {
+				continue
+			}
+
+			arg, err := nextArg(spec.verb)
+
+			if err != nil {
+				return err
+			}
+
+			size, ok := arg.(int32)
+
+			if !ok {
+				return fmt.Errorf("%%%c expects int as width or precision, but got %T", spec.verb, arg)
+			}
+
+			*field = strings.Replace(*field, "*", fmt.Sprint(size), 1)
+		}
+
+		arg, err := nextArg(spec.verb)
+
+		if err != nil {
+			return err
+		}
+
+		formatted, err := spec.format(arg)
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(out, formatted); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (spec *conversion) format(arg interface{}) (string, error) {
+	mismatch := func(expected string) (string, error) {
+		return "", fmt.Errorf("%%%c expects %v, but got %v", spec.verb, expected, typeName(arg))
+	}
+
+	switch spec.verb {
+	case 'd', 'i', 'u', 'o', 'x', 'X':
+		var intValue int32
+
+		switch value := arg.(type) {
+		case int32:
+			intValue = value
+		case bool:
+			if value {
+				intValue = 1
+			}
+		default:
+			return mismatch("int")
+		}
+
+		switch spec.verb {
+		case 'd', 'i':
+			return fmt.Sprintf(spec.goFormat('d'), intValue), nil
+		case 'u':
+			return fmt.Sprintf(spec.goFormat('d'), uint32(intValue)), nil
+		default:
+			return fmt.Sprintf(spec.goFormat(spec.verb), uint32(intValue)), nil
+		}
+	case 'c':
+		switch value := arg.(type) {
+		case int32:
+			return fmt.Sprintf(spec.goFormat('c'), rune(byte(value))), nil
+		case string:
+			return fmt.Sprintf(spec.goFormat('s'), value), nil
+		default:
+			return mismatch("char")
+		}
+	case 's':
+		value, ok := arg.(string)
+
+		if !ok {
+			return mismatch("string")
+		}
+
+		return fmt.Sprintf(spec.goFormat('s'), value), nil
+	case 'f', 'F', 'e', 'E', 'g', 'G':
+		value, ok := arg.(float64)
+
+		if !ok {
+			return mismatch("float")
+		}
+
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return spec.formatNonFinite(value), nil
+		}
+
+		if spec.precision == "" {
+			spec.precision = ".6"
+		}
+
+		return fmt.Sprintf(spec.goFormat(spec.verb), value), nil
+	}
+
+	return "", fmt.Errorf("unsupported conversion %%%c", spec.verb)
+}
+
+// formatNonFinite prints infinities and NaN the way C does
+func (spec *conversion) formatNonFinite(value float64) string {
+	var text string
+
+	switch {
+	case math.IsNaN(value):
+		text = "nan"
+	case value < 0:
+		text = "-inf"
+	case strings.Contains(spec.flags, "+"):
+		text = "+inf"
+	case strings.Contains(spec.flags, " "):
+		text = " inf"
+	default:
+		text = "inf"
+	}
+
+	if spec.verb >= 'A' && spec.verb <= 'Z' {
+		text = strings.ToUpper(text)
+	}
+
+	if strings.Contains(spec.flags, "-") {
+		return fmt.Sprintf("%-"+spec.width+"s", text)
+	}
+
+	return fmt.Sprintf("%"+spec.width+"s", text)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case int32:
+		return "int"
+	case float64:
+		return "float"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
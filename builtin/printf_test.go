@@ -0,0 +1,57 @@
+package builtin
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrintf(t *testing.T) {
+	tests := []struct {
+		format   string
+		args     []interface{}
+		expected string
+	}{
+		{"%d|%5d|%-5d|%05d", []interface{}{int32(1), int32(-2), int32(3), int32(4)}, "1|   -2|3    |00004"},
+		{"%x %X %o %u", []interface{}{int32(255), int32(255), int32(8), int32(-1)}, "ff FF 10 4294967295"},
+		{"%d %d", []interface{}{true, false}, "1 0"},
+		{"%f %.2f %e %g %g", []interface{}{1.5, 1.005, 1234.5, 0.0001, 123456789.0}, "1.500000 1.00 1.234500e+03 0.0001 1.23457e+08"},
+		{"%*d|%.*f", []interface{}{int32(4), int32(7), int32(1), 2.25}, "   7|2.2"},
+		{"%s %c %c %%", []interface{}{"abc", "d", int32('e')}, "abc d e %"},
+		{"%ld %lf", []interface{}{int32(1), 0.5}, "1 0.500000"},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+
+		if err := Printf(&out, test.format, test.args); err != nil {
+			t.Errorf("Format %q: unexpected error %v", test.format, err)
+			continue
+		}
+
+		if out.String() != test.expected {
+			t.Errorf("Format %q: expecting %q, got %q", test.format, test.expected, out.String())
+		}
+	}
+}
+
+func TestPrintfErrors(t *testing.T) {
+	tests := []struct {
+		format string
+		args   []interface{}
+	}{
+		{"%d %d", []interface{}{int32(1)}},
+		{"%d", []interface{}{1.5}},
+		{"%f", []interface{}{int32(1)}},
+		{"%s", []interface{}{true}},
+		{"%p", []interface{}{"abc"}},
+		{"100%", []interface{}{}},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+
+		if err := Printf(&out, test.format, test.args); err == nil {
+			t.Errorf("Format %q with %v: expecting error", test.format, test.args)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package bytecode
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/host"
+	"github.com/carlcui/expressive/internal/testutil"
+	"github.com/carlcui/expressive/logger"
+	"github.com/carlcui/expressive/typing"
+)
+
+// compile compiles a program, failing the test if it does not fit in bytecode
+func compile(root ast.Node, t *testing.T) *Program {
+	var buffer logger.Buffer
+
+	program := Compile(root, &buffer)
+
+	if program == nil {
+		t.Fatalf("Error(s) encountered: %v", buffer.Messages())
+	}
+
+	return program
+}
+
+func compileFile(dirName string, fileName string, t *testing.T) *Program {
+	return compile(testutil.AnalyzeFile(dirName, fileName, t), t)
+}
+
+// roundTrip serializes and deserializes a program, as build --target=bytecode then run would
+func roundTrip(program *Program, t *testing.T) *Program {
+	var file bytes.Buffer
+
+	if err := Write(&file, program); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := Read(&file)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return read
+}
+
+func run(program *Program) (string, *logger.Buffer) {
+	var out bytes.Buffer
+	var buffer logger.Buffer
+
+	Run(program, &out, &buffer)
+
+	return out.String(), &buffer
+}
+
+// testOutputs runs every program of dirName having a .txt file holding its expected output,
+// once written and read back as a .expc file
+func testOutputs(dirName string, t *testing.T) {
+	testutil.Outputs(dirName, func(root ast.Node) (string, *logger.Buffer) {
+		return run(roundTrip(compile(root, t), t))
+	}, t)
+}
+
+func TestE2ePrograms(t *testing.T) {
+	testOutputs("../e2e", t)
+}
+
+func TestPrograms(t *testing.T) {
+	testOutputs("../interp/testFiles", t)
+}
+
+func TestRuntimeError(t *testing.T) {
+	out, buffer := run(roundTrip(compileFile("../interp/testFiles", "division_by_zero.exp", t), t))
+
+	if out != "before\n" {
+		t.Errorf("Expecting output before the runtime error to be printed, got %q", out)
+	}
+
+	messages := buffer.Messages()
+
+	if len(messages) != 1 || !strings.Contains(messages[0], "row 3, column 16: runtime error: integer division by zero") {
+		t.Errorf("Expecting division by zero at row 3, column 16, got %v", messages)
+	}
+}
+
+func TestMalformedCode(t *testing.T) {
+	program := &Program{Code: []byte{byte(ADD_INT), byte(HALT)}}
+
+	_, buffer := run(program)
+
+	messages := buffer.Messages()
+
+	if len(messages) != 1 || !strings.Contains(messages[0], "bytecode offset 0: runtime error: stack underflow") {
+		t.Errorf("Expecting stack underflow at offset 0, got %v", messages)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	var file bytes.Buffer
+
+	if err := Write(&file, compileFile("../e2e", "switch_1.exp", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := file.Bytes()
+
+	otherVersion := append([]byte(nil), valid...)
+	otherVersion[5] = Version + 1
+
+	// corrupt writes a program of switch_1.exp changed by change
+	corrupt := func(change func(program *Program)) []byte {
+		program := compileFile("../e2e", "switch_1.exp", t)
+		change(program)
+
+		var file bytes.Buffer
+
+		if err := Write(&file, program); err != nil {
+			t.Fatal(err)
+		}
+
+		return file.Bytes()
+	}
+
+	manySlots := corrupt(func(program *Program) { program.Slots = 1 << 28 })
+
+	manyConstants := corrupt(func(program *Program) {
+		for i := len(program.Constants); i <= maxOperands; i++ {
+			program.Constants = append(program.Constants, int32(i))
+		}
+	})
+
+	locationOutOfCode := corrupt(func(program *Program) {
+		program.Locations = append(program.Locations, Location{Offset: uint32(len(program.Code))})
+	})
+
+	locationsOutOfOrder := corrupt(func(program *Program) {
+		program.Locations = append(program.Locations, Location{Offset: 0})
+	})
+
+	cases := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"magic", []byte("ELF\x00\x00\x01"), "not an expressive bytecode file"},
+		{"version", otherVersion, "unsupported bytecode version"},
+		{"truncated", valid[:len(valid)-3], "corrupt bytecode file: unexpected EOF"},
+		{"slots", manySlots, "corrupt bytecode file: 268435456 slots, expecting at most 65536"},
+		{"constants", manyConstants, "corrupt bytecode file: 65537 constants, expecting at most 65536"},
+		{"location out of code", locationOutOfCode, "out of order or out of code"},
+		{"locations out of order", locationsOutOfOrder, "corrupt bytecode file: location offset 0 out of order or out of code"},
+	}
+
+	for _, c := range cases {
+		_, err := Read(bytes.NewReader(c.data))
+
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("Case %v: expecting error %q, got %v", c.name, c.expected, err)
+		}
+	}
+}
+
+func TestDisassemble(t *testing.T) {
+	var out bytes.Buffer
+
+	if err := Disassemble(&out, compileFile("../interp/testFiles", "division_by_zero.exp", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	listing := out.String()
+
+	for _, expected := range []string{"constants:", "string \"before\\n\"", "DIV_INT", "row 3, column 16", "HALT"} {
+		if !strings.Contains(listing, expected) {
+			t.Errorf("Expecting the listing to contain %q, got\n%v", expected, listing)
+		}
+	}
+}
+
+func TestHostFunctions(t *testing.T) {
+	square := &host.Function{Name: "square", Params: []typing.Typing{typing.INT}, Result: typing.INT, Call: func(args []host.Value) (host.Value, error) {
+		return args[0].(int32) * args[0].(int32), nil
+	}}
+
+	root := testutil.AnalyzeSource("let x = square(3);\nsquare(x);\nprint \"%d\\n\", square(x) + 1;\n", []*host.Function{square}, t)
+
+	program := roundTrip(compile(root, t), t)
+
+	var listing bytes.Buffer
+
+	if err := Disassemble(&listing, program); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"string \"square\"", "CALL", "POP"} {
+		if !strings.Contains(listing.String(), expected) {
+			t.Errorf("Expecting the listing to contain %q, got\n%v", expected, listing.String())
+		}
+	}
+
+	var out bytes.Buffer
+	var buffer logger.Buffer
+	var vm VM
+	vm.Init(program, &out)
+	vm.Register(square)
+	vm.Run(&buffer)
+
+	if buffer.ErrorsCount() > 0 || out.String() != "82\n" {
+		t.Errorf("Expecting 82 to be printed, got %q and %v", out.String(), buffer.Messages())
+	}
+
+	if _, buffer := run(program); buffer.ErrorsCount() != 1 || !strings.Contains(buffer.Messages()[0], "runtime error: host function square is not registered") {
+		t.Errorf("Expecting the unregistered function to be reported, got %v", buffer.Messages())
+	}
+}
+
+func TestExternFunctions(t *testing.T) {
+	root := testutil.AnalyzeSource("extern func puts(s: string) -> int;\nprint \"before\\n\";\nlet a = puts(\"a\");\nprint \"after\\n\";\n", nil, t)
+
+	out, buffer := run(roundTrip(compile(root, t), t))
+
+	if out != "before\n" {
+		t.Errorf("Expecting the output before the call, got %q", out)
+	}
+
+	if messages := buffer.Messages(); len(messages) != 1 || !strings.HasSuffix(messages[0], "runtime error: extern function puts cannot be called by the vm") {
+		t.Errorf("Expecting the call of the extern function to be reported, got %v", messages)
+	}
+}
+
+func TestTooManyOperands(t *testing.T) {
+	var constants, variables strings.Builder
+
+	for i := 0; i <= maxOperands; i++ {
+		fmt.Fprintf(&constants, "print \"%%d\", %d;\n", i)
+		fmt.Fprintf(&variables, "let v%d = 0;\n", i)
+	}
+
+	cases := []struct {
+		src      string
+		location string
+		expected string
+	}{
+		{constants.String(), "65535;", "too many constants for the bytecode target"},
+		{variables.String(), "let v65536", "too many variables for the bytecode target"},
+	}
+
+	for _, c := range cases {
+		var buffer logger.Buffer
+
+		program := Compile(testutil.AnalyzeSource(c.src, nil, t), &buffer)
+
+		// the print format is the first constant, so the last int is one too many
+		expected := fmt.Sprintf("at %d: %v", strings.Index(c.src, c.location), c.expected)
+
+		if program != nil || fmt.Sprint(buffer.Messages()) != fmt.Sprint([]string{expected}) {
+			t.Errorf("Expecting %q, got %v", expected, buffer.Messages())
+		}
+	}
+}
@@ -0,0 +1,485 @@
+package bytecode
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/signature"
+	"github.com/carlcui/expressive/symbolTable"
+	"github.com/carlcui/expressive/typing"
+)
+
+// Compiler translates an analysed ast into a Program
+type Compiler struct {
+	program   *Program
+	constants map[interface{}]int
+	slots     map[*symbolTable.Binding]int
+	externs   map[string]bool // the functions declared extern, which only compiled code can call
+	breaks    [][]int         // offsets of the jumps of breaks, one list per enclosing breakable statement
+	node      ast.Node        // the innermost statement or expression being compiled, locating compile errors
+}
+
+// compileError stops the compilation of a program that bytecode cannot hold. It is raised as a
+// panic and recovered by Compile.
+type compileError struct {
+	location string
+	message  string
+}
+
+// operandNames name what the 16 bit operands of instructions count or number
+var operandNames = map[Opcode]string{
+	CONST: "constants",
+	LOAD:  "variables",
+	STORE: "variables",
+	PRINT: "arguments",
+	CALL:  "arguments",
+}
+
+func (compiler *Compiler) Init() {
+	compiler.program = &Program{}
+	compiler.constants = make(map[interface{}]int)
+	compiler.slots = make(map[*symbolTable.Binding]int)
+	compiler.externs = make(map[string]bool)
+}
+
+// floatKey tells apart floats that compare equal, e.g. 0.0 and -0.0, as constant pool keys
+type floatKey uint64
+
+// opcodes of binary operators on ints and floats. Equality is handled for every type.
+var intOpcodes = map[signature.Operator]Opcode{
+	signature.ADD:              ADD_INT,
+	signature.SUBTRACT:         SUB_INT,
+	signature.MULTIPLY:         MUL_INT,
+	signature.DIVIDE:           DIV_INT,
+	signature.MODULO:           MOD_INT,
+	signature.EXPONENTIATE:     POW_INT,
+	signature.LESS:             LT_INT,
+	signature.LESS_OR_EQUAL:    LE_INT,
+	signature.GREATER:          GT_INT,
+	signature.GREATER_OR_EQUAL: GE_INT,
+	signature.NEGATE:           NEG_INT,
+}
+
+var floatOpcodes = map[signature.Operator]Opcode{
+	signature.ADD:              ADD_FLOAT,
+	signature.SUBTRACT:         SUB_FLOAT,
+	signature.MULTIPLY:         MUL_FLOAT,
+	signature.DIVIDE:           DIV_FLOAT,
+	signature.EXPONENTIATE:     POW_FLOAT,
+	signature.LESS:             LT_FLOAT,
+	signature.LESS_OR_EQUAL:    LE_FLOAT,
+	signature.GREATER:          GT_FLOAT,
+	signature.GREATER_OR_EQUAL: GE_FLOAT,
+	signature.NEGATE:           NEG_FLOAT,
+}
+
+func (compiler *Compiler) compileStmts(stmts []ast.Node) {
+	for _, stmt := range stmts {
+		compiler.compileStmt(stmt)
+	}
+}
+
+func (compiler *Compiler) compileStmt(node ast.Node) {
+	defer compiler.enter(node)()
+
+	switch stmt := node.(type) {
+	case nil:
+	case *ast.ProgramNode:
+		compiler.compileStmts(stmt.Chilren)
+		compiler.emit(HALT)
+	case *ast.BlockNode:
+		compiler.compileStmts(stmt.Stmts)
+	case *ast.VariableDeclarationNode:
+		identifier := stmt.Identifier.(*ast.IdentifierNode)
+
+		if stmt.Expr == nil {
+			compiler.emitConstant(defaultValue(identifier))
+		} else {
+			compiler.compileExpr(stmt.Expr)
+		}
+
+		compiler.emitOperand(STORE, compiler.declareSlot(identifier))
+	case *ast.AssignmentNode:
+		identifier := stmt.LHS.(*ast.IdentifierNode)
+
+		if stmt.Operator == signature.VOID_OPERATOR {
+			compiler.compileExpr(stmt.RHS)
+		} else {
+			compiler.compileExpr(identifier)
+			compiler.compileExpr(stmt.RHS)
+			compiler.compileOperator(stmt, stmt.Operator, identifier.GetTyping())
+		}
+
+		compiler.emitOperand(STORE, compiler.slot(identifier))
+	case *ast.IncDecNode:
+		identifier := stmt.LHS.(*ast.IdentifierNode)
+
+		compiler.compileExpr(identifier)
+		compiler.emitConstant(int32(1))
+
+		if stmt.IsIncrement {
+			compiler.emit(ADD_INT)
+		} else {
+			compiler.emit(SUB_INT)
+		}
+
+		compiler.emitOperand(STORE, compiler.slot(identifier))
+	case *ast.PrintNode:
+		compiler.compileExpr(stmt.StringExpr)
+
+		for _, arg := range stmt.Args {
+			compiler.compileExpr(arg)
+		}
+
+		compiler.locate(stmt)
+		compiler.emitOperand(PRINT, len(stmt.Args))
+	case *ast.IfStmtNode:
+		endJumps := make([]int, 0)
+
+		for i, conditionExpr := range stmt.ConditionExprs {
+			compiler.compileExpr(conditionExpr)
+			nextJump := compiler.emitJump(JUMP_IF_FALSE)
+
+			compiler.compileStmt(stmt.ConditionBlocks[i])
+			endJumps = append(endJumps, compiler.emitJump(JUMP))
+
+			compiler.patchJump(nextJump)
+		}
+
+		compiler.compileStmt(stmt.ElseBlock)
+
+		for _, endJump := range endJumps {
+			compiler.patchJump(endJump)
+		}
+	case *ast.WhileStmtNode:
+		start := len(compiler.program.Code)
+
+		compiler.compileExpr(stmt.ConditionExpr)
+		endJump := compiler.emitJump(JUMP_IF_FALSE)
+
+		compiler.compileBreakable(func() {
+			compiler.compileStmt(stmt.Block)
+			compiler.emitOperand(JUMP, start)
+			compiler.patchJump(endJump)
+		})
+	case *ast.ForStmtNode:
+		compiler.compileStmt(stmt.InitializationStmt)
+
+		start := len(compiler.program.Code)
+		endJump := -1
+
+		if stmt.ConditionExpr != nil {
+			compiler.compileExpr(stmt.ConditionExpr)
+			endJump = compiler.emitJump(JUMP_IF_FALSE)
+		}
+
+		compiler.compileBreakable(func() {
+			compiler.compileStmt(stmt.Block)
+			compiler.compileStmt(stmt.IterationStmt)
+			compiler.emitOperand(JUMP, start)
+
+			if endJump >= 0 {
+				compiler.patchJump(endJump)
+			}
+		})
+	case *ast.SwitchStmtNode:
+		compiler.compileSwitchStmt(stmt)
+	case *ast.BreakNode:
+		breaks := &compiler.breaks[len(compiler.breaks)-1]
+		*breaks = append(*breaks, compiler.emitJump(JUMP))
+	case *ast.CallNode:
+		compiler.compileExpr(stmt)
+
+		if stmt.GetTyping() != typing.VOID {
+			compiler.emit(POP)
+		}
+	case *ast.ExternNode:
+		compiler.externs[stmt.Name()] = true
+	default:
+		panic(fmt.Sprintf("%v: unexpected statement %T", node.GetLocation(), node))
+	}
+}
+
+// compileSwitchStmt compares the test value with each case in order, jumping to the block of
+// the first match. Blocks are laid out in order, so a block without break falls through.
+func (compiler *Compiler) compileSwitchStmt(node *ast.SwitchStmtNode) {
+	testSlot := compiler.newSlot()
+
+	compiler.compileExpr(node.TestExpr)
+	compiler.emitOperand(STORE, testSlot)
+
+	caseJumps := make([]int, len(node.CaseExprs))
+
+	for i, caseExpr := range node.CaseExprs {
+		compiler.emitOperand(LOAD, testSlot)
+		compiler.compileExpr(caseExpr)
+		compiler.emit(EQ)
+		caseJumps[i] = compiler.emitJump(JUMP_IF_TRUE)
+	}
+
+	defaultJump := compiler.emitJump(JUMP)
+
+	compiler.compileBreakable(func() {
+		for i, caseBlock := range node.CaseBlocks {
+			compiler.patchJump(caseJumps[i])
+			compiler.compileStmt(caseBlock)
+		}
+
+		compiler.patchJump(defaultJump)
+		compiler.compileStmt(node.DefaultBlock)
+	})
+}
+
+// compileBreakable compiles the body of a loop or switch, making its breaks jump to the end
+func (compiler *Compiler) compileBreakable(compileBody func()) {
+	compiler.breaks = append(compiler.breaks, make([]int, 0))
+
+	compileBody()
+
+	breaks := compiler.breaks[len(compiler.breaks)-1]
+	compiler.breaks = compiler.breaks[:len(compiler.breaks)-1]
+
+	for _, breakJump := range breaks {
+		compiler.patchJump(breakJump)
+	}
+}
+
+func (compiler *Compiler) compileExpr(node ast.Node) {
+	defer compiler.enter(node)()
+
+	switch expr := node.(type) {
+	case *ast.IntegerNode:
+		compiler.emitConstant(int32(expr.Val))
+	case *ast.FloatNode:
+		compiler.emitConstant(float64(expr.Val))
+	case *ast.BooleanNode:
+		compiler.emitConstant(expr.Val)
+	case *ast.CharacterNode:
+		compiler.emitConstant(string(expr.Val))
+	case *ast.StringNode:
+		stringValue := expr.StringValue()
+		compiler.emitConstant(stringValue[:len(stringValue)-1]) // without terminating character
+	case *ast.IdentifierNode:
+		compiler.emitOperand(LOAD, compiler.slot(expr))
+	case *ast.UnaryOperatorNode:
+		compiler.compileExpr(expr.Expr)
+		compiler.compileOperator(expr, expr.Operator, expr.Expr.GetTyping())
+	case *ast.BinaryOperatorNode:
+		switch expr.Operator {
+		case signature.LOGIC_AND:
+			compiler.compileShortCircuit(expr, JUMP_IF_FALSE, false)
+		case signature.LOGIC_OR:
+			compiler.compileShortCircuit(expr, JUMP_IF_TRUE, true)
+		default:
+			compiler.compileExpr(expr.Lhs)
+			compiler.compileExpr(expr.Rhs)
+			compiler.compileOperator(expr, expr.Operator, expr.Lhs.GetTyping())
+		}
+	case *ast.TernaryOperatorNode:
+		compiler.compileExpr(expr.Expr1)
+		elseJump := compiler.emitJump(JUMP_IF_FALSE)
+
+		compiler.compileExpr(expr.Expr2)
+		endJump := compiler.emitJump(JUMP)
+
+		compiler.patchJump(elseJump)
+		compiler.compileExpr(expr.Expr3)
+
+		compiler.patchJump(endJump)
+	case *ast.CallNode:
+		for _, arg := range expr.Args {
+			compiler.compileExpr(arg)
+		}
+
+		if compiler.externs[expr.Name()] {
+			compiler.emitConstant("extern function " + expr.Name() + " cannot be called by the vm")
+			compiler.locate(expr)
+			compiler.emit(FAIL)
+			break
+		}
+
+		compiler.locate(expr)
+		compiler.emitOperand(CALL, compiler.constant(expr.Name()))
+	default:
+		panic(fmt.Sprintf("%v: unexpected expression %T", node.GetLocation(), node))
+	}
+}
+
+// compileShortCircuit skips the right-hand side when the left-hand side alone decides the result
+func (compiler *Compiler) compileShortCircuit(node *ast.BinaryOperatorNode, skip Opcode, skippedResult bool) {
+	compiler.compileExpr(node.Lhs)
+	skipJump := compiler.emitJump(skip)
+
+	compiler.compileExpr(node.Rhs)
+	endJump := compiler.emitJump(JUMP)
+
+	compiler.patchJump(skipJump)
+	compiler.emitConstant(skippedResult)
+
+	compiler.patchJump(endJump)
+}
+
+// compileOperator emits the instruction applying operator on operands of the given typing,
+// already on the stack
+func (compiler *Compiler) compileOperator(node ast.Node, operator signature.Operator, operandTyping typing.Typing) {
+	switch operator {
+	case signature.LOGIC_NOT:
+		compiler.emit(NOT)
+		return
+	case signature.SHALLOW_EQUAL, signature.DEEP_EQUAL:
+		compiler.emit(EQ)
+		return
+	case signature.SHALLOW_NOT_EQUAL, signature.DEEP_NOT_EQUAL:
+		compiler.emit(NE)
+		return
+	}
+
+	var opcode Opcode
+	var ok bool
+
+	switch operandTyping {
+	case typing.INT:
+		opcode, ok = intOpcodes[operator]
+	case typing.FLOAT:
+		opcode, ok = floatOpcodes[operator]
+	case typing.STRING:
+		opcode, ok = CONCAT, operator == signature.ADD
+	}
+
+	if !ok {
+		panic(fmt.Sprintf("%v: cannot compile %v on %v", node.GetLocation(), operator, operandTyping))
+	}
+
+	if opcode == DIV_INT || opcode == MOD_INT {
+		compiler.locate(node)
+	}
+
+	compiler.emit(opcode)
+}
+
+// defaultValue is the value of a variable declared without initializer
+func defaultValue(identifier *ast.IdentifierNode) interface{} {
+	switch identifier.GetTyping() {
+	case typing.INT:
+		return int32(0)
+	case typing.FLOAT:
+		return float64(0)
+	case typing.BOOL:
+		return false
+	case typing.CHAR, typing.STRING:
+		return ""
+	default:
+		panic(fmt.Sprintf("%v: no default value for type %v", identifier.GetLocation(), identifier.GetTyping()))
+	}
+}
+
+func (compiler *Compiler) declareSlot(identifier *ast.IdentifierNode) int {
+	binding := identifier.GetBinding()
+
+	if slot, ok := compiler.slots[binding]; ok {
+		return slot
+	}
+
+	slot := compiler.newSlot()
+	compiler.slots[binding] = slot
+
+	return slot
+}
+
+func (compiler *Compiler) slot(identifier *ast.IdentifierNode) int {
+	slot, ok := compiler.slots[identifier.GetBinding()]
+
+	if !ok {
+		panic(fmt.Sprintf("%v: variable %v has no slot", identifier.GetLocation(), identifier.Tok.Raw))
+	}
+
+	return slot
+}
+
+func (compiler *Compiler) newSlot() int {
+	slot := compiler.program.Slots
+	compiler.program.Slots++
+
+	return slot
+}
+
+// enter makes node the one compile errors are located at, until the returned function restores
+// the enclosing one
+func (compiler *Compiler) enter(node ast.Node) func() {
+	enclosing := compiler.node
+
+	if node != nil {
+		compiler.node = node
+	}
+
+	return func() {
+		compiler.node = enclosing
+	}
+}
+
+// locate records the source location of the next instruction, for run time errors
+func (compiler *Compiler) locate(node ast.Node) {
+	compiler.program.Locations = append(compiler.program.Locations, Location{
+		Offset:   uint32(len(compiler.program.Code)),
+		Location: node.GetLocation(),
+	})
+}
+
+func (compiler *Compiler) emit(opcode Opcode) {
+	compiler.program.Code = append(compiler.program.Code, byte(opcode))
+}
+
+func (compiler *Compiler) emitOperand(opcode Opcode, operand int) {
+	compiler.emit(opcode)
+
+	switch opcode.OperandSize() {
+	case 2:
+		if operand > math.MaxUint16 {
+			panic(compileError{compiler.node.GetLocation(), "too many " + operandNames[opcode] + " for the bytecode target"})
+		}
+
+		compiler.program.Code = append(compiler.program.Code, 0, 0)
+		binary.BigEndian.PutUint16(compiler.program.Code[len(compiler.program.Code)-2:], uint16(operand))
+	case 4:
+		compiler.program.Code = append(compiler.program.Code, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(compiler.program.Code[len(compiler.program.Code)-4:], uint32(operand))
+	}
+}
+
+func (compiler *Compiler) emitConstant(value interface{}) {
+	compiler.emitOperand(CONST, compiler.constant(value))
+}
+
+// constant returns the index of value in the constant pool, adding it if needed
+func (compiler *Compiler) constant(value interface{}) int {
+	key := value
+
+	if floatValue, ok := value.(float64); ok {
+		key = floatKey(math.Float64bits(floatValue))
+	}
+
+	index, ok := compiler.constants[key]
+
+	if !ok {
+		index = len(compiler.program.Constants)
+		compiler.program.Constants = append(compiler.program.Constants, value)
+		compiler.constants[key] = index
+	}
+
+	return index
+}
+
+// emitJump emits a jump to be patched later, returning its offset
+func (compiler *Compiler) emitJump(opcode Opcode) int {
+	offset := len(compiler.program.Code)
+	compiler.emitOperand(opcode, 0)
+
+	return offset
+}
+
+// patchJump makes the jump at offset go to the next instruction
+func (compiler *Compiler) patchJump(offset int) {
+	binary.BigEndian.PutUint32(compiler.program.Code[offset+1:], uint32(len(compiler.program.Code)))
+}
@@ -0,0 +1,84 @@
+package bytecode
+
+import (
+	"fmt"
+	"io"
+)
+
+// Disassemble writes a human readable listing of a program: its constant pool, then one
+// instruction per line with its offset, operand and, where known, its source location.
+func Disassemble(out io.Writer, program *Program) error {
+	w := &listingWriter{out: out}
+
+	w.printf("; expressive bytecode version %d, %d slot(s)\n", Version, program.Slots)
+	w.printf("constants:\n")
+
+	for i, constant := range program.Constants {
+		w.printf("  %4d  %v\n", i, describeConstant(constant))
+	}
+
+	w.printf("code:\n")
+
+	for offset := 0; offset < len(program.Code); {
+		opcode := Opcode(program.Code[offset])
+
+		if opcode >= OPCODE_COUNT || offset+opcode.Size() > len(program.Code) {
+			w.printf("  %04x  %v ; malformed\n", offset, opcode)
+			break
+		}
+
+		line := fmt.Sprintf("  %04x  %v", offset, opcode)
+		comment := program.LocationAt(offset)
+
+		switch opcode {
+		case CONST, CALL:
+			index := program.operand(offset)
+			line += fmt.Sprintf(" %d", index)
+
+			if index < len(program.Constants) {
+				comment = describeConstant(program.Constants[index])
+			}
+		case JUMP, JUMP_IF_FALSE, JUMP_IF_TRUE:
+			line += fmt.Sprintf(" %04x", program.operand(offset))
+		case LOAD, STORE, PRINT:
+			line += fmt.Sprintf(" %d", program.operand(offset))
+		}
+
+		if comment != "" {
+			line = fmt.Sprintf("%-30v ; %v", line, comment)
+		}
+
+		w.printf("%v\n", line)
+
+		offset += opcode.Size()
+	}
+
+	return w.err
+}
+
+func describeConstant(constant interface{}) string {
+	switch value := constant.(type) {
+	case int32:
+		return fmt.Sprintf("int %d", value)
+	case float64:
+		return fmt.Sprintf("float %v", value)
+	case bool:
+		return fmt.Sprintf("bool %v", value)
+	case string:
+		return fmt.Sprintf("string %q", value)
+	default:
+		return fmt.Sprintf("%T %v", constant, constant)
+	}
+}
+
+// listingWriter remembers the first error, so that writes can be checked once at the end
+type listingWriter struct {
+	out io.Writer
+	err error
+}
+
+func (w *listingWriter) printf(format string, args ...interface{}) {
+	if w.err == nil {
+		_, w.err = fmt.Fprintf(w.out, format, args...)
+	}
+}
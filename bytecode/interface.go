@@ -0,0 +1,41 @@
+package bytecode
+
+import (
+	"io"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/logger"
+)
+
+// Compile translates an analysed program into bytecode. It returns nil after reporting to logger
+// if the program has more variables or constants than instructions can number.
+func Compile(node ast.Node, logger logger.Logger) (program *Program) {
+	var compiler Compiler
+	compiler.Init()
+
+	defer func() {
+		if err := recover(); err != nil {
+			compileErr, ok := err.(compileError)
+
+			if !ok {
+				panic(err)
+			}
+
+			logger.Log(compileErr.location, compileErr.message)
+			program = nil
+		}
+	}()
+
+	compiler.compileStmt(node)
+
+	return compiler.program
+}
+
+// Run executes a program, writing what it prints to out. A runtime error stops the program and
+// is reported to logger.
+func Run(program *Program, out io.Writer, logger logger.Logger) {
+	var vm VM
+	vm.Init(program, out)
+
+	vm.Run(logger)
+}
@@ -0,0 +1,118 @@
+package bytecode
+
+import "fmt"
+
+// Opcode is the first byte of an instruction, followed by its operands in big endian
+type Opcode byte
+
+const (
+	HALT Opcode = iota
+
+	CONST // u16 constant index: push a constant
+	LOAD  // u16 slot: push a variable
+	STORE // u16 slot: pop into a variable
+
+	ADD_INT
+	SUB_INT
+	MUL_INT
+	DIV_INT
+	MOD_INT
+	POW_INT
+	LT_INT
+	LE_INT
+	GT_INT
+	GE_INT
+	NEG_INT
+
+	ADD_FLOAT
+	SUB_FLOAT
+	MUL_FLOAT
+	DIV_FLOAT
+	POW_FLOAT
+	LT_FLOAT
+	LE_FLOAT
+	GT_FLOAT
+	GE_FLOAT
+	NEG_FLOAT
+
+	CONCAT
+	EQ
+	NE
+	NOT
+
+	JUMP          // u32 address
+	JUMP_IF_FALSE // u32 address: pop a bool, jump if it is false
+	JUMP_IF_TRUE  // u32 address: pop a bool, jump if it is true
+
+	PRINT // u16 argument count: pop the arguments, then the format, and print them
+
+	CALL // u16 constant index of the name: pop the arguments, call the host function, push its result unless it returns nothing
+	POP  // discard the top of the stack
+	FAIL // pop a message and stop with a runtime error, e.g. at a call of an extern function
+
+	OPCODE_COUNT
+)
+
+var opcodeNames = [...]string{
+	HALT:          "HALT",
+	CONST:         "CONST",
+	LOAD:          "LOAD",
+	STORE:         "STORE",
+	ADD_INT:       "ADD_INT",
+	SUB_INT:       "SUB_INT",
+	MUL_INT:       "MUL_INT",
+	DIV_INT:       "DIV_INT",
+	MOD_INT:       "MOD_INT",
+	POW_INT:       "POW_INT",
+	LT_INT:        "LT_INT",
+	LE_INT:        "LE_INT",
+	GT_INT:        "GT_INT",
+	GE_INT:        "GE_INT",
+	NEG_INT:       "NEG_INT",
+	ADD_FLOAT:     "ADD_FLOAT",
+	SUB_FLOAT:     "SUB_FLOAT",
+	MUL_FLOAT:     "MUL_FLOAT",
+	DIV_FLOAT:     "DIV_FLOAT",
+	POW_FLOAT:     "POW_FLOAT",
+	LT_FLOAT:      "LT_FLOAT",
+	LE_FLOAT:      "LE_FLOAT",
+	GT_FLOAT:      "GT_FLOAT",
+	GE_FLOAT:      "GE_FLOAT",
+	NEG_FLOAT:     "NEG_FLOAT",
+	CONCAT:        "CONCAT",
+	EQ:            "EQ",
+	NE:            "NE",
+	NOT:           "NOT",
+	JUMP:          "JUMP",
+	JUMP_IF_FALSE: "JUMP_IF_FALSE",
+	JUMP_IF_TRUE:  "JUMP_IF_TRUE",
+	PRINT:         "PRINT",
+	CALL:          "CALL",
+	POP:           "POP",
+	FAIL:          "FAIL",
+}
+
+func (opcode Opcode) String() string {
+	if opcode < OPCODE_COUNT {
+		return opcodeNames[opcode]
+	}
+
+	return fmt.Sprintf("OPCODE(%d)", byte(opcode))
+}
+
+// OperandSize is the number of bytes following the opcode in an instruction
+func (opcode Opcode) OperandSize() int {
+	switch opcode {
+	case CONST, LOAD, STORE, PRINT, CALL:
+		return 2
+	case JUMP, JUMP_IF_FALSE, JUMP_IF_TRUE:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// Size is the number of bytes of an instruction
+func (opcode Opcode) Size() int {
+	return 1 + opcode.OperandSize()
+}
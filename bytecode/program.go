@@ -0,0 +1,52 @@
+package bytecode
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+// maxOperands is the number of slots or constants a program can have: instructions number them
+// with 16 bit operands
+const maxOperands = math.MaxUint16 + 1
+
+// Program is a compiled program, ready to be run by the vm or serialized
+type Program struct {
+	Constants []interface{} // int32, float64, bool or string
+	Slots     int           // number of variables
+	Code      []byte
+	Locations []Location // sorted by offset
+}
+
+// Location maps an instruction that can fail at run time to its source location
+type Location struct {
+	Offset   uint32
+	Location string
+}
+
+// LocationAt returns the source location of the instruction at offset, or "" if unknown
+func (program *Program) LocationAt(offset int) string {
+	i := sort.Search(len(program.Locations), func(i int) bool {
+		return program.Locations[i].Offset >= uint32(offset)
+	})
+
+	if i < len(program.Locations) && program.Locations[i].Offset == uint32(offset) {
+		return program.Locations[i].Location
+	}
+
+	return ""
+}
+
+func (program *Program) operand(offset int) int {
+	opcode := Opcode(program.Code[offset])
+	operand := program.Code[offset+1 : offset+opcode.Size()]
+
+	switch opcode.OperandSize() {
+	case 2:
+		return int(binary.BigEndian.Uint16(operand))
+	case 4:
+		return int(binary.BigEndian.Uint32(operand))
+	default:
+		return 0
+	}
+}
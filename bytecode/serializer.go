@@ -0,0 +1,229 @@
+package bytecode
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// FileExtension is the extension of serialized programs
+const FileExtension = ".expc"
+
+// Version is the version of the serialized format. It changes whenever the format or the
+// instruction set does, and programs of another version are refused.
+const Version = 4
+
+var magic = [4]byte{'E', 'X', 'P', 'C'}
+
+// tags of serialized constants
+const (
+	intTag byte = iota + 1
+	floatTag
+	boolTag
+	stringTag
+)
+
+// maxLength guards against allocating huge buffers when reading a corrupt file
+const maxLength = 1 << 30
+
+// Write serializes a program. All integers are big endian, strings are prefixed with their
+// length:
+//
+//	magic "EXPC", version u16
+//	constant count u32, then per constant a tag byte and its value
+//	slot count u32
+//	code length u32, code
+//	location count u32, then per location an offset u32 and a string
+func Write(out io.Writer, program *Program) error {
+	writer := bufio.NewWriter(out)
+	w := binaryWriter{writer: writer}
+
+	w.write(magic)
+	w.write(uint16(Version))
+
+	w.write(uint32(len(program.Constants)))
+
+	for _, constant := range program.Constants {
+		switch value := constant.(type) {
+		case int32:
+			w.write(intTag)
+			w.write(value)
+		case float64:
+			w.write(floatTag)
+			w.write(math.Float64bits(value))
+		case bool:
+			w.write(boolTag)
+			w.write(value)
+		case string:
+			w.write(stringTag)
+			w.writeString(value)
+		default:
+			return fmt.Errorf("cannot serialize constant %v of type %T", constant, constant)
+		}
+	}
+
+	w.write(uint32(program.Slots))
+
+	w.write(uint32(len(program.Code)))
+	w.write(program.Code)
+
+	w.write(uint32(len(program.Locations)))
+
+	for _, location := range program.Locations {
+		w.write(location.Offset)
+		w.writeString(location.Location)
+	}
+
+	if w.err != nil {
+		return w.err
+	}
+
+	return writer.Flush()
+}
+
+// Read deserializes a program written by Write
+func Read(in io.Reader) (*Program, error) {
+	r := binaryReader{reader: bufio.NewReader(in)}
+
+	var fileMagic [4]byte
+	var version uint16
+
+	r.read(&fileMagic)
+
+	if r.err == nil && fileMagic != magic {
+		return nil, errors.New("not an expressive bytecode file")
+	}
+
+	r.read(&version)
+
+	if r.err == nil && version != Version {
+		return nil, fmt.Errorf("unsupported bytecode version %d, expecting %d", version, Version)
+	}
+
+	program := &Program{}
+
+	constantCount := r.readCount("constants")
+
+	for i := 0; i < constantCount && r.err == nil; i++ {
+		var tag byte
+		r.read(&tag)
+
+		switch tag {
+		case intTag:
+			var value int32
+			r.read(&value)
+			program.Constants = append(program.Constants, value)
+		case floatTag:
+			var bits uint64
+			r.read(&bits)
+			program.Constants = append(program.Constants, math.Float64frombits(bits))
+		case boolTag:
+			var value bool
+			r.read(&value)
+			program.Constants = append(program.Constants, value)
+		case stringTag:
+			program.Constants = append(program.Constants, r.readString())
+		default:
+			if r.err == nil {
+				return nil, fmt.Errorf("unknown constant tag %d", tag)
+			}
+		}
+	}
+
+	program.Slots = r.readCount("slots")
+
+	program.Code = make([]byte, r.readLength())
+	r.read(program.Code)
+
+	locationCount := r.readLength()
+
+	for i := 0; i < locationCount && r.err == nil; i++ {
+		var location Location
+
+		r.read(&location.Offset)
+		location.Location = r.readString()
+
+		// LocationAt searches locations by offset, among the instructions of the code
+		if r.err == nil && (int(location.Offset) >= len(program.Code) || i > 0 && location.Offset < program.Locations[i-1].Offset) {
+			r.err = fmt.Errorf("location offset %d out of order or out of code", location.Offset)
+		}
+
+		program.Locations = append(program.Locations, location)
+	}
+
+	if r.err != nil {
+		if r.err == io.EOF {
+			r.err = io.ErrUnexpectedEOF
+		}
+
+		return nil, fmt.Errorf("corrupt bytecode file: %v", r.err)
+	}
+
+	return program, nil
+}
+
+// binaryWriter remembers the first error, so that writes can be checked once at the end
+type binaryWriter struct {
+	writer io.Writer
+	err    error
+}
+
+func (w *binaryWriter) write(data interface{}) {
+	if w.err == nil {
+		w.err = binary.Write(w.writer, binary.BigEndian, data)
+	}
+}
+
+func (w *binaryWriter) writeString(value string) {
+	w.write(uint32(len(value)))
+	w.write([]byte(value))
+}
+
+// binaryReader remembers the first error, so that reads can be checked once at the end
+type binaryReader struct {
+	reader io.Reader
+	err    error
+}
+
+func (r *binaryReader) read(data interface{}) {
+	if r.err == nil {
+		r.err = binary.Read(r.reader, binary.BigEndian, data)
+	}
+}
+
+func (r *binaryReader) readLength() int {
+	var length uint32
+	r.read(&length)
+
+	if r.err == nil && length > maxLength {
+		r.err = fmt.Errorf("length %d too large", length)
+	}
+
+	if r.err != nil {
+		return 0
+	}
+
+	return int(length)
+}
+
+// readCount reads the number of slots or constants, which operands must be able to number
+func (r *binaryReader) readCount(what string) int {
+	count := r.readLength()
+
+	if r.err == nil && count > maxOperands {
+		r.err = fmt.Errorf("%d %v, expecting at most %d", count, what, maxOperands)
+		return 0
+	}
+
+	return count
+}
+
+func (r *binaryReader) readString() string {
+	value := make([]byte, r.readLength())
+	r.read(value)
+
+	return string(value)
+}
@@ -0,0 +1,346 @@
+package bytecode
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/carlcui/expressive/builtin"
+	"github.com/carlcui/expressive/host"
+	"github.com/carlcui/expressive/logger"
+	"github.com/carlcui/expressive/typing"
+)
+
+// VM is a stack machine running a Program
+type VM struct {
+	program   *Program
+	out       *bufio.Writer
+	stack     []interface{}
+	slots     []interface{}
+	pc        int
+	functions map[string]*host.Function
+}
+
+// runtimeError aborts the program. It is raised as a panic and recovered by Run.
+type runtimeError struct {
+	offset  int
+	message string
+}
+
+func (vm *VM) Init(program *Program, out io.Writer) {
+	vm.program = program
+	vm.out = bufio.NewWriter(out)
+	vm.stack = make([]interface{}, 0, 16)
+	vm.slots = make([]interface{}, program.Slots)
+	vm.functions = make(map[string]*host.Function)
+}
+
+// Register makes the CALL instructions naming the functions of the host call them back
+func (vm *VM) Register(functions ...*host.Function) {
+	for _, function := range functions {
+		vm.functions[function.Name] = function
+	}
+}
+
+// Run executes the program until HALT. Runtime errors, including malformed bytecode, are
+// reported to logger.
+func (vm *VM) Run(logger logger.Logger) {
+	// flush what has been printed before reporting a runtime error
+	defer func() {
+		vm.out.Flush()
+
+		if err := recover(); err != nil {
+			runtimeErr, ok := err.(runtimeError)
+
+			if !ok {
+				panic(err)
+			}
+
+			location := vm.program.LocationAt(runtimeErr.offset)
+
+			if location == "" {
+				location = fmt.Sprintf("bytecode offset %d", runtimeErr.offset)
+			}
+
+			logger.Log(location, "runtime error: "+runtimeErr.message)
+		}
+	}()
+
+	vm.execute()
+}
+
+func (vm *VM) execute() {
+	code := vm.program.Code
+
+	for {
+		if vm.pc >= len(code) {
+			vm.raise(vm.pc, "program counter out of code")
+		}
+
+		offset := vm.pc
+		opcode := Opcode(code[offset])
+
+		if opcode >= OPCODE_COUNT || offset+opcode.Size() > len(code) {
+			vm.raise(offset, fmt.Sprintf("malformed instruction %v", opcode))
+		}
+
+		vm.pc += opcode.Size()
+
+		switch opcode {
+		case HALT:
+			return
+		case CONST:
+			index := vm.operand16(offset)
+
+			if index >= len(vm.program.Constants) {
+				vm.raise(offset, fmt.Sprintf("constant %d out of range", index))
+			}
+
+			vm.push(vm.program.Constants[index])
+		case LOAD:
+			vm.push(vm.slots[vm.slot(offset)])
+		case STORE:
+			vm.slots[vm.slot(offset)] = vm.pop(offset)
+
+		case ADD_INT, SUB_INT, MUL_INT, DIV_INT, MOD_INT, POW_INT, LT_INT, LE_INT, GT_INT, GE_INT:
+			rhs := vm.popInt(offset)
+			lhs := vm.popInt(offset)
+			vm.push(vm.operateInt(offset, opcode, lhs, rhs))
+		case ADD_FLOAT, SUB_FLOAT, MUL_FLOAT, DIV_FLOAT, POW_FLOAT, LT_FLOAT, LE_FLOAT, GT_FLOAT, GE_FLOAT:
+			rhs := vm.popFloat(offset)
+			lhs := vm.popFloat(offset)
+			vm.push(operateFloat(opcode, lhs, rhs))
+		case NEG_INT:
+			vm.push(-vm.popInt(offset))
+		case NEG_FLOAT:
+			vm.push(-vm.popFloat(offset))
+
+		case CONCAT:
+			rhs := vm.popString(offset)
+			lhs := vm.popString(offset)
+			vm.push(lhs + rhs)
+		case EQ:
+			rhs := vm.pop(offset)
+			vm.push(vm.pop(offset) == rhs)
+		case NE:
+			rhs := vm.pop(offset)
+			vm.push(vm.pop(offset) != rhs)
+		case NOT:
+			vm.push(!vm.popBool(offset))
+
+		case JUMP:
+			vm.pc = vm.operand32(offset)
+		case JUMP_IF_FALSE:
+			if !vm.popBool(offset) {
+				vm.pc = vm.operand32(offset)
+			}
+		case JUMP_IF_TRUE:
+			if vm.popBool(offset) {
+				vm.pc = vm.operand32(offset)
+			}
+
+		case PRINT:
+			argCount := vm.operand16(offset)
+
+			if argCount+1 > len(vm.stack) {
+				vm.raise(offset, "stack underflow")
+			}
+
+			args := make([]interface{}, argCount)
+			copy(args, vm.stack[len(vm.stack)-argCount:])
+			vm.stack = vm.stack[:len(vm.stack)-argCount]
+
+			format := vm.popString(offset)
+
+			if err := builtin.Printf(vm.out, format, args); err != nil {
+				vm.raise(offset, err.Error())
+			}
+
+		case CALL:
+			vm.call(offset)
+		case POP:
+			vm.pop(offset)
+		case FAIL:
+			vm.raise(offset, vm.popString(offset))
+		}
+	}
+}
+
+// call calls back the host function named by the CALL instruction at offset, with what has been
+// printed so far written out
+func (vm *VM) call(offset int) {
+	index := vm.operand16(offset)
+
+	if index >= len(vm.program.Constants) {
+		vm.raise(offset, fmt.Sprintf("constant %d out of range", index))
+	}
+
+	name, ok := vm.program.Constants[index].(string)
+
+	if !ok {
+		vm.raise(offset, "expecting the name of a function")
+	}
+
+	function, ok := vm.functions[name]
+
+	if !ok {
+		vm.raise(offset, "host function "+name+" is not registered")
+	}
+
+	argCount := len(function.Params)
+
+	if argCount > len(vm.stack) {
+		vm.raise(offset, "stack underflow")
+	}
+
+	args := make([]interface{}, argCount)
+	copy(args, vm.stack[len(vm.stack)-argCount:])
+	vm.stack = vm.stack[:len(vm.stack)-argCount]
+
+	vm.out.Flush()
+
+	result, err := function.Invoke(args)
+
+	if err != nil {
+		vm.raise(offset, err.Error())
+	}
+
+	if function.Result != typing.VOID {
+		vm.push(result)
+	}
+}
+
+func (vm *VM) operateInt(offset int, opcode Opcode, lhs int32, rhs int32) interface{} {
+	switch opcode {
+	case ADD_INT:
+		return lhs + rhs
+	case SUB_INT:
+		return lhs - rhs
+	case MUL_INT:
+		return lhs * rhs
+	case DIV_INT:
+		if rhs == 0 {
+			vm.raise(offset, "integer division by zero")
+		}
+
+		return lhs / rhs
+	case MOD_INT:
+		if rhs == 0 {
+			vm.raise(offset, "integer division by zero")
+		}
+
+		return lhs % rhs
+	case POW_INT:
+		return builtin.PowInt(lhs, rhs)
+	case LT_INT:
+		return lhs < rhs
+	case LE_INT:
+		return lhs <= rhs
+	case GT_INT:
+		return lhs > rhs
+	default:
+		return lhs >= rhs
+	}
+}
+
+func operateFloat(opcode Opcode, lhs float64, rhs float64) interface{} {
+	switch opcode {
+	case ADD_FLOAT:
+		return lhs + rhs
+	case SUB_FLOAT:
+		return lhs - rhs
+	case MUL_FLOAT:
+		return lhs * rhs
+	case DIV_FLOAT:
+		return lhs / rhs
+	case POW_FLOAT:
+		return math.Pow(lhs, rhs)
+	case LT_FLOAT:
+		return lhs < rhs
+	case LE_FLOAT:
+		return lhs <= rhs
+	case GT_FLOAT:
+		return lhs > rhs
+	default:
+		return lhs >= rhs
+	}
+}
+
+func (vm *VM) operand16(offset int) int {
+	return int(binary.BigEndian.Uint16(vm.program.Code[offset+1:]))
+}
+
+func (vm *VM) operand32(offset int) int {
+	return int(binary.BigEndian.Uint32(vm.program.Code[offset+1:]))
+}
+
+func (vm *VM) slot(offset int) int {
+	slot := vm.operand16(offset)
+
+	if slot >= len(vm.slots) {
+		vm.raise(offset, fmt.Sprintf("slot %d out of range", slot))
+	}
+
+	return slot
+}
+
+func (vm *VM) push(value interface{}) {
+	vm.stack = append(vm.stack, value)
+}
+
+func (vm *VM) pop(offset int) interface{} {
+	if len(vm.stack) == 0 {
+		vm.raise(offset, "stack underflow")
+	}
+
+	value := vm.stack[len(vm.stack)-1]
+	vm.stack = vm.stack[:len(vm.stack)-1]
+
+	return value
+}
+
+func (vm *VM) popInt(offset int) int32 {
+	value, ok := vm.pop(offset).(int32)
+
+	if !ok {
+		vm.raise(offset, "expecting int on the stack")
+	}
+
+	return value
+}
+
+func (vm *VM) popFloat(offset int) float64 {
+	value, ok := vm.pop(offset).(float64)
+
+	if !ok {
+		vm.raise(offset, "expecting float on the stack")
+	}
+
+	return value
+}
+
+func (vm *VM) popBool(offset int) bool {
+	value, ok := vm.pop(offset).(bool)
+
+	if !ok {
+		vm.raise(offset, "expecting bool on the stack")
+	}
+
+	return value
+}
+
+func (vm *VM) popString(offset int) string {
+	value, ok := vm.pop(offset).(string)
+
+	if !ok {
+		vm.raise(offset, "expecting string on the stack")
+	}
+
+	return value
+}
+
+func (vm *VM) raise(offset int, message string) {
+	panic(runtimeError{offset, message})
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/logger"
+)
+
+func newAstCommand() *command {
+	cmd := newCommand("ast", "[options] <file|->", "Print the analysed abstract syntax tree as json.")
+
+	var options sourceOptions
+	options.register(cmd.flags)
+
+	cmd.run = func(cmd *command, args []string) int {
+		src, code := options.source(cmd, args)
+
+		if code != exitOK {
+			return code
+		}
+
+		var frontendLogger logger.StdError
+
+		root := parseFile(src, &frontendLogger)
+
+		if root != nil {
+			ast.PrintAst(root)
+		}
+
+		if frontendLogger.ErrorsCount() > 0 {
+			return exitFailure
+		}
+
+		return exitOK
+	}
+
+	return cmd
+}
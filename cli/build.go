@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/carlcui/expressive/logger"
+)
+
+type buildOptions struct {
+	sourceOptions
+	parallelOptions
+	lintOptions
+	optimizeOptions
+	checksOptions
+	outDir string
+	target string
+	debug  bool
+}
+
+func newBuildCommand() *command {
+	cmd := newCommand("build", "[options] <file|dir|pattern|->...", "Compile source files and write their llvm IR to .ll files, or another target.")
+
+	var options buildOptions
+	options.sourceOptions.register(cmd.flags)
+	options.parallelOptions.register(cmd.flags)
+	options.lintOptions.register(cmd.flags)
+	options.optimizeOptions.register(cmd.flags, "the IR of the llvm target")
+	cmd.flags.StringVar(&options.outDir, "outDir", ".", "output directory, mirroring the tree of the source files")
+	cmd.flags.StringVar(&options.target, "target", "llvm", "output format, one of "+targetNames())
+	cmd.flags.BoolVar(&options.debug, "g", false, "generate DWARF debug information (llvm target only)")
+	options.checksOptions.register(cmd.flags, "on to exit with status 3 and a runtime error on int overflow and division by zero, instead of undefined behaviour (llvm target only)")
+
+	cmd.run = func(cmd *command, args []string) int {
+		target := findTarget(options.target)
+
+		if target == nil {
+			return cmd.usageError("unknown target %q, expecting one of %v", options.target, targetNames())
+		}
+
+		if options.debug && target.generateWithOptions == nil {
+			return cmd.usageError("target %q has no debug information", target.name)
+		}
+
+		if options.checks && target.generateWithOptions == nil {
+			return cmd.usageError("target %q has no runtime checks", target.name)
+		}
+
+		sources, code := options.sources(cmd, args)
+
+		if code != exitOK {
+			return code
+		}
+
+		if err := checkOutputPaths(sources, options.outDir, target.extension); err != nil {
+			fmt.Fprintf(os.Stderr, "expressive %v: %v\n", cmd.name, err)
+			return exitFailure
+		}
+
+		return options.forEachSource(sources, func(src *source, logger logger.Logger) {
+			options.buildSource(src, target, logger)
+		})
+	}
+
+	return cmd
+}
+
+func (options *buildOptions) buildSource(src *source, target *target, logger logger.Logger) {
+	root := analyzeFile(src, &options.levels, logger)
+
+	if logger.ErrorsCount() > 0 {
+		return
+	}
+
+	options.optimize(root)
+
+	var output string
+
+	if target.generateWithOptions != nil {
+		output = target.generateWithOptions(root, options.codegenOptions(options.debug, options.checks), logger)
+	} else {
+		output = target.generate(root, logger)
+	}
+
+	if logger.ErrorsCount() > 0 {
+		return
+	}
+
+	if err := writeOutput(src.outputPath(options.outDir, target.extension), output); err != nil {
+		logger.Log(src.String(), err.Error())
+	}
+}
+
+func writeOutput(outfile string, content string) error {
+	if err := os.MkdirAll(filepath.Dir(outfile), os.ModeDir|os.ModePerm); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(outfile, []byte(content), 0644)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/carlcui/expressive/bytecode"
+	"github.com/carlcui/expressive/lint"
+	"github.com/carlcui/expressive/logger"
+)
+
+// bytecodeFile returns the path of the compiled program named by the arguments, or "" if they
+// do not name exactly one .expc file
+func (options *sourceOptions) bytecodeFile(args []string) string {
+	if options.file != "" {
+		args = append([]string{options.file}, args...)
+	}
+
+	if len(args) != 1 || filepath.Ext(args[0]) != bytecode.FileExtension {
+		return ""
+	}
+
+	if filepath.IsAbs(args[0]) {
+		return args[0]
+	}
+
+	return filepath.Join(options.dir, args[0])
+}
+
+func readBytecode(path string) (*bytecode.Program, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	program, err := bytecode.Read(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+
+	return program, nil
+}
+
+// loadBytecode reads the .expc file named by the arguments, or compiles the source they name.
+// The returned exit code is exitOK on success.
+func loadBytecode(cmd *command, options *sourceOptions, levels *lint.Levels, optimize *optimizeOptions, args []string) (*bytecode.Program, int) {
+	if path := options.bytecodeFile(args); path != "" {
+		program, err := readBytecode(path)
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "expressive %v: %v\n", cmd.name, err)
+			return nil, exitFailure
+		}
+
+		return program, exitOK
+	}
+
+	src, code := options.source(cmd, args)
+
+	if code != exitOK {
+		return nil, code
+	}
+
+	var frontendLogger logger.StdError
+
+	root := analyzeFile(src, levels, &frontendLogger)
+
+	if frontendLogger.ErrorsCount() > 0 {
+		return nil, exitFailure
+	}
+
+	optimize.optimize(root)
+
+	program := bytecode.Compile(root, &frontendLogger)
+
+	if program == nil {
+		return nil, exitFailure
+	}
+
+	return program, exitOK
+}
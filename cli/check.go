@@ -0,0 +1,34 @@
+package main
+
+import (
+	"github.com/carlcui/expressive/logger"
+)
+
+type checkOptions struct {
+	sourceOptions
+	parallelOptions
+	lintOptions
+}
+
+func newCheckCommand() *command {
+	cmd := newCommand("check", "[options] <file|dir|pattern|->...", "Scan, parse and type check source files and report lints without generating code.")
+
+	var options checkOptions
+	options.sourceOptions.register(cmd.flags)
+	options.parallelOptions.register(cmd.flags)
+	options.lintOptions.register(cmd.flags)
+
+	cmd.run = func(cmd *command, args []string) int {
+		sources, code := options.sources(cmd, args)
+
+		if code != exitOK {
+			return code
+		}
+
+		return options.forEachSource(sources, func(src *source, logger logger.Logger) {
+			analyzeFile(src, &options.levels, logger)
+		})
+	}
+
+	return cmd
+}
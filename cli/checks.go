@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+// switchFlag is turned on or off, as in --checks=on
+type switchFlag struct {
+	on *bool
+}
+
+func (f *switchFlag) String() string {
+	if f.on != nil && *f.on {
+		return "on"
+	}
+
+	return "off"
+}
+
+func (f *switchFlag) Set(value string) error {
+	switch value {
+	case "on":
+		*f.on = true
+	case "off":
+		*f.on = false
+	default:
+		return fmt.Errorf("expecting on or off, got %q", value)
+	}
+
+	return nil
+}
+
+// checksOptions controls whether the generated program guards its int operations
+type checksOptions struct {
+	checks bool
+}
+
+func (options *checksOptions) register(flags *flag.FlagSet, usage string) {
+	flags.Var(&switchFlag{&options.checks}, "checks", usage)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// exit codes shared by every command
+const (
+	exitOK          = 0 // command finished successfully
+	exitFailure     = 1 // the program has errors, or the output could not be produced
+	exitUsageFailed = 2 // the command line could not be understood
+)
+
+// command is a subcommand of the cli, e.g. `expressive build`
+type command struct {
+	name      string
+	usageLine string // arguments following the command name in usage text
+	short     string // one-line description shown in the command list
+	flags     *flag.FlagSet
+	run       func(cmd *command, args []string) int
+}
+
+func newCommand(name string, usageLine string, short string) *command {
+	cmd := &command{name: name, usageLine: usageLine, short: short}
+
+	cmd.flags = flag.NewFlagSet(name, flag.ContinueOnError)
+	cmd.flags.SetOutput(os.Stderr)
+	cmd.flags.Usage = cmd.printUsage
+
+	return cmd
+}
+
+func (cmd *command) printUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: expressive %v %v\n\n%v\n", cmd.name, cmd.usageLine, cmd.short)
+
+	hasFlags := false
+	cmd.flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+
+	if hasFlags {
+		fmt.Fprintln(os.Stderr, "\nOptions:")
+		cmd.flags.PrintDefaults()
+	}
+}
+
+// execute parses the flags of the command and runs it with the remaining positional arguments
+func (cmd *command) execute(args []string) int {
+	positionals, err := parseFlags(cmd.flags, args)
+
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+
+	if err != nil {
+		return exitUsageFailed
+	}
+
+	return cmd.run(cmd, positionals)
+}
+
+// usageError reports a misuse of the command and returns the matching exit code
+func (cmd *command) usageError(format string, args ...interface{}) int {
+	fmt.Fprintf(os.Stderr, "expressive %v: %v\n", cmd.name, fmt.Sprintf(format, args...))
+	fmt.Fprintf(os.Stderr, "Run 'expressive help %v' for usage.\n", cmd.name)
+
+	return exitUsageFailed
+}
+
+// parseFlags parses flags that may appear before, between or after positional arguments.
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	positionals := make([]string, 0)
+
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = flags.Args()
+
+		if len(args) == 0 {
+			return positionals, nil
+		}
+
+		positionals = append(positionals, args[0])
+		args = args[1:]
+	}
+}
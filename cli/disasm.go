@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/carlcui/expressive/bytecode"
+)
+
+type disasmOptions struct {
+	sourceOptions
+	lintOptions
+	optimizeOptions
+}
+
+func newDisasmCommand() *command {
+	cmd := newCommand("disasm", "[options] <file.expc|file|->", "Print the bytecode of a compiled program, or of a source file compiled to bytecode.")
+
+	var options disasmOptions
+	options.sourceOptions.register(cmd.flags)
+	options.lintOptions.register(cmd.flags)
+	options.optimizeOptions.register(cmd.flags, "")
+
+	cmd.run = func(cmd *command, args []string) int {
+		program, code := loadBytecode(cmd, &options.sourceOptions, &options.levels, &options.optimizeOptions, args)
+
+		if code != exitOK {
+			return code
+		}
+
+		if err := bytecode.Disassemble(os.Stdout, program); err != nil {
+			fmt.Fprintf(os.Stderr, "expressive disasm: %v\n", err)
+			return exitFailure
+		}
+
+		return exitOK
+	}
+
+	return cmd
+}
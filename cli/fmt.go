@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/carlcui/expressive/format"
+	"github.com/carlcui/expressive/logger"
+)
+
+type fmtOptions struct {
+	sourceOptions
+	check bool
+	write bool
+}
+
+func newFmtCommand() *command {
+	cmd := newCommand("fmt", "[options] <file|dir|pattern|->...", "Format source files in the canonical layout, and print them to stdout.")
+
+	var options fmtOptions
+	options.sourceOptions.register(cmd.flags)
+	cmd.flags.BoolVar(&options.check, "check", false, "print the names of the files that are not formatted instead, and exit with status 1 if there is any")
+	cmd.flags.BoolVar(&options.write, "write", false, "rewrite the files that are not formatted instead of printing them")
+
+	cmd.run = func(cmd *command, args []string) int {
+		if options.check && options.write {
+			return cmd.usageError("--check and --write cannot be combined")
+		}
+
+		sources, code := options.sources(cmd, args)
+
+		if code != exitOK {
+			return code
+		}
+
+		if options.write {
+			for _, src := range sources {
+				if src.isStdin {
+					return cmd.usageError("cannot rewrite stdin (-)")
+				}
+			}
+		}
+
+		exitCode := exitOK
+
+		for _, src := range sources {
+			if !options.format(src) {
+				exitCode = exitFailure
+			}
+		}
+
+		return exitCode
+	}
+
+	return cmd
+}
+
+// format formats a source file as the options ask, and returns false if it cannot be formatted,
+// or is not formatted when checking
+func (options *fmtOptions) format(src *source) bool {
+	var frontendLogger logger.StdError
+
+	root := parseCST(src, &frontendLogger)
+
+	if root == nil || frontendLogger.ErrorsCount() > 0 {
+		return false
+	}
+
+	formatted := format.Source(root)
+
+	original := src.content
+
+	if !src.isStdin {
+		content, err := ioutil.ReadFile(src.path())
+
+		if err != nil {
+			frontendLogger.Log(src.String(), err.Error())
+			return false
+		}
+
+		original = content
+	}
+
+	switch {
+	case options.check:
+		if formatted != string(original) {
+			fmt.Println(src)
+			return false
+		}
+	case options.write:
+		if formatted != string(original) {
+			if err := ioutil.WriteFile(src.path(), []byte(formatted), 0644); err != nil {
+				frontendLogger.Log(src.String(), err.Error())
+				return false
+			}
+		}
+	default:
+		fmt.Print(formatted)
+	}
+
+	return true
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/cst"
+	"github.com/carlcui/expressive/input"
+	"github.com/carlcui/expressive/internal/frontend"
+	"github.com/carlcui/expressive/lint"
+	"github.com/carlcui/expressive/logger"
+	"github.com/carlcui/expressive/scanner"
+)
+
+// openInput opens the input of the source. It returns nil after reporting to logger if the
+// source cannot be read.
+func openInput(src *source, logger logger.Logger) input.Input {
+	sourceInput, err := src.newInput(logger)
+
+	if err != nil {
+		logger.Log(src.String(), err.Error())
+		return nil
+	}
+
+	return sourceInput
+}
+
+// newScanner opens a scanner over the source. It returns nil after reporting to logger if the
+// source cannot be read.
+func newScanner(src *source, logger logger.Logger) *scanner.ExpressiveScanner {
+	sourceInput := openInput(src, logger)
+
+	if sourceInput == nil {
+		return nil
+	}
+
+	var s scanner.ExpressiveScanner
+	s.Init(sourceInput)
+
+	return &s
+}
+
+// parseCST parses a source file into its concrete syntax tree, keeping comments and whitespace.
+// It returns nil if the source file cannot be read.
+func parseCST(src *source, logger logger.Logger) *cst.Node {
+	sourceInput := openInput(src, logger)
+
+	if sourceInput == nil {
+		return nil
+	}
+
+	return frontend.ParseCST(sourceInput, logger)
+}
+
+// parseFile runs the frontend (scanning, parsing and semantic analysis) over a source file.
+// It returns nil if the source file cannot be read.
+func parseFile(src *source, logger logger.Logger) ast.Node {
+	sourceInput := openInput(src, logger)
+
+	if sourceInput == nil {
+		return nil
+	}
+
+	return frontend.Analyze(sourceInput, nil, logger)
+}
+
+// analyzeFile is like parseFile, and also reports lints of a correct program
+func analyzeFile(src *source, levels *lint.Levels, logger logger.Logger) ast.Node {
+	root := parseFile(src, logger)
+
+	if root == nil || logger.ErrorsCount() > 0 {
+		return root
+	}
+
+	lint.Check(root, levels, logger)
+
+	return root
+}
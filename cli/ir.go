@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/carlcui/expressive/codegen"
+	"github.com/carlcui/expressive/logger"
+)
+
+type irOptions struct {
+	sourceOptions
+	lintOptions
+	optimizeOptions
+	checksOptions
+	debug bool
+}
+
+func newIrCommand() *command {
+	cmd := newCommand("ir", "[options] <file|->", "Compile a source file and print its llvm IR to stdout.")
+
+	var options irOptions
+	options.sourceOptions.register(cmd.flags)
+	options.lintOptions.register(cmd.flags)
+	options.optimizeOptions.register(cmd.flags, "llvm IR")
+	cmd.flags.BoolVar(&options.debug, "g", false, "generate DWARF debug information")
+	options.checksOptions.register(cmd.flags, "on to exit with status 3 and a runtime error on int overflow and division by zero, instead of undefined behaviour")
+
+	cmd.run = func(cmd *command, args []string) int {
+		src, code := options.source(cmd, args)
+
+		if code != exitOK {
+			return code
+		}
+
+		var frontendLogger logger.StdError
+
+		root := analyzeFile(src, &options.levels, &frontendLogger)
+
+		if frontendLogger.ErrorsCount() > 0 {
+			return exitFailure
+		}
+
+		var codegenLogger logger.StdError
+
+		options.optimize(root)
+
+		irCode := codegen.GenerateWithOptions(root, options.codegenOptions(options.debug, options.checks), &codegenLogger)
+
+		if codegenLogger.ErrorsCount() > 0 {
+			return exitFailure
+		}
+
+		fmt.Print(irCode)
+
+		return exitOK
+	}
+
+	return cmd
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/carlcui/expressive/lint"
+)
+
+// lintFlag sets the level of the lints named by each occurrence of the flag
+type lintFlag struct {
+	levels *lint.Levels
+	level  lint.Level
+}
+
+func (f *lintFlag) String() string {
+	return ""
+}
+
+func (f *lintFlag) Set(names string) error {
+	return f.levels.Set(names, f.level)
+}
+
+// lintOptions controls which lints are reported as warnings, errors or not at all
+type lintOptions struct {
+	levels lint.Levels
+}
+
+func (options *lintOptions) register(flags *flag.FlagSet) {
+	names := strings.Join(lint.Names(), ", ")
+
+	flags.Var(&lintFlag{&options.levels, lint.ALLOW}, "allow", "comma separated lints not to report, or all ("+names+")")
+	flags.Var(&lintFlag{&options.levels, lint.DENY}, "deny", "comma separated lints to report as errors, or all")
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/carlcui/expressive/lsp"
+)
+
+func newLspCommand() *command {
+	cmd := newCommand("lsp", "", "Serve the Language Server Protocol over stdin and stdout, for editors.")
+
+	cmd.run = func(cmd *command, args []string) int {
+		if len(args) > 0 {
+			return cmd.usageError("unexpected arguments %v", args)
+		}
+
+		if err := lsp.NewServer(os.Stdin, os.Stdout).Serve(); err != nil {
+			fmt.Fprintf(os.Stderr, "expressive lsp: %v\n", err)
+			return exitFailure
+		}
+
+		return exitOK
+	}
+
+	return cmd
+}
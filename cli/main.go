@@ -2,124 +2,94 @@ package main
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
-	"path"
-	"strings"
-
-	"github.com/carlcui/expressive/codegen"
-
-	"github.com/carlcui/expressive/ast"
-	"github.com/carlcui/expressive/input"
-	"github.com/carlcui/expressive/logger"
-	"github.com/carlcui/expressive/parser"
-	"github.com/carlcui/expressive/scanner"
-	"github.com/carlcui/expressive/semanticAnalyser"
 )
 
-const helpMessage = "Current supported options are: \n" +
-	"--asm: produce llvm ir code \n" +
-	"--dir/-d: directory of source file \n" +
-	"--file/-f: source file name \n" +
-	"--help: see all command line options \n" +
-	"--outDir: output directory \n"
-
-func parseFile(dirName string, filename string, logger logger.Logger) ast.Node {
-	var fileInput input.File
-	fileInput.Init(dirName, filename)
-
-	var s scanner.ExpressiveScanner
-	s.Init(&fileInput)
-
-	var p parser.Parser
-	p.Init(&s, logger)
-
-	root := p.Parse()
-
-	semanticAnalyser.Analyze(root, logger)
-
-	return root
-}
-
-func checkSourceFileExtension(filename string) {
-	if path.Ext(filename) != ".exp" {
-		fmt.Printf("File %v does not end with .exp", filename)
+const helpMessage = "expressive is a compiler for the expressive language.\n\n" +
+	"Usage:\n\n" +
+	"\texpressive <command> [options] <file>\n\n" +
+	"The commands are:\n\n"
+
+var commands = newCommands()
+
+// newCommands creates the commands of the cli, in the order of the help message
+func newCommands() []*command {
+	return []*command{
+		newBuildCommand(),
+		newRunCommand(),
+		newCheckCommand(),
+		newFmtCommand(),
+		newTokensCommand(),
+		newAstCommand(),
+		newIrCommand(),
+		newDisasmCommand(),
+		newLspCommand(),
+		newReplCommand(),
 	}
 }
 
-func createOutDirIfNotExist(outdir string) {
-	if _, err := os.Stat(outdir); err != nil {
-		os.MkdirAll(outdir, os.ModeDir|os.ModePerm)
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
 	}
+
+	return nil
 }
 
-func main() {
-	args := os.Args
+func printHelp() {
+	fmt.Fprint(os.Stderr, helpMessage)
 
-	if len(args) == 1 {
-		panic(fmt.Errorf("No options given. \n %v", helpMessage))
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "\t%-8v %v\n", cmd.name, cmd.short)
 	}
 
-	var filename string
-	dirName := "." // default to current dir
-	outDir := "."  // default to current dir
-
-	options := args[1:]
-
-	for i, arg := range options {
-
-		if arg == "--file" || arg == "-f" {
-			if i+1 == len(options) {
-				panic("No file specified")
-			}
-
-			filename = options[i+1]
-		}
-
-		if arg == "--dir" || arg == "-d" {
-			if i+1 == len(options) {
-				panic("No directory specified")
-			}
+	fmt.Fprintln(os.Stderr, "\nUse \"expressive help <command>\" for more information about a command.")
+}
 
-			dirName = options[i+1]
-		}
+func help(args []string) int {
+	if len(args) == 0 {
+		printHelp()
+		return exitOK
+	}
 
-		if arg == "--outDir" {
-			if i+1 == len(options) {
-				panic("No output directory specified")
-			}
+	cmd := findCommand(args[0])
 
-			outDir = options[i+1]
-		}
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "expressive help: unknown command %q\n", args[0])
+		return exitUsageFailed
 	}
 
-	checkSourceFileExtension(filename)
-
-	var frontendLogger logger.StdError
+	cmd.printUsage()
 
-	root := parseFile(dirName, filename, &frontendLogger)
+	return exitOK
+}
 
-	if frontendLogger.ErrorsCount() > 0 {
-		return
+func runMain(args []string) int {
+	if len(args) == 0 {
+		printHelp()
+		return exitUsageFailed
 	}
 
-	var codegenLogger logger.StdError
-
-	code := codegen.Generate(root, &codegenLogger)
+	name := args[0]
 
-	if codegenLogger.ErrorsCount() > 0 {
-		return
+	switch name {
+	case "help", "--help", "-help", "-h":
+		return help(args[1:])
 	}
 
-	outputFilename := strings.Replace(filename, ".exp", ".s", -1)
+	cmd := findCommand(name)
 
-	outfile := path.Join(outDir, outputFilename)
-
-	createOutDirIfNotExist(outDir)
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "expressive: unknown command %q\n", name)
+		fmt.Fprintln(os.Stderr, "Run 'expressive help' for usage.")
+		return exitUsageFailed
+	}
 
-	err := ioutil.WriteFile(outfile, []byte(code), os.ModePerm)
+	return cmd.execute(args[1:])
+}
 
-	if err != nil {
-		fmt.Println(err)
-	}
+func main() {
+	os.Exit(runMain(os.Args[1:]))
 }
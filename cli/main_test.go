@@ -0,0 +1,208 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carlcui/expressive/codegen"
+)
+
+// capture creates a temporary file standing for stdout, stderr or stdin while a command runs
+func capture(content string, t *testing.T) *os.File {
+	file, err := ioutil.TempFile("", "expressive-cli")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := file.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+// readBack returns what was written to a file made by capture, and removes it
+func readBack(file *os.File, t *testing.T) string {
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	content, err := ioutil.ReadFile(file.Name())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(content)
+}
+
+// execute runs the cli with args and stdin, with commands created anew so that no flag is kept
+// from a previous run. It returns what was printed to stdout and stderr, and the exit code.
+func execute(stdin string, t *testing.T, args ...string) (string, string, int) {
+	savedStdin, savedStdout, savedStderr, savedCommands := os.Stdin, os.Stdout, os.Stderr, commands
+
+	defer func() {
+		os.Stdin, os.Stdout, os.Stderr, commands = savedStdin, savedStdout, savedStderr, savedCommands
+	}()
+
+	in, out, errOut := capture(stdin, t), capture("", t), capture("", t)
+	defer readBack(in, t)
+
+	os.Stdin, os.Stdout, os.Stderr = in, out, errOut
+	commands = newCommands()
+
+	code := runMain(args)
+
+	return readBack(out, t), readBack(errOut, t), code
+}
+
+// writeFiles creates a temporary directory holding files, given by their slash separated path
+// relative to the directory, and returns the directory
+func writeFiles(files map[string]string, t *testing.T) string {
+	dir, err := ioutil.TempDir("", "expressive-cli")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestUsageErrors(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{nil, "The commands are:"},
+		{[]string{"compile"}, "expressive: unknown command \"compile\""},
+		{[]string{"help", "compile"}, "expressive help: unknown command \"compile\""},
+		{[]string{"check"}, "expressive check: no source file given"},
+		{[]string{"check", "--nope", "a.exp"}, "flag provided but not defined: -nope"},
+		{[]string{"build", "--target", "cobol", "a.exp"}, "expressive build: unknown target \"cobol\""},
+		{[]string{"build", "--target", "c", "-g", "a.exp"}, "expressive build: target \"c\" has no debug information"},
+		{[]string{"ir", "a.exp", "b.exp"}, "expressive ir: expecting one source file, but got 2"},
+	}
+
+	dir := writeFiles(map[string]string{"a.exp": "", "b.exp": ""}, t)
+	defer os.RemoveAll(dir)
+
+	for _, test := range tests {
+		args := test.args
+
+		if len(args) > 1 && args[0] == "ir" {
+			args = append([]string{"ir", "-d", dir}, args[1:]...)
+		}
+
+		stdout, stderr, code := execute("", t, args...)
+
+		if code != exitUsageFailed || stdout != "" || !strings.Contains(stderr, test.expected) {
+			t.Errorf("Expecting %q to exit with %v and report %q, got %v and %q", args, exitUsageFailed, test.expected, code, stderr)
+		}
+	}
+}
+
+func TestHelp(t *testing.T) {
+	for _, args := range [][]string{{"help"}, {"help", "run"}, {"run", "--help"}} {
+		if _, stderr, code := execute("", t, args...); code != exitOK || !strings.Contains(stderr, "Usage:") {
+			t.Errorf("Expecting %q to print the usage and exit with 0, got %v and %q", args, code, stderr)
+		}
+	}
+
+	// the optimization levels only describe llvm IR passes where llvm IR is generated
+	for _, test := range []struct {
+		command string
+		ir      bool
+	}{{"run", false}, {"disasm", false}, {"ir", true}, {"build", true}} {
+		if _, stderr, _ := execute("", t, "help", test.command); strings.Contains(stderr, "dead instructions") != test.ir {
+			t.Errorf("Expecting the help of %v to mention llvm IR passes: %v, got %q", test.command, test.ir, stderr)
+		}
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	dir := writeFiles(map[string]string{
+		"ok.exp":      "let a = 6 * 7;\nprint \"%d\\n\", a;\n",
+		"type.exp":    "let a = 1 + \"x\";\n",
+		"runtime.exp": "print \"before\\n\";\nlet a = 0;\nprint \"%d\\n\", 1 / a;\n",
+		"lint.exp":    "let unused = 1;\n",
+	}, t)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		args           []string
+		code           int
+		stdout, stderr string
+	}{
+		{[]string{"check", "-d", dir, "ok.exp"}, exitOK, "", ""},
+		{[]string{"check", "-d", dir, "type.exp"}, exitFailure, "", "type.exp: row 0, column 10: +(Addition) does not support operation on [INT STRING]"},
+		{[]string{"check", "-d", dir, "lint.exp"}, exitOK, "", "warning: variable \"unused\" is declared but never read (unused-variable)"},
+		{[]string{"check", "-d", dir, "--deny", "all", "lint.exp"}, exitFailure, "", "variable \"unused\" is declared but never read (unused-variable)"},
+		{[]string{"check", "-d", dir, "missing.exp"}, exitFailure, "", "missing.exp"},
+		{[]string{"run", "-d", dir, "ok.exp"}, exitOK, "42\n", ""},
+		{[]string{"run", "--vm", "-d", dir, "ok.exp"}, exitOK, "42\n", ""},
+		{[]string{"run", "-d", dir, "runtime.exp"}, codegen.PanicExitCode, "before\n", "runtime error: integer division by zero"},
+		{[]string{"run", "--vm", "-d", dir, "runtime.exp"}, codegen.PanicExitCode, "before\n", "runtime error: integer division by zero"},
+		{[]string{"run", "-d", dir, "type.exp"}, exitFailure, "", "does not support operation"},
+		{[]string{"ir", "-d", dir, "ok.exp"}, exitOK, "define i32 @main()", ""},
+		{[]string{"tokens", "-d", dir, "ok.exp"}, exitOK, "row 0, column 4: IDENTIFIER: a\n", ""},
+		{[]string{"ast", "-d", dir, "ok.exp"}, exitOK, "\"NodeType\": \"Program node\"", ""},
+	}
+
+	for _, test := range tests {
+		stdout, stderr, code := execute("", t, test.args...)
+
+		if code != test.code || !strings.Contains(stdout, test.stdout) || !strings.Contains(stderr, test.stderr) || test.stderr == "" && stderr != "" {
+			t.Errorf("Expecting %q to exit with %v, print %q and report %q, got %v, %q and %q", test.args, test.code, test.stdout, test.stderr, code, stdout, stderr)
+		}
+	}
+}
+
+func TestStdin(t *testing.T) {
+	stdout, stderr, code := execute("print \"%d\\n\", 1 + 2;\n", t, "run", "-")
+
+	if code != exitOK || stdout != "3\n" || stderr != "" {
+		t.Errorf("Expecting the program on stdin to print 3, got %v, %q and %q", code, stdout, stderr)
+	}
+
+	_, stderr, code = execute("let a = 1;\nlet \xff = 2;\n", t, "check", "-")
+
+	if expected := "file <stdin>: row 1, column 4: invalid UTF-8 encoding: unexpected byte 0xff\n"; code != exitFailure || !strings.HasPrefix(stderr, expected) {
+		t.Errorf("Expecting %q, got %v and %q", expected, code, stderr)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	dir := writeFiles(map[string]string{"a.exp": "print \"a\\n\";\n", "nested/b.exp": "print \"b\\n\";\n"}, t)
+	defer os.RemoveAll(dir)
+
+	outDir := filepath.Join(dir, "out")
+
+	if _, stderr, code := execute("", t, "build", "-d", dir, "--outDir", outDir, "."); code != exitOK {
+		t.Fatalf("Expecting build to succeed, got %v and %q", code, stderr)
+	}
+
+	for _, name := range []string{"a.ll", filepath.Join("nested", "b.ll")} {
+		if content, err := ioutil.ReadFile(filepath.Join(outDir, name)); err != nil || !strings.Contains(string(content), "define i32 @main()") {
+			t.Errorf("Expecting %v to hold llvm IR, got %v", name, err)
+		}
+	}
+}
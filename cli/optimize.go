@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"strconv"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/codegen"
+	"github.com/carlcui/expressive/codegen/passes"
+	"github.com/carlcui/expressive/optimizer"
+)
+
+// optimizationFlag selects an optimization level when given, as in -O1
+type optimizationFlag struct {
+	level *int
+	value int
+}
+
+func (f *optimizationFlag) String() string {
+	return ""
+}
+
+func (f *optimizationFlag) Set(value string) error {
+	enabled, err := strconv.ParseBool(value)
+
+	if err == nil && enabled {
+		*f.level = f.value
+	}
+
+	return err
+}
+
+func (f *optimizationFlag) IsBoolFlag() bool {
+	return true
+}
+
+// optimizeOptions controls how much the analysed program is optimized before running or
+// generating code
+type optimizeOptions struct {
+	level int
+}
+
+// register the optimization levels. ir names the llvm IR the command generates, to describe its
+// passes, or is empty if the command generates none.
+func (options *optimizeOptions) register(flags *flag.FlagSet, ir string) {
+	o1Usage := "fold constant expressions and consts, and remove branches and loops that never run"
+	o2Usage := "optimize as -O1"
+
+	if ir != "" {
+		o1Usage += ", then remove empty blocks and dead instructions from " + ir
+		o2Usage += ", and promote variables to registers in " + ir
+	}
+
+	flags.Var(&optimizationFlag{&options.level, 0}, "O0", "do not optimize (default)")
+	flags.Var(&optimizationFlag{&options.level, 1}, "O1", o1Usage)
+	flags.Var(&optimizationFlag{&options.level, 2}, "O2", o2Usage)
+}
+
+// codegenOptions of the llvm IR generated at the optimization level
+func (options *optimizeOptions) codegenOptions(debug bool, checks bool) codegen.Options {
+	return codegen.Options{DebugInfo: debug, Checks: checks, Passes: passes.Pipeline(options.level)}
+}
+
+func (options *optimizeOptions) optimize(root ast.Node) {
+	if options.level >= 1 {
+		optimizer.Optimize(root)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/carlcui/expressive/logger"
+)
+
+// parallelOptions controls how many sources are compiled at the same time
+type parallelOptions struct {
+	jobs int
+}
+
+func (options *parallelOptions) register(flags *flag.FlagSet) {
+	flags.IntVar(&options.jobs, "j", runtime.NumCPU(), "number of source files compiled in parallel")
+}
+
+// runJob runs job over one source, turning a panic into a diagnostic of that source instead
+// of taking down the compilation of every other source
+func runJob(job func(src *source, logger logger.Logger), src *source, logger logger.Logger) {
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Log(src.String(), fmt.Sprintf("internal compiler error: %v", err))
+		}
+	}()
+
+	job(src, logger)
+}
+
+// forEachSource runs job over every source, using up to options.jobs goroutines. Each job logs
+// into its own buffer, and buffers are printed in the order the sources were given once the
+// job of the source has finished.
+func (options *parallelOptions) forEachSource(sources []*source, job func(src *source, logger logger.Logger)) int {
+	jobs := options.jobs
+
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	loggers := make([]*logger.Buffer, len(sources))
+	done := make([]chan struct{}, len(sources))
+	indices := make(chan int)
+
+	for i := range sources {
+		loggers[i] = &logger.Buffer{}
+		done[i] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+
+	for worker := 0; worker < jobs; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				runJob(job, sources[i], loggers[i])
+				close(done[i])
+			}
+		}()
+	}
+
+	go func() {
+		for i := range sources {
+			indices <- i
+		}
+
+		close(indices)
+	}()
+
+	exitCode := exitOK
+
+	for i := range sources {
+		<-done[i]
+
+		loggers[i].Flush(os.Stderr)
+
+		if loggers[i].ErrorsCount() > 0 {
+			exitCode = exitFailure
+		}
+	}
+
+	wg.Wait()
+
+	return exitCode
+}
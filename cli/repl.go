@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/carlcui/expressive/repl"
+)
+
+func newReplCommand() *command {
+	cmd := newCommand("repl", "", "Evaluate statements and expressions as they are entered, printing the value and the type of expressions.\n\n"+
+		"Meta-commands:\n"+
+		"\t:type <expr>   print the type of an expression\n"+
+		"\t:ast <entry>   print the analysed ast of an expression or statements\n"+
+		"\t:ir            print the llvm IR of the statements entered so far\n"+
+		"\t:reset         forget the variables and statements entered so far")
+
+	cmd.run = func(cmd *command, args []string) int {
+		if len(args) > 0 {
+			return cmd.usageError("unexpected arguments %v", args)
+		}
+
+		if err := repl.NewSession(os.Stdout).Run(os.Stdin); err != nil {
+			fmt.Fprintf(os.Stderr, "expressive repl: %v\n", err)
+			return exitFailure
+		}
+
+		return exitOK
+	}
+
+	return cmd
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+
+	"github.com/carlcui/expressive/bytecode"
+	"github.com/carlcui/expressive/codegen"
+	"github.com/carlcui/expressive/interp"
+	"github.com/carlcui/expressive/logger"
+)
+
+type runOptions struct {
+	sourceOptions
+	lintOptions
+	optimizeOptions
+	vm bool
+}
+
+func newRunCommand() *command {
+	cmd := newCommand("run", "[options] <file.expc|file|->", "Execute a source file with the built-in interpreter, or a compiled program with the bytecode vm, without an external lli.")
+
+	var options runOptions
+	options.sourceOptions.register(cmd.flags)
+	options.lintOptions.register(cmd.flags)
+	options.optimizeOptions.register(cmd.flags, "")
+	cmd.flags.BoolVar(&options.vm, "vm", false, "compile the source file to bytecode and run it with the vm instead of the interpreter")
+
+	cmd.run = func(cmd *command, args []string) int {
+		if options.vm || options.bytecodeFile(args) != "" {
+			program, code := loadBytecode(cmd, &options.sourceOptions, &options.levels, &options.optimizeOptions, args)
+
+			if code != exitOK {
+				return code
+			}
+
+			var runtimeLogger logger.StdError
+
+			bytecode.Run(program, os.Stdout, &runtimeLogger)
+
+			return exitCodeOf(&runtimeLogger)
+		}
+
+		src, code := options.source(cmd, args)
+
+		if code != exitOK {
+			return code
+		}
+
+		var frontendLogger logger.StdError
+
+		root := analyzeFile(src, &options.levels, &frontendLogger)
+
+		if frontendLogger.ErrorsCount() > 0 {
+			return exitFailure
+		}
+
+		options.optimize(root)
+
+		var runtimeLogger logger.StdError
+
+		interp.Run(root, os.Stdout, &runtimeLogger)
+
+		return exitCodeOf(&runtimeLogger)
+	}
+
+	return cmd
+}
+
+// exitCodeOf returns the status of a run: a runtime error exits like a failed runtime check of
+// a compiled program
+func exitCodeOf(logger logger.Logger) int {
+	if logger.ErrorsCount() > 0 {
+		return codegen.PanicExitCode
+	}
+
+	return exitOK
+}
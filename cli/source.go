@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carlcui/expressive/input"
+	"github.com/carlcui/expressive/logger"
+)
+
+// stdinArgument is the argument standing for source read from stdin
+const stdinArgument = "-"
+
+const sourceFileExtension = ".exp"
+
+// source is one compilation unit given on the command line
+type source struct {
+	dir     string // directory name is resolved against
+	name    string // path of the source file relative to dir, or an absolute path
+	isStdin bool
+	content []byte // source read from stdin
+}
+
+func (src *source) String() string {
+	if src.isStdin {
+		return "<stdin>"
+	}
+
+	return src.path()
+}
+
+func (src *source) path() string {
+	if filepath.IsAbs(src.name) {
+		return src.name
+	}
+
+	return filepath.Join(src.dir, src.name)
+}
+
+// newInput opens the source for scanning. Encoding errors are reported to logger while scanning.
+func (src *source) newInput(logger logger.Logger) (input.Input, error) {
+	if src.isStdin {
+		var reader input.Reader
+		reader.Init(bytes.NewReader(src.content), src.String(), logger)
+
+		return &reader, nil
+	}
+
+	var fileInput input.File
+
+	dir := src.dir
+
+	if filepath.IsAbs(src.name) {
+		dir = ""
+	}
+
+	if err := fileInput.Init(dir, src.name, logger); err != nil {
+		return nil, err
+	}
+
+	return &fileInput, nil
+}
+
+// outputPath mirrors the location of the source under outDir, replacing its extension with ext
+func (src *source) outputPath(outDir string, ext string) string {
+	name := src.name
+
+	if src.isStdin {
+		name = "stdin"
+	} else if filepath.IsAbs(name) {
+		name = filepath.Base(name)
+	}
+
+	return filepath.Join(outDir, strings.TrimSuffix(name, filepath.Ext(name))+ext)
+}
+
+// checkOutputPaths returns an error if two sources would be built into the same file under
+// outDir, e.g. sources of the same name outside of the source directory
+func checkOutputPaths(sources []*source, outDir string, ext string) error {
+	built := make(map[string]*source)
+
+	for _, src := range sources {
+		outfile := src.outputPath(outDir, ext)
+
+		if other, ok := built[outfile]; ok {
+			return fmt.Errorf("%v and %v would both be built into %v", other, src, outfile)
+		}
+
+		built[outfile] = src
+	}
+
+	return nil
+}
+
+// sourceExpander turns command line arguments into sources
+type sourceExpander struct {
+	dir     string
+	sources []*source
+	seen    map[string]bool
+	stdin   bool
+}
+
+func (expander *sourceExpander) add(src *source) {
+	key := src.String()
+
+	if expander.seen[key] {
+		return
+	}
+
+	expander.seen[key] = true
+	expander.sources = append(expander.sources, src)
+}
+
+// relative returns the path of a file found on disk relative to the source directory, or its
+// absolute path if the file is outside of the source directory, so that it is not resolved
+// against the source directory a second time
+func (expander *sourceExpander) relative(filePath string) string {
+	rel, err := filepath.Rel(expander.dir, filePath)
+
+	if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return rel
+	}
+
+	if absPath, err := filepath.Abs(filePath); err == nil {
+		return absPath
+	}
+
+	return filePath
+}
+
+func (expander *sourceExpander) expand(arg string) error {
+	if arg == stdinArgument {
+		if expander.stdin {
+			return fmt.Errorf("stdin (-) can only be given once")
+		}
+
+		content, err := ioutil.ReadAll(os.Stdin)
+
+		if err != nil {
+			return err
+		}
+
+		expander.stdin = true
+		expander.add(&source{name: stdinArgument, isStdin: true, content: content})
+
+		return nil
+	}
+
+	argPath := arg
+
+	if !filepath.IsAbs(arg) {
+		argPath = filepath.Join(expander.dir, arg)
+	}
+
+	if strings.ContainsAny(arg, "*?[") {
+		matches, err := filepath.Glob(argPath)
+
+		if err != nil {
+			return err
+		}
+
+		if len(matches) == 0 {
+			return fmt.Errorf("pattern %v matches no files", arg)
+		}
+
+		for _, match := range matches {
+			if err := expander.expandPath(match, false); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return expander.expandPath(argPath, true)
+}
+
+// expandPath adds a source file, or every source file under a directory. Files named
+// explicitly must have the source file extension, other files are skipped.
+func (expander *sourceExpander) expandPath(filePath string, explicit bool) error {
+	info, err := os.Stat(filePath)
+
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		if filepath.Ext(filePath) != sourceFileExtension {
+			if explicit {
+				return fmt.Errorf("file %v does not end with %v", filePath, sourceFileExtension)
+			}
+
+			return nil
+		}
+
+		expander.add(&source{dir: expander.dir, name: expander.relative(filePath)})
+
+		return nil
+	}
+
+	return filepath.Walk(filePath, func(walkedPath string, walkedInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !walkedInfo.IsDir() && filepath.Ext(walkedPath) == sourceFileExtension {
+			expander.add(&source{dir: expander.dir, name: expander.relative(walkedPath)})
+		}
+
+		return nil
+	})
+}
+
+// sourceOptions locates the source files a command works on
+type sourceOptions struct {
+	dir  string
+	file string
+}
+
+func (options *sourceOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&options.dir, "dir", ".", "directory source files are resolved against")
+	flags.StringVar(&options.dir, "d", ".", "shorthand for --dir")
+	flags.StringVar(&options.file, "file", "", "source file name (may also be given as an argument)")
+	flags.StringVar(&options.file, "f", "", "shorthand for --file")
+}
+
+// sources expands --file and the positional arguments, each a file, a directory, a glob
+// pattern or - for stdin, into source files. The returned exit code is exitOK on success.
+func (options *sourceOptions) sources(cmd *command, args []string) ([]*source, int) {
+	if options.file != "" {
+		args = append([]string{options.file}, args...)
+	}
+
+	if len(args) == 0 {
+		return nil, cmd.usageError("no source file given")
+	}
+
+	expander := sourceExpander{dir: options.dir, seen: make(map[string]bool)}
+
+	for _, arg := range args {
+		if err := expander.expand(arg); err != nil {
+			fmt.Fprintf(os.Stderr, "expressive %v: %v\n", cmd.name, err)
+			return nil, exitFailure
+		}
+	}
+
+	if len(expander.sources) == 0 {
+		fmt.Fprintf(os.Stderr, "expressive %v: no %v files found in %v\n", cmd.name, sourceFileExtension, strings.Join(args, " "))
+		return nil, exitFailure
+	}
+
+	return expander.sources, exitOK
+}
+
+// source is like sources, but requires the arguments to name exactly one source file
+func (options *sourceOptions) source(cmd *command, args []string) (*source, int) {
+	sources, code := options.sources(cmd, args)
+
+	if code != exitOK {
+		return nil, code
+	}
+
+	if len(sources) != 1 {
+		return nil, cmd.usageError("expecting one source file, but got %v", len(sources))
+	}
+
+	return sources[0], exitOK
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSourcePaths(t *testing.T) {
+	root := writeFiles(map[string]string{
+		"foo.exp":     "print \"root\\n\";\n",
+		"sub/foo.exp": "print \"sub\\n\";\n",
+		"sub/bar.exp": "print \"bar\\n\";\n",
+	}, t)
+	defer os.RemoveAll(root)
+
+	sub := filepath.Join(root, "sub")
+	outDir := filepath.Join(root, "out")
+
+	tests := []struct {
+		dir, arg     string
+		path, output string
+	}{
+		{sub, "bar.exp", filepath.Join(sub, "bar.exp"), filepath.Join(outDir, "bar.ll")},
+		{sub, filepath.Join(root, "foo.exp"), filepath.Join(root, "foo.exp"), filepath.Join(outDir, "foo.ll")},
+		{sub, filepath.Join("..", "foo.exp"), filepath.Join(root, "foo.exp"), filepath.Join(outDir, "foo.ll")},
+		{root, filepath.Join("sub", "..", "foo.exp"), filepath.Join(root, "foo.exp"), filepath.Join(outDir, "foo.ll")},
+		{root, filepath.Join("sub", "bar.exp"), filepath.Join(sub, "bar.exp"), filepath.Join(outDir, "sub", "bar.ll")},
+	}
+
+	for _, test := range tests {
+		options := sourceOptions{dir: test.dir}
+
+		sources, code := options.sources(newCheckCommand(), []string{test.arg})
+
+		if code != exitOK || len(sources) != 1 {
+			t.Errorf("Expecting %v in %v to be one source, got %v sources", test.arg, test.dir, len(sources))
+			continue
+		}
+
+		if path := sources[0].path(); path != test.path {
+			t.Errorf("Expecting %v in %v to be read from %v, got %v", test.arg, test.dir, test.path, path)
+		}
+
+		if output := sources[0].outputPath(outDir, ".ll"); output != test.output {
+			t.Errorf("Expecting %v in %v to be built into %v, got %v", test.arg, test.dir, test.output, output)
+		}
+	}
+}
+
+func TestRunsSourceOutsideOfDir(t *testing.T) {
+	root := writeFiles(map[string]string{"foo.exp": "print \"root\\n\";\n", "sub/foo.exp": "print \"sub\\n\";\n"}, t)
+	defer os.RemoveAll(root)
+
+	wd, err := os.Getwd()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Chdir(wd)
+
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{
+		{"run", "-d", "sub", filepath.Join("..", "foo.exp")},
+		{"run", "-d", "sub", filepath.Join(root, "foo.exp")},
+		{"run", "foo.exp"},
+	} {
+		if stdout, stderr, code := execute("", t, args...); code != exitOK || stdout != "root\n" {
+			t.Errorf("Expecting %q to run foo.exp of %v, got %v, %q and %q", args, root, code, stdout, stderr)
+		}
+	}
+
+	if _, stderr, code := execute("", t, "build", "-d", "sub", "--outDir", "out", filepath.Join("..", "foo.exp")); code != exitOK {
+		t.Fatalf("Expecting build to succeed, got %v and %q", code, stderr)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "out", "foo.ll")); err != nil {
+		t.Errorf("Expecting foo.ll to be written to out, got %v", err)
+	}
+}
+
+func TestBuildRefusesOutputClashes(t *testing.T) {
+	root := writeFiles(map[string]string{"a/foo.exp": "print \"a\\n\";\n", "b/foo.exp": "print \"b\\n\";\n", "dir/bar.exp": "print \"bar\\n\";\n"}, t)
+	defer os.RemoveAll(root)
+
+	outDir := filepath.Join(root, "out")
+	first, second := filepath.Join(root, "a", "foo.exp"), filepath.Join(root, "b", "foo.exp")
+
+	_, stderr, code := execute("", t, "build", "-d", filepath.Join(root, "dir"), "--outDir", outDir, first, second)
+	expected := "expressive build: " + first + " and " + second + " would both be built into " + filepath.Join(outDir, "foo.ll")
+
+	if code != exitFailure || !strings.Contains(stderr, expected) {
+		t.Errorf("Expecting build to fail with %q, got %v and %q", expected, code, stderr)
+	}
+
+	if _, err := os.Stat(outDir); !os.IsNotExist(err) {
+		t.Errorf("Expecting nothing to be built, got %v", err)
+	}
+
+	if _, stderr, code := execute("", t, "build", "-d", filepath.Join(root, "dir"), "--outDir", outDir, first, "bar.exp"); code != exitOK {
+		t.Errorf("Expecting sources of different names to be built, got %v and %q", code, stderr)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/bytecode"
+	"github.com/carlcui/expressive/codegen"
+	"github.com/carlcui/expressive/codegen/c"
+	"github.com/carlcui/expressive/codegen/js"
+	"github.com/carlcui/expressive/codegen/wat"
+	"github.com/carlcui/expressive/logger"
+)
+
+// target is an output format of the build command
+type target struct {
+	name      string
+	extension string
+	generate  func(root ast.Node, logger logger.Logger) string // errors are logged
+
+	// generateWithOptions can also describe the program for debuggers and run passes over the
+	// generated code, nil when the target cannot
+	generateWithOptions func(root ast.Node, options codegen.Options, logger logger.Logger) string
+}
+
+var targets = []*target{
+	{"llvm", ".ll", codegen.Generate, codegen.GenerateWithOptions},
+	{"bytecode", bytecode.FileExtension, generateBytecode, nil},
+	{"c", ".c", c.Generate, nil},
+	{"wat", ".wat", wat.Generate, nil},
+	{"js", ".js", js.Generate, nil},
+}
+
+func findTarget(name string) *target {
+	for _, t := range targets {
+		if t.name == name {
+			return t
+		}
+	}
+
+	return nil
+}
+
+func targetNames() string {
+	names := make([]string, len(targets))
+
+	for i, t := range targets {
+		names[i] = t.name
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func generateBytecode(root ast.Node, logger logger.Logger) string {
+	program := bytecode.Compile(root, logger)
+
+	if program == nil {
+		return ""
+	}
+
+	var out bytes.Buffer
+
+	if err := bytecode.Write(&out, program); err != nil {
+		logger.Log(root.GetLocation(), err.Error())
+	}
+
+	return out.String()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/carlcui/expressive/logger"
+	"github.com/carlcui/expressive/token"
+)
+
+func newTokensCommand() *command {
+	cmd := newCommand("tokens", "[options] <file|->", "Print the token stream produced by the scanner, comments included.")
+
+	var options sourceOptions
+	options.register(cmd.flags)
+
+	cmd.run = func(cmd *command, args []string) int {
+		src, code := options.source(cmd, args)
+
+		if code != exitOK {
+			return code
+		}
+
+		var scannerLogger logger.StdError
+
+		s := newScanner(src, &scannerLogger)
+
+		if s == nil {
+			return exitFailure
+		}
+
+		for tok := s.Next(); ; tok = s.Next() {
+			fmt.Printf("%v: %v\n", tok.GetLocation(), tok)
+
+			if tok.TokenType == token.ILLEGAL {
+				scannerLogger.Log(tok.GetLocation(), "illegal token \""+tok.Raw+"\"")
+			} else if tok.Error != "" {
+				scannerLogger.Log(tok.GetErrorLocation(), tok.Error)
+			}
+
+			if tok.TokenType == token.EOF {
+				break
+			}
+		}
+
+		if scannerLogger.ErrorsCount() > 0 {
+			return exitFailure
+		}
+
+		return exitOK
+	}
+
+	return cmd
+}
@@ -0,0 +1,103 @@
+package c
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/host"
+	"github.com/carlcui/expressive/internal/testutil"
+	"github.com/carlcui/expressive/logger"
+	"github.com/carlcui/expressive/typing"
+)
+
+// testGolden compares the C generated for every program of dirName with the .c file of the
+// same name in expectedDirName
+func testGolden(dirName string, expectedDirName string, t *testing.T) {
+	testutil.Golden(dirName, expectedDirName, func(root ast.Node, fileName string) map[string]string {
+		var buffer logger.Buffer
+
+		actual := Generate(root, &buffer)
+
+		if buffer.ErrorsCount() > 0 {
+			t.Errorf("File %v: error(s): %v", fileName, buffer.Messages())
+		}
+
+		return map[string]string{".c": actual}
+	}, t)
+}
+
+func TestE2ePrograms(t *testing.T) {
+	testGolden("../../e2e", "./testFiles/e2e", t)
+}
+
+func TestPrograms(t *testing.T) {
+	testGolden("./testFiles", "./testFiles", t)
+}
+
+func TestStringLiteral(t *testing.T) {
+	cases := map[string]string{
+		"plain":         `"plain"`,
+		"a\"b\\c\n":     `"a\"b\\c\n"`,
+		"\x01" + "7":    `"\0017"`,
+		"what??/":       `"what?\?/"`,
+		"tab\there\x7f": `"tab\there\177"`,
+	}
+
+	for s, expected := range cases {
+		if actual := stringLiteral(s); actual != expected {
+			t.Errorf("Expecting %q to be quoted as %v, got %v", s, expected, actual)
+		}
+	}
+}
+
+func TestPrototype(t *testing.T) {
+	cases := []struct {
+		functionType *typing.FunctionType
+		expected     string
+	}{
+		{typing.NewFunctionType(typing.INT, typing.STRING), "int32_t f(const char *);"},
+		{typing.NewFunctionType(typing.VOID), "void f(void);"},
+		{typing.NewVariadicFunctionType(typing.FLOAT, typing.BOOL), "double f(bool, ...);"},
+		{typing.NewVariadicFunctionType(typing.STRING), "const char *f();"},
+	}
+
+	for _, c := range cases {
+		if actual := prototype(c.functionType, "f"); actual != c.expected {
+			t.Errorf("Expecting %v to be declared as %v, got %v", c.functionType, c.expected, actual)
+		}
+	}
+}
+
+func TestFunctionErrors(t *testing.T) {
+	square := &host.Function{Name: "square", Params: []typing.Typing{typing.INT}, Result: typing.INT, Call: func(args []host.Value) (host.Value, error) {
+		return args[0], nil
+	}}
+
+	cases := []struct {
+		src      string
+		expected []string
+	}{
+		{"extern func printf(format: string, ...) -> int;\nprintf(\"%d\", 1);", nil},
+		{"let a = 1;\nextern func strlen(s: string) -> int;", []string{`at 23: function "strlen" clashes with a function of the generated code`}},
+		{"extern func printf(format: string) -> int;", []string{`at 12: function "printf" clashes with a function of the generated code`}},
+		{"extern func expressive_add(a: int, b: int) -> int;", []string{`at 12: function "expressive_add" clashes with a function of the generated code`}},
+		{"extern func register(a: int);\nregister(1);", []string{`at 12: function "register" clashes with a keyword of C`}},
+		{"extern func fflush(stream: string) -> int;", []string{`at 12: function "fflush" clashes with a function of stdio.h`}},
+		{"extern func srand(seed: int);\nsrand(1);", nil},
+		{"extern func srand(seed: int);\nprint \"a\" + \"b\";", []string{`at 12: function "srand" clashes with a function of stdlib.h`}},
+		{"extern func sqrt(x: float) -> float;\nprint \"%f\", sqrt(2.0) ^^ 2.0;", nil},
+		{"extern func sqrtf(x: float) -> float;\nprint \"%f\", sqrtf(2.0) ^^ 2.0;", []string{`at 12: function "sqrtf" clashes with a function of math.h`}},
+		{"let a = 1;\nsquare(a);\nprint \"%d\", square(2);", []string{"at 11: host functions are not supported by the C target"}},
+	}
+
+	for _, c := range cases {
+		var buffer logger.Buffer
+
+		actual := Generate(testutil.AnalyzeSource(c.src, []*host.Function{square}, t), &buffer)
+
+		if fmt.Sprint(buffer.Messages()) != fmt.Sprint(c.expected) || (actual == "") != (len(c.expected) > 0) {
+			t.Errorf("Expecting %q to report %v, got %v", c.src, c.expected, buffer.Messages())
+		}
+	}
+}
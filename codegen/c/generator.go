@@ -0,0 +1,613 @@
+package c
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/logger"
+	"github.com/carlcui/expressive/signature"
+	"github.com/carlcui/expressive/symbolTable"
+	"github.com/carlcui/expressive/typing"
+)
+
+const indentation = "    "
+
+// Generator translates an analysed ast into a C99 program
+type Generator struct {
+	body      strings.Builder // statements of main
+	indent    int
+	names     map[*symbolTable.Binding]string
+	usedNames map[string]bool
+	switches  int // number of switch statements generated, numbering their labels
+	breaks    []*breakTarget
+	helpers   [HELPER_COUNT]bool
+	headers   [HEADER_COUNT]bool
+
+	prototypes  []string          // the prototypes of the extern functions, in order of declaration
+	externs     []*ast.ExternNode // the extern functions declared, checked against the headers included
+	unsupported ast.Node          // the first call of a host function, nil if there is none
+
+	logger logger.Logger
+}
+
+// breakTarget is where the breaks of a loop or switch jump to
+type breakTarget struct {
+	label string // "" for loops, which use C's break
+	used  bool
+}
+
+func (generator *Generator) Init(logger logger.Logger) {
+	generator.logger = logger
+	generator.names = make(map[*symbolTable.Binding]string)
+	generator.usedNames = make(map[string]bool)
+	generator.headers[stdboolHeader] = true
+	generator.headers[stdintHeader] = true
+	generator.headers[stdioHeader] = true
+}
+
+// binaryOperators are the C operators for operators that apply directly to C values
+var binaryOperators = map[signature.Operator]string{
+	signature.DIVIDE:            "/",
+	signature.MODULO:            "%",
+	signature.LOGIC_AND:         "&&",
+	signature.LOGIC_OR:          "||",
+	signature.GREATER:           ">",
+	signature.GREATER_OR_EQUAL:  ">=",
+	signature.LESS:              "<",
+	signature.LESS_OR_EQUAL:     "<=",
+	signature.SHALLOW_EQUAL:     "==",
+	signature.DEEP_EQUAL:        "==",
+	signature.SHALLOW_NOT_EQUAL: "!=",
+	signature.DEEP_NOT_EQUAL:    "!=",
+}
+
+// floatOperators are the C operators for float operators that ints implement with helpers
+var floatOperators = map[signature.Operator]string{
+	signature.ADD:      "+",
+	signature.SUBTRACT: "-",
+	signature.MULTIPLY: "*",
+}
+
+var intHelpers = map[signature.Operator]helper{
+	signature.ADD:          addInt,
+	signature.SUBTRACT:     subtractInt,
+	signature.MULTIPLY:     multiplyInt,
+	signature.EXPONENTIATE: powInt,
+}
+
+// String returns the whole translation unit: includes, the helpers used, the prototypes of the
+// extern functions, then main
+func (generator *Generator) String() string {
+	var unit strings.Builder
+
+	for h, used := range generator.headers {
+		if used {
+			fmt.Fprintf(&unit, "#include <%v>\n", headerNames[h])
+		}
+	}
+
+	for h, used := range generator.helpers {
+		if used {
+			unit.WriteString("\n")
+			unit.WriteString(helperDefinitions[h])
+		}
+	}
+
+	if len(generator.prototypes) > 0 {
+		unit.WriteString("\n")
+	}
+
+	for _, prototype := range generator.prototypes {
+		unit.WriteString(prototype + "\n")
+	}
+
+	unit.WriteString("\nint main(void) {\n")
+	unit.WriteString(generator.body.String())
+	unit.WriteString(indentation + "return 0;\n}\n")
+
+	return unit.String()
+}
+
+func (generator *Generator) generateStmts(stmts []ast.Node) {
+	for _, stmt := range stmts {
+		generator.generateStmt(stmt)
+	}
+}
+
+func (generator *Generator) generateStmt(node ast.Node) {
+	switch stmt := node.(type) {
+	case *ast.ProgramNode:
+		// C calls extern functions by their own names, which variables cannot shadow
+		for _, child := range stmt.Chilren {
+			if extern, ok := child.(*ast.ExternNode); ok {
+				generator.usedNames[extern.Name()] = true
+			}
+		}
+
+		generator.indent++
+		generator.generateStmts(stmt.Chilren)
+		generator.indent--
+	case *ast.BlockNode:
+		generator.generateBlock(stmt)
+	case *ast.VariableDeclarationNode:
+		generator.line(generator.declaration(stmt) + ";")
+	case *ast.AssignmentNode, *ast.IncDecNode:
+		generator.line(generator.simpleStmt(stmt) + ";")
+	case *ast.PrintNode:
+		args := []string{generator.expr(stmt.StringExpr)}
+
+		for _, arg := range stmt.Args {
+			args = append(args, generator.expr(arg))
+		}
+
+		generator.line(fmt.Sprintf("printf(%v);", strings.Join(args, ", ")))
+	case *ast.IfStmtNode:
+		for i, conditionExpr := range stmt.ConditionExprs {
+			keyword := "if"
+
+			if i > 0 {
+				keyword = "} else if"
+			}
+
+			generator.line(fmt.Sprintf("%v (%v) {", keyword, generator.expr(conditionExpr)))
+			generator.generateBlockBody(stmt.ConditionBlocks[i])
+		}
+
+		if stmt.ElseBlock != nil {
+			generator.line("} else {")
+			generator.generateBlockBody(stmt.ElseBlock)
+		}
+
+		generator.line("}")
+	case *ast.WhileStmtNode:
+		generator.line(fmt.Sprintf("while (%v) {", generator.expr(stmt.ConditionExpr)))
+		generator.generateBreakable("", func() {
+			generator.generateBlockBody(stmt.Block)
+		})
+		generator.line("}")
+	case *ast.ForStmtNode:
+		var initialization, condition, iteration string
+
+		if stmt.InitializationStmt != nil {
+			initialization = generator.simpleStmt(stmt.InitializationStmt)
+		}
+
+		if stmt.ConditionExpr != nil {
+			condition = " " + generator.expr(stmt.ConditionExpr)
+		}
+
+		if stmt.IterationStmt != nil {
+			iteration = " " + generator.simpleStmt(stmt.IterationStmt)
+		}
+
+		generator.line(fmt.Sprintf("for (%v;%v;%v) {", initialization, condition, iteration))
+		generator.generateBreakable("", func() {
+			generator.generateBlockBody(stmt.Block)
+		})
+		generator.line("}")
+	case *ast.SwitchStmtNode:
+		generator.generateSwitchStmt(stmt)
+	case *ast.BreakNode:
+		target := generator.breaks[len(generator.breaks)-1]
+		target.used = true
+
+		if target.label == "" {
+			generator.line("break;")
+		} else {
+			generator.line(fmt.Sprintf("goto %v;", target.label))
+		}
+	case *ast.CallNode:
+		generator.line(generator.call(stmt) + ";")
+	case *ast.ExternNode:
+		generator.declareFunction(stmt)
+	default:
+		panic(fmt.Sprintf("%v: unexpected statement %T", node.GetLocation(), node))
+	}
+}
+
+// generateSwitchStmt lowers a switch to comparisons jumping to labelled case blocks. Case
+// expressions need not be constant and may be strings, so C's switch cannot be used. Blocks
+// are laid out in order, so a block without break falls through to the next one.
+//
+//	{
+//	    int32_t expressive_switch_0_test = a;
+//	    if (expressive_switch_0_test == 1) goto switch_0_case_0;
+//	    goto switch_0_default;
+//	switch_0_case_0:
+//	    { ... goto switch_0_end; }
+//	switch_0_default:
+//	    { ... }
+//	}
+//	switch_0_end:;
+func (generator *Generator) generateSwitchStmt(node *ast.SwitchStmtNode) {
+	prefix := fmt.Sprintf("switch_%d_", generator.switches)
+	generator.switches++
+
+	test := "expressive_" + prefix + "test"
+	testTyping := node.TestExpr.GetTyping()
+
+	generator.line("{")
+	generator.indent++
+
+	generator.line(fmt.Sprintf("%v = %v;", declaration(testTyping, test), generator.expr(node.TestExpr)))
+
+	for i, caseExpr := range node.CaseExprs {
+		condition := generator.equality(signature.SHALLOW_EQUAL, testTyping, test, generator.expr(caseExpr))
+		generator.line(fmt.Sprintf("if (%v) goto %vcase_%d;", condition, prefix, i))
+	}
+
+	generator.line(fmt.Sprintf("goto %vdefault;", prefix))
+
+	broken := generator.generateBreakable(prefix+"end", func() {
+		for i, caseBlock := range node.CaseBlocks {
+			generator.label(fmt.Sprintf("%vcase_%d", prefix, i), caseBlock)
+		}
+
+		generator.label(prefix+"default", node.DefaultBlock)
+	})
+
+	generator.indent--
+	generator.line("}")
+
+	if broken {
+		generator.indent--
+		generator.line(prefix + "end:;")
+		generator.indent++
+	}
+}
+
+// label writes a labelled block, or the label alone when the block is empty
+func (generator *Generator) label(label string, block ast.Node) {
+	generator.indent--
+
+	if block == nil || block.(*ast.BlockNode).IsEmptyBlock() {
+		generator.line(label + ":;")
+		generator.indent++
+		return
+	}
+
+	generator.line(label + ":")
+	generator.indent++
+
+	generator.generateStmt(block)
+}
+
+// generateBreakable generates the body of a loop or switch, whose breaks jump to label, or use
+// C's break when label is "". It tells if the body breaks.
+func (generator *Generator) generateBreakable(label string, generateBody func()) bool {
+	target := &breakTarget{label: label}
+	generator.breaks = append(generator.breaks, target)
+
+	generateBody()
+
+	generator.breaks = generator.breaks[:len(generator.breaks)-1]
+
+	return target.used
+}
+
+func (generator *Generator) generateBlock(node *ast.BlockNode) {
+	generator.line("{")
+	generator.generateBlockBody(node)
+	generator.line("}")
+}
+
+// generateBlockBody generates the statements of a block, without braces
+func (generator *Generator) generateBlockBody(node ast.Node) {
+	generator.indent++
+	generator.generateStmts(node.(*ast.BlockNode).Stmts)
+	generator.indent--
+}
+
+// simpleStmt returns a statement that can appear in a for header, without semicolon
+func (generator *Generator) simpleStmt(node ast.Node) string {
+	switch stmt := node.(type) {
+	case *ast.VariableDeclarationNode:
+		return generator.declaration(stmt)
+	case *ast.AssignmentNode:
+		identifier := stmt.LHS.(*ast.IdentifierNode)
+		name := generator.name(identifier)
+		rhs := generator.expr(stmt.RHS)
+
+		if stmt.Operator == signature.VOID_OPERATOR {
+			return fmt.Sprintf("%v = %v", name, rhs)
+		}
+
+		return fmt.Sprintf("%v = %v", name, generator.operation(stmt.Operator, identifier.GetTyping(), name, rhs))
+	case *ast.IncDecNode:
+		name := generator.name(stmt.LHS.(*ast.IdentifierNode))
+		operator := signature.SUBTRACT
+
+		if stmt.IsIncrement {
+			operator = signature.ADD
+		}
+
+		return fmt.Sprintf("%v = %v", name, generator.operation(operator, typing.INT, name, "1"))
+	default:
+		panic(fmt.Sprintf("%v: unexpected statement %T", node.GetLocation(), node))
+	}
+}
+
+func (generator *Generator) declaration(node *ast.VariableDeclarationNode) string {
+	identifier := node.Identifier.(*ast.IdentifierNode)
+	identifierTyping := identifier.GetTyping()
+
+	name := generator.declareName(identifier)
+	declaration := declaration(identifierTyping, name)
+
+	if node.Expr == nil {
+		// Semantic analysis makes sure the variable is assigned before being read. The default
+		// value keeps the memory well-defined anyway.
+		return fmt.Sprintf("%v = %v", declaration, defaultValue(identifier))
+	}
+
+	if !identifier.GetBinding().IsVariable {
+		declaration = "const " + declaration
+	}
+
+	return fmt.Sprintf("%v = %v", declaration, generator.expr(node.Expr))
+}
+
+// defaultValue is the value of a variable declared without initializer
+func defaultValue(identifier *ast.IdentifierNode) string {
+	switch identifier.GetTyping() {
+	case typing.INT:
+		return "0"
+	case typing.FLOAT:
+		return "0.0"
+	case typing.BOOL:
+		return "false"
+	case typing.CHAR, typing.STRING:
+		return `""`
+	default:
+		panic(fmt.Sprintf("%v: no default value for type %v", identifier.GetLocation(), identifier.GetTyping()))
+	}
+}
+
+// declareName chooses the C name of a variable: its own name, unless it is reserved or taken by
+// another variable. Shadowing variables are renamed too, since in C the scope of a variable
+// starts within its own initializer.
+func (generator *Generator) declareName(identifier *ast.IdentifierNode) string {
+	base := identifier.Tok.Raw
+
+	// generated names start with expressive_ and never end with _, so they cannot clash
+	if isReserved(base) || strings.HasPrefix(base, "expressive_") {
+		base += "_"
+	}
+
+	name := base
+
+	for i := 2; generator.usedNames[name]; i++ {
+		name = fmt.Sprintf("%v_%d", base, i)
+	}
+
+	generator.usedNames[name] = true
+	generator.names[identifier.GetBinding()] = name
+
+	return name
+}
+
+func (generator *Generator) name(identifier *ast.IdentifierNode) string {
+	name, ok := generator.names[identifier.GetBinding()]
+
+	if !ok {
+		panic(fmt.Sprintf("%v: variable %v is not declared", identifier.GetLocation(), identifier.Tok.Raw))
+	}
+
+	return name
+}
+
+// expr returns the C expression of node. Binary operations are parenthesized, so that the
+// structure of the ast does not depend on C precedence.
+func (generator *Generator) expr(node ast.Node) string {
+	switch expr := node.(type) {
+	case *ast.IntegerNode:
+		return intLiteral(expr.Val)
+	case *ast.FloatNode:
+		return floatLiteral(float64(expr.Val))
+	case *ast.BooleanNode:
+		return strconv.FormatBool(expr.Val)
+	case *ast.CharacterNode:
+		return stringLiteral(string(expr.Val))
+	case *ast.StringNode:
+		stringValue := expr.StringValue()
+		return stringLiteral(stringValue[:len(stringValue)-1]) // without terminating character
+	case *ast.IdentifierNode:
+		return generator.name(expr)
+	case *ast.UnaryOperatorNode:
+		operand := generator.expr(expr.Expr)
+
+		switch {
+		case expr.Operator == signature.LOGIC_NOT:
+			return "!" + operand
+		case expr.Operator == signature.NEGATE && expr.GetTyping() == typing.INT:
+			return generator.callHelper(subtractInt, "0", operand)
+		case expr.Operator == signature.NEGATE && expr.GetTyping() == typing.FLOAT:
+			return "-(" + operand + ")"
+		}
+
+		panic(fmt.Sprintf("%v: cannot generate %v", node.GetLocation(), expr.Operator))
+	case *ast.BinaryOperatorNode:
+		return generator.operation(expr.Operator, expr.Lhs.GetTyping(), generator.expr(expr.Lhs), generator.expr(expr.Rhs))
+	case *ast.TernaryOperatorNode:
+		return fmt.Sprintf("(%v ? %v : %v)", generator.expr(expr.Expr1), generator.expr(expr.Expr2), generator.expr(expr.Expr3))
+	case *ast.CallNode:
+		return generator.call(expr)
+	default:
+		panic(fmt.Sprintf("%v: unexpected expression %T", node.GetLocation(), node))
+	}
+}
+
+// operation applies a binary operator to C expressions of the given typing
+func (generator *Generator) operation(operator signature.Operator, operandTyping typing.Typing, lhs string, rhs string) string {
+	if h, ok := intHelpers[operator]; ok && operandTyping == typing.INT {
+		return generator.callHelper(h, lhs, rhs)
+	}
+
+	switch {
+	case operandTyping == typing.STRING && operator == signature.ADD:
+		return generator.callHelper(concat, lhs, rhs)
+	case operandTyping == typing.FLOAT && operator == signature.EXPONENTIATE:
+		generator.headers[mathHeader] = true
+		return fmt.Sprintf("pow(%v, %v)", lhs, rhs)
+	case operandTyping == typing.FLOAT && floatOperators[operator] != "":
+		return fmt.Sprintf("(%v %v %v)", lhs, floatOperators[operator], rhs)
+	}
+
+	switch operator {
+	case signature.SHALLOW_EQUAL, signature.DEEP_EQUAL, signature.SHALLOW_NOT_EQUAL, signature.DEEP_NOT_EQUAL:
+		return generator.equality(operator, operandTyping, lhs, rhs)
+	}
+
+	if cOperator, ok := binaryOperators[operator]; ok {
+		return fmt.Sprintf("(%v %v %v)", lhs, cOperator, rhs)
+	}
+
+	panic(fmt.Sprintf("cannot generate %v on %v", operator, operandTyping))
+}
+
+// equality compares values, including strings and chars
+func (generator *Generator) equality(operator signature.Operator, operandTyping typing.Typing, lhs string, rhs string) string {
+	cOperator := binaryOperators[operator]
+
+	if operandTyping == typing.STRING || operandTyping == typing.CHAR {
+		generator.headers[stringHeader] = true
+		return fmt.Sprintf("(strcmp(%v, %v) %v 0)", lhs, rhs, cOperator)
+	}
+
+	return fmt.Sprintf("(%v %v %v)", lhs, cOperator, rhs)
+}
+
+func (generator *Generator) callHelper(h helper, args ...string) string {
+	generator.helpers[h] = true
+
+	for _, header := range helperHeaders[h] {
+		generator.headers[header] = true
+	}
+
+	return fmt.Sprintf("%v(%v)", helperNames[h], strings.Join(args, ", "))
+}
+
+// declareFunction declares an extern function with its prototype. C calls it by its own name,
+// which is its symbol for the linker, so the name must neither be a keyword nor name another
+// function of the generated code.
+func (generator *Generator) declareFunction(node *ast.ExternNode) {
+	identifier := node.Identifier.(*ast.IdentifierNode)
+	name := node.Name()
+	prototype := prototype(identifier.GetTyping().(*typing.FunctionType), name)
+
+	generator.names[identifier.GetBinding()] = name
+
+	if keywords[name] {
+		generator.logger.Log(identifier.GetLocation(), "function \""+name+"\" clashes with a keyword of C")
+		return
+	}
+
+	if libraryPrototype, ok := libraryFunctions[name]; ok && libraryPrototype != prototype || strings.HasPrefix(name, "expressive_") {
+		generator.logger.Log(identifier.GetLocation(), "function \""+name+"\" clashes with a function of the generated code")
+		return
+	}
+
+	generator.prototypes = append(generator.prototypes, prototype)
+	generator.externs = append(generator.externs, node)
+}
+
+// checkHeaders reports the extern functions that a header included by the generated code declares
+// with another prototype. It runs once the program is generated, when the headers are known.
+func (generator *Generator) checkHeaders() {
+	for _, node := range generator.externs {
+		identifier := node.Identifier.(*ast.IdentifierNode)
+		prototype := prototype(identifier.GetTyping().(*typing.FunctionType), node.Name())
+
+		for h, included := range generator.headers {
+			if headerPrototype, ok := headerFunctions[h][node.Name()]; included && ok && headerPrototype != prototype {
+				generator.logger.Log(identifier.GetLocation(), "function \""+node.Name()+"\" clashes with a function of "+headerNames[h])
+				break
+			}
+		}
+	}
+}
+
+// call returns the C call of an extern function. The functions of the host cannot be called from
+// C, so their calls are recorded as unsupported.
+func (generator *Generator) call(node *ast.CallNode) string {
+	name, ok := generator.names[node.Callee.(*ast.IdentifierNode).GetBinding()]
+
+	if !ok {
+		if generator.unsupported == nil {
+			generator.unsupported = node
+		}
+
+		return "0"
+	}
+
+	args := make([]string, len(node.Args))
+
+	for i, arg := range node.Args {
+		args[i] = generator.expr(arg)
+	}
+
+	return fmt.Sprintf("%v(%v)", name, strings.Join(args, ", "))
+}
+
+func (generator *Generator) line(line string) {
+	generator.body.WriteString(strings.Repeat(indentation, generator.indent))
+	generator.body.WriteString(line)
+	generator.body.WriteString("\n")
+}
+
+// intLiteral formats an int so that C reads it as an int: the smallest int is the negation of
+// 2147483648, which does not fit in an int
+func intLiteral(value int) string {
+	if value == math.MinInt32 {
+		return "(-2147483647 - 1)"
+	}
+
+	return strconv.Itoa(value)
+}
+
+// floatLiteral formats a double so that C reads it back exactly and as a double
+func floatLiteral(value float64) string {
+	literal := strconv.FormatFloat(value, 'g', -1, 64)
+
+	if !strings.ContainsAny(literal, ".e") {
+		literal += ".0"
+	}
+
+	return literal
+}
+
+// stringLiteral quotes s as a C string literal. Octal escapes are used for other control
+// characters, since hexadecimal escapes would swallow following hex digits, and ? is escaped
+// after ? to avoid trigraphs.
+func stringLiteral(s string) string {
+	var literal strings.Builder
+
+	literal.WriteByte('"')
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case c == '"' || c == '\\':
+			literal.WriteByte('\\')
+			literal.WriteByte(c)
+		case c == '\n':
+			literal.WriteString(`\n`)
+		case c == '\t':
+			literal.WriteString(`\t`)
+		case c == '?' && i > 0 && s[i-1] == '?':
+			literal.WriteString(`\?`)
+		case c < ' ' || c == 0x7f:
+			fmt.Fprintf(&literal, "\\%03o", c)
+		default:
+			literal.WriteByte(c)
+		}
+	}
+
+	literal.WriteByte('"')
+
+	return literal.String()
+}
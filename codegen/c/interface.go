@@ -0,0 +1,28 @@
+package c
+
+import (
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/logger"
+)
+
+// Generate C99 source for ast. The program only needs a hosted C99 implementation, and the
+// extern functions it declares, for the linker to resolve against the C library or object files.
+func Generate(node ast.Node, logger logger.Logger) string {
+	var generator Generator
+	generator.Init(logger)
+
+	errorsCount := logger.ErrorsCount()
+
+	generator.generateStmt(node)
+	generator.checkHeaders()
+
+	if generator.unsupported != nil {
+		logger.Log(generator.unsupported.GetLocation(), "host functions are not supported by the C target")
+	}
+
+	if logger.ErrorsCount() > errorsCount {
+		return ""
+	}
+
+	return generator.String()
+}
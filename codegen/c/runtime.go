@@ -0,0 +1,186 @@
+package c
+
+// helper is a C function emitted before main when the program needs it
+type helper int
+
+const (
+	addInt helper = iota
+	subtractInt
+	multiplyInt
+	powInt
+	concat
+	HELPER_COUNT
+)
+
+var helperNames = [...]string{
+	addInt:      "expressive_add",
+	subtractInt: "expressive_subtract",
+	multiplyInt: "expressive_multiply",
+	powInt:      "expressive_pow",
+	concat:      "expressive_concat",
+}
+
+// ints wrap around at 32 bits, as in the llvm backend. Signed overflow is undefined in C, so the
+// arithmetic is done on unsigned ints.
+var helperDefinitions = [...]string{
+	addInt: `static int32_t expressive_add(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a + (uint32_t)b);
+}
+`,
+	subtractInt: `static int32_t expressive_subtract(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a - (uint32_t)b);
+}
+`,
+	multiplyInt: `static int32_t expressive_multiply(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a * (uint32_t)b);
+}
+`,
+	powInt: `static int32_t expressive_pow(int32_t base, int32_t exponent) {
+    uint32_t result = 1;
+    uint32_t factor = (uint32_t)base;
+
+    if (exponent < 0) {
+        return base == 1 ? 1 : base == -1 ? (exponent % 2 == 0 ? 1 : -1) : 0;
+    }
+
+    for (; exponent > 0; exponent >>= 1) {
+        if (exponent & 1) {
+            result *= factor;
+        }
+
+        factor *= factor;
+    }
+
+    return (int32_t)result;
+}
+`,
+	concat: `static const char *expressive_concat(const char *a, const char *b) {
+    size_t lengthA = strlen(a);
+    size_t lengthB = strlen(b);
+    char *result = malloc(lengthA + lengthB + 1);
+
+    if (result == NULL) {
+        abort();
+    }
+
+    memcpy(result, a, lengthA);
+    memcpy(result + lengthA, b, lengthB + 1);
+
+    return result;
+}
+`,
+}
+
+// header is a standard header included when the program needs it
+type header int
+
+const (
+	stdboolHeader header = iota
+	stdintHeader
+	stdioHeader
+	stdlibHeader
+	stringHeader
+	mathHeader
+	HEADER_COUNT
+)
+
+var headerNames = [...]string{
+	stdboolHeader: "stdbool.h",
+	stdintHeader:  "stdint.h",
+	stdioHeader:   "stdio.h",
+	stdlibHeader:  "stdlib.h",
+	stringHeader:  "string.h",
+	mathHeader:    "math.h",
+}
+
+// headerFunctions are the functions each header declares, by the prototype a program may declare
+// them with too, or "" for those it cannot declare the same way, e.g. taking a FILE * or a size_t
+var headerFunctions = [...]map[string]string{
+	stdioHeader: {
+		"remove": "int32_t remove(const char *);", "rename": "int32_t rename(const char *, const char *);",
+		"tmpfile": "", "tmpnam": "", "fclose": "", "fflush": "", "fopen": "", "freopen": "", "setbuf": "",
+		"setvbuf": "", "fprintf": "", "fscanf": "", "printf": "int32_t printf(const char *, ...);",
+		"scanf": "int32_t scanf(const char *, ...);", "snprintf": "", "sprintf": "",
+		"sscanf": "int32_t sscanf(const char *, const char *, ...);", "vfprintf": "", "vfscanf": "",
+		"vprintf": "", "vscanf": "", "vsnprintf": "", "vsprintf": "", "vsscanf": "", "fgetc": "",
+		"fgets": "", "fputc": "", "fputs": "", "getc": "", "getchar": "int32_t getchar(void);",
+		"gets": "", "putc": "", "putchar": "int32_t putchar(int32_t);", "puts": "int32_t puts(const char *);",
+		"ungetc": "", "fread": "", "fwrite": "", "fgetpos": "", "fseek": "", "fsetpos": "", "ftell": "",
+		"rewind": "", "clearerr": "", "feof": "", "ferror": "", "perror": "void perror(const char *);",
+	},
+	stdlibHeader: {
+		"atof": "double atof(const char *);", "atoi": "int32_t atoi(const char *);", "atol": "",
+		"atoll": "", "strtod": "", "strtof": "", "strtold": "", "strtol": "", "strtoll": "",
+		"strtoul": "", "strtoull": "", "rand": "int32_t rand(void);", "srand": "", "calloc": "",
+		"free": "", "malloc": "", "realloc": "", "abort": "void abort(void);", "atexit": "",
+		"exit": "void exit(int32_t);", "_Exit": "void _Exit(int32_t);", "getenv": "",
+		"system": "int32_t system(const char *);", "bsearch": "", "qsort": "",
+		"abs": "int32_t abs(int32_t);", "labs": "", "llabs": "", "div": "", "ldiv": "", "lldiv": "",
+		"mblen": "", "mbtowc": "", "wctomb": "", "mbstowcs": "", "wcstombs": "",
+	},
+	stringHeader: {
+		"memcpy": "", "memmove": "", "strcpy": "", "strncpy": "", "strcat": "", "strncat": "",
+		"memcmp": "", "strcmp": "int32_t strcmp(const char *, const char *);",
+		"strcoll": "int32_t strcoll(const char *, const char *);", "strncmp": "", "strxfrm": "",
+		"memchr": "", "strchr": "", "strcspn": "", "strpbrk": "", "strrchr": "", "strspn": "",
+		"strstr": "", "strtok": "", "memset": "", "strerror": "", "strlen": "",
+	},
+	mathHeader: mathFunctions(),
+}
+
+// mathFunctions are the functions and function-like macros of math.h. Each function of doubles
+// has variants of float and long double suffixed with f and l, which a program cannot declare.
+func mathFunctions() map[string]string {
+	// the parameters of the functions returning a double that a program can declare
+	params := map[string]string{
+		"atan2": "double, double", "hypot": "double, double", "pow": "double, double",
+		"fmod": "double, double", "remainder": "double, double", "copysign": "double, double",
+		"nextafter": "double, double", "fdim": "double, double", "fmax": "double, double",
+		"fmin": "double, double", "fma": "double, double, double", "ldexp": "double, int32_t",
+		"scalbn": "double, int32_t", "nan": "const char *",
+	}
+
+	for _, name := range []string{
+		"acos", "asin", "atan", "cos", "sin", "tan", "acosh", "asinh", "atanh", "cosh", "sinh",
+		"tanh", "exp", "exp2", "expm1", "log", "log10", "log1p", "log2", "logb", "cbrt", "fabs",
+		"sqrt", "erf", "erfc", "lgamma", "tgamma", "ceil", "floor", "nearbyint", "rint", "round",
+		"trunc",
+	} {
+		params[name] = "double"
+	}
+
+	functions := map[string]string{"ilogb": "int32_t ilogb(double);"}
+
+	for name, params := range params {
+		functions[name] = "double " + name + "(" + params + ");"
+	}
+
+	for _, name := range []string{"frexp", "modf", "scalbln", "lrint", "llrint", "lround", "llround", "remquo", "nexttoward"} {
+		functions[name] = ""
+	}
+
+	names := make([]string, 0, len(functions))
+
+	for name := range functions {
+		names = append(names, name)
+	}
+
+	for _, name := range names {
+		functions[name+"f"] = ""
+		functions[name+"l"] = ""
+	}
+
+	for _, macro := range []string{
+		"fpclassify", "isfinite", "isinf", "isnan", "isnormal", "signbit", "isgreater",
+		"isgreaterequal", "isless", "islessequal", "islessgreater", "isunordered",
+	} {
+		functions[macro] = ""
+	}
+
+	return functions
+}
+
+// helperHeaders are the headers each helper needs, besides stdint.h
+var helperHeaders = map[helper][]header{
+	concat: {stdlibHeader, stringHeader},
+}
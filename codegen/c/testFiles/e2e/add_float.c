@@ -0,0 +1,9 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    double a = (1.100000023841858 + 2.200000047683716);
+    printf("1.1 + 2.2 = %.1f\n", a);
+    return 0;
+}
@@ -0,0 +1,13 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+static int32_t expressive_add(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a + (uint32_t)b);
+}
+
+int main(void) {
+    int32_t a = expressive_add(5, 6);
+    printf("5 + 6 = %d\n", a);
+    return 0;
+}
@@ -0,0 +1,27 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    bool a = true;
+    bool b = false;
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("\n");
+    a = true;
+    b = true;
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("\n");
+    a = false;
+    b = false;
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    return 0;
+}
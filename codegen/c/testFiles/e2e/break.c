@@ -0,0 +1,22 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+static int32_t expressive_add(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a + (uint32_t)b);
+}
+
+int main(void) {
+    int32_t i = 0;
+    int32_t j = 0;
+    for (i = 0; (i < 5); i = expressive_add(i, 1)) {
+        for (j = 0; (j < 5); j = expressive_add(j, 1)) {
+            printf("%d %d\n", i, j);
+            if ((i > 2)) {
+                break;
+            }
+        }
+    }
+    printf("%d %d\n", i, j);
+    return 0;
+}
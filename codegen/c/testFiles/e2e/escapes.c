@@ -0,0 +1,10 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    printf("tab:\t|quotes:\"'|backslash:\\|not a new line:\\n|\n");
+    printf("hex:Ab unicode:é😀\n");
+    printf("%s%s%s%s%s\n", "a", "B", "'", "\"", "☺");
+    return 0;
+}
@@ -0,0 +1,39 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    double a = 5.5;
+    double b = 6.599999904632568;
+    printf("%d\n", (a > b));
+    printf("%d\n", (a < b));
+    printf("%d\n", (a >= b));
+    printf("%d\n", (a <= b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("\n");
+    a = 6.599999904632568;
+    b = 5.5;
+    printf("%d\n", (a > b));
+    printf("%d\n", (a < b));
+    printf("%d\n", (a >= b));
+    printf("%d\n", (a <= b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("\n");
+    a = 5.0;
+    b = 5.0;
+    printf("%d\n", (a > b));
+    printf("%d\n", (a < b));
+    printf("%d\n", (a >= b));
+    printf("%d\n", (a <= b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    return 0;
+}
@@ -0,0 +1,27 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+static int32_t expressive_add(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a + (uint32_t)b);
+}
+
+static int32_t expressive_subtract(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a - (uint32_t)b);
+}
+
+int main(void) {
+    int32_t i = 0;
+    for (i = 0; (i < 5); i = expressive_add(i, 1)) {
+        printf("%d\n", i);
+    }
+    for (int32_t i_2 = 5; (i_2 >= 0); i_2 = expressive_subtract(i_2, 1)) {
+        printf("%d\n", i_2);
+    }
+    for (int32_t j = 0; (j < 10); j = expressive_add(j, 1)) {
+        if ((j > 5)) {
+            printf("%d\n", j);
+        }
+    }
+    return 0;
+}
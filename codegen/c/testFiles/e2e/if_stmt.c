@@ -0,0 +1,75 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    int32_t a = 5;
+    if ((a > 4)) {
+        printf("test1-true\n");
+    } else {
+        printf("test1-false");
+    }
+    if ((a > 5)) {
+        printf("test2-false");
+    } else {
+        printf("test2-true\n");
+    }
+    if ((a > 1)) {
+        printf("test3-true\n");
+    }
+    if ((a < 1)) {
+        printf("test4-false");
+    }
+    if ((a < 3)) {
+        printf("test5-false");
+    } else if ((a > 4)) {
+        printf("test5-true\n");
+    }
+    if ((a < 3)) {
+        printf("test6-false");
+    } else if ((a > 4)) {
+        printf("test6-true\n");
+    } else {
+        printf("test6-false");
+    }
+    if ((a < 3)) {
+        printf("test7-false");
+    } else if ((a < 2)) {
+        printf("test7-false");
+    } else {
+        printf("test7-true\n");
+    }
+    if ((a < 3)) {
+        printf("test8-false");
+    } else if ((a < 2)) {
+        printf("test8-false");
+    } else if ((a < 2)) {
+        printf("test8-false");
+    } else if ((a < 2)) {
+        printf("test8-false");
+    } else if ((a < 2)) {
+        printf("test8-false");
+    } else if ((a < 2)) {
+        printf("test8-false");
+    } else if ((a < 2)) {
+        printf("test8-false");
+    } else if ((a < 2)) {
+        printf("test8-false");
+    } else if ((a < 2)) {
+        printf("test8-false");
+    } else if ((a < 2)) {
+        printf("test8-false");
+    } else {
+        printf("test8-true\n");
+    }
+    printf("%d\n", a);
+    if ((a > 2)) {
+        printf("%d\n", a);
+    }
+    if ((a > 2)) {
+        int32_t a_2 = 7;
+        printf("%d\n", a_2);
+    }
+    printf("%d\n", a);
+    return 0;
+}
@@ -0,0 +1,64 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    int32_t a = 0;
+    int32_t b = 0;
+    a = 5;
+    b = 6;
+    printf("%d\n", (a > b));
+    printf("%d\n", (a < b));
+    printf("%d\n", (a >= b));
+    printf("%d\n", (a <= b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("\n");
+    a = 6;
+    b = 5;
+    printf("%d\n", (a > b));
+    printf("%d\n", (a < b));
+    printf("%d\n", (a >= b));
+    printf("%d\n", (a <= b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("\n");
+    a = 5;
+    b = 5;
+    printf("%d\n", (a > b));
+    printf("%d\n", (a < b));
+    printf("%d\n", (a >= b));
+    printf("%d\n", (a <= b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("\n");
+    a = -5;
+    b = 6;
+    printf("%d\n", (a > b));
+    printf("%d\n", (a < b));
+    printf("%d\n", (a >= b));
+    printf("%d\n", (a <= b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("\n");
+    a = 5;
+    b = -6;
+    printf("%d\n", (a > b));
+    printf("%d\n", (a < b));
+    printf("%d\n", (a >= b));
+    printf("%d\n", (a <= b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("%d\n", (a == b));
+    printf("%d\n", (a != b));
+    printf("\n");
+    return 0;
+}
@@ -0,0 +1,26 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    printf("%d\n", true);
+    printf("%d\n", false);
+    printf("\n");
+    printf("%d\n", (true && true));
+    printf("%d\n", (true && false));
+    printf("%d\n", (false && false));
+    printf("%d\n", (false && true));
+    printf("\n");
+    printf("%d\n", (true || true));
+    printf("%d\n", (true || false));
+    printf("%d\n", (false || false));
+    printf("%d\n", (false || true));
+    printf("\n");
+    printf("%d\n", !true);
+    printf("%d\n", !false);
+    printf("\n");
+    printf("%d\n", !(true && false));
+    printf("%d\n", ((true && !false) || false));
+    printf("%d\n", ((true && !false) || (false || true)));
+    return 0;
+}
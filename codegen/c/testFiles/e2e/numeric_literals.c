@@ -0,0 +1,15 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    int32_t flags = 10;
+    int32_t mask = 31;
+    int32_t mode = 493;
+    int32_t million = 1000000;
+    printf("%d %d %d %d\n", flags, mask, mode, million);
+    printf("%d %d\n", 65535, (-2147483647 - 1));
+    printf("%f %f %f %f\n", 0.5, 5.0, 2500.0, 1000.25);
+    printf("%d\n", (0.0010000000474974513 < 0.009999999776482582));
+    return 0;
+}
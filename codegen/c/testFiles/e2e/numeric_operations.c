@@ -0,0 +1,40 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+static int32_t expressive_add(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a + (uint32_t)b);
+}
+
+static int32_t expressive_subtract(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a - (uint32_t)b);
+}
+
+static int32_t expressive_multiply(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a * (uint32_t)b);
+}
+
+int main(void) {
+    printf("%d\n", expressive_add(5, 6));
+    printf("%d\n", expressive_add(expressive_add(5, 6), 7));
+    printf("%d\n", expressive_subtract(expressive_add(5, 6), 4));
+    printf("%d\n", expressive_add(expressive_subtract(5, 5), 5));
+    printf("%d\n", expressive_subtract(5, 6));
+    printf("%d\n", expressive_subtract(expressive_subtract(5, 6), 5));
+    printf("%d\n", expressive_add(5, expressive_subtract(4, 3)));
+    printf("%d\n", expressive_multiply(5, 6));
+    printf("%d\n", (5 / 6));
+    printf("%d\n", (6 / 6));
+    printf("%d\n", (6 % 5));
+    printf("%d\n", (12 / 4));
+    printf("%d\n", expressive_multiply(3, expressive_add(5, 6)));
+    printf("%d\n", expressive_multiply(expressive_add(5, 6), expressive_add(4, 3)));
+    printf("%d\n", expressive_add(5, expressive_multiply(4, 2)));
+    printf("\n");
+    printf("%.1f\n", (1.100000023841858 + 2.200000047683716));
+    printf("%.1f\n", (1.100000023841858 - 2.200000047683716));
+    printf("%.1f\n", (2.200000047683716 - 1.100000023841858));
+    printf("%.1f\n", (1.0 * 3.0));
+    printf("%.1f\n", (3.0 / 1.0));
+    return 0;
+}
@@ -0,0 +1,35 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+static int32_t expressive_add(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a + (uint32_t)b);
+}
+
+int main(void) {
+    int32_t a = 5;
+    int32_t b = 0;
+    {
+        int32_t expressive_switch_0_test = expressive_add(a, 1);
+        if ((expressive_switch_0_test == 1)) goto switch_0_case_0;
+        if ((expressive_switch_0_test == 6)) goto switch_0_case_1;
+        goto switch_0_default;
+    switch_0_case_0:
+        {
+            b = 3;
+            goto switch_0_end;
+        }
+    switch_0_case_1:
+        {
+            b = 6;
+            goto switch_0_end;
+        }
+    switch_0_default:
+        {
+            b = 7;
+        }
+    }
+switch_0_end:;
+    printf("%d\n", b);
+    return 0;
+}
@@ -0,0 +1,28 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    int32_t a = 5;
+    int32_t b = 0;
+    {
+        bool expressive_switch_0_test = true;
+        if ((expressive_switch_0_test == (a > 7))) goto switch_0_case_0;
+        if ((expressive_switch_0_test == ((a >= 0) && (a <= 7)))) goto switch_0_case_1;
+        goto switch_0_default;
+    switch_0_case_0:
+        {
+            b = 7;
+            goto switch_0_end;
+        }
+    switch_0_case_1:
+        {
+            b = 10;
+            goto switch_0_end;
+        }
+    switch_0_default:;
+    }
+switch_0_end:;
+    printf("%d\n", b);
+    return 0;
+}
@@ -0,0 +1,32 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    int32_t a = 5;
+    int32_t b = 0;
+    {
+        int32_t expressive_switch_0_test = a;
+        if ((expressive_switch_0_test == 5)) goto switch_0_case_0;
+        if ((expressive_switch_0_test == 6)) goto switch_0_case_1;
+        if ((expressive_switch_0_test == 7)) goto switch_0_case_2;
+        if ((expressive_switch_0_test == 8)) goto switch_0_case_3;
+        goto switch_0_default;
+    switch_0_case_0:;
+    switch_0_case_1:;
+    switch_0_case_2:
+        {
+            b = 7;
+            goto switch_0_end;
+        }
+    switch_0_case_3:
+        {
+            b = 8;
+            goto switch_0_end;
+        }
+    switch_0_default:;
+    }
+switch_0_end:;
+    printf("%d\n", b);
+    return 0;
+}
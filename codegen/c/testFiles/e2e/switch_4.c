@@ -0,0 +1,31 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    int32_t a = 5;
+    int32_t b = 0;
+    {
+        int32_t expressive_switch_0_test = a;
+        if ((expressive_switch_0_test == 1)) goto switch_0_case_0;
+        if ((expressive_switch_0_test == 2)) goto switch_0_case_1;
+        goto switch_0_default;
+    switch_0_case_0:
+        {
+            b = 1;
+            goto switch_0_end;
+        }
+    switch_0_case_1:
+        {
+            b = 2;
+            goto switch_0_end;
+        }
+    switch_0_default:
+        {
+            b = 3;
+        }
+    }
+switch_0_end:;
+    printf("%d\n", b);
+    return 0;
+}
@@ -0,0 +1,48 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    int32_t a = 5;
+    int32_t b = 0;
+    {
+        int32_t expressive_switch_0_test = a;
+        if ((expressive_switch_0_test == 1)) goto switch_0_case_0;
+        if ((expressive_switch_0_test == 5)) goto switch_0_case_1;
+        if ((expressive_switch_0_test == 6)) goto switch_0_case_2;
+        if ((expressive_switch_0_test == 7)) goto switch_0_case_3;
+        if ((expressive_switch_0_test == 8)) goto switch_0_case_4;
+        goto switch_0_default;
+    switch_0_case_0:
+        {
+            b = 1;
+        }
+    switch_0_case_1:
+        {
+            b = 5;
+            printf("%d\n", b);
+        }
+    switch_0_case_2:
+        {
+            b = 6;
+            printf("%d\n", b);
+        }
+    switch_0_case_3:
+        {
+            b = 7;
+            printf("%d\n", b);
+        }
+    switch_0_case_4:
+        {
+            b = 8;
+            printf("%d\n", b);
+        }
+    switch_0_default:
+        {
+            b = 9;
+            printf("%d\n", b);
+        }
+    }
+    printf("%d\n", b);
+    return 0;
+}
@@ -0,0 +1,10 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    const int32_t a = 55;
+    const int32_t b = 66;
+    printf("%d\n", ((a > b) ? a : b));
+    return 0;
+}
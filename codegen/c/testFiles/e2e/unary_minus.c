@@ -0,0 +1,27 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+static int32_t expressive_add(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a + (uint32_t)b);
+}
+
+static int32_t expressive_subtract(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a - (uint32_t)b);
+}
+
+static int32_t expressive_multiply(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a * (uint32_t)b);
+}
+
+int main(void) {
+    int32_t a = 5;
+    double x = 2.5;
+    int32_t smallest = (-2147483647 - 1);
+    printf("%d %d %d\n", expressive_subtract(a, 1), expressive_subtract(a, 1), expressive_subtract(a, -1));
+    printf("%d %d %d\n", expressive_subtract(0, a), expressive_subtract(0, expressive_subtract(0, a)), expressive_multiply(expressive_subtract(0, expressive_add(a, 1)), 2));
+    printf("%d %d\n", smallest, expressive_multiply(expressive_subtract(0, a), expressive_subtract(0, a)));
+    printf("%f %f %f\n", -(x), (x - 1.5), -((x * 2.0)));
+    printf("%d %d\n", (expressive_subtract(0, a) < 0), !(expressive_subtract(0, a) > 0));
+    return 0;
+}
@@ -0,0 +1,25 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+static int32_t expressive_add(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a + (uint32_t)b);
+}
+
+static int32_t expressive_subtract(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a - (uint32_t)b);
+}
+
+int main(void) {
+    int32_t a = 5;
+    printf("%d\n", a);
+    a = 6;
+    printf("%d\n", a);
+    a = expressive_add(5, 7);
+    printf("%d\n", a);
+    a = expressive_add(a, 3);
+    printf("%d\n", a);
+    a = expressive_subtract(a, 5);
+    printf("%d\n", a);
+    return 0;
+}
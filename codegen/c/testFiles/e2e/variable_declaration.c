@@ -0,0 +1,13 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+int main(void) {
+    int32_t a = 0;
+    int32_t b = 5;
+    int32_t c = 5;
+    double d = 0.0;
+    const double e = 5.5;
+    const double f = 5.599999904632568;
+    return 0;
+}
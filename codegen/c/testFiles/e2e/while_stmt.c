@@ -0,0 +1,17 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+static int32_t expressive_add(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a + (uint32_t)b);
+}
+
+int main(void) {
+    int32_t a = 5;
+    while ((a < 10)) {
+        printf("%d", a);
+        a = expressive_add(a, 1);
+    }
+    printf("\n");
+    return 0;
+}
@@ -0,0 +1,23 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+
+static int32_t expressive_add(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a + (uint32_t)b);
+}
+
+int32_t puts(const char *);
+int32_t printf(const char *, ...);
+void srand(int32_t);
+int32_t rand(void);
+int32_t abs(int32_t);
+
+int main(void) {
+    int32_t register_ = 1;
+    int32_t register__2 = abs(-2);
+    srand(register_);
+    rand();
+    printf("%d %s %d\n", puts("puts"), "printf", expressive_add(register__2, abs(-3)));
+    printf("%d\n", 4);
+    return 0;
+}
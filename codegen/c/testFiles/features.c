@@ -0,0 +1,92 @@
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include <math.h>
+
+static int32_t expressive_add(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a + (uint32_t)b);
+}
+
+static int32_t expressive_subtract(int32_t a, int32_t b) {
+    return (int32_t)((uint32_t)a - (uint32_t)b);
+}
+
+static int32_t expressive_pow(int32_t base, int32_t exponent) {
+    uint32_t result = 1;
+    uint32_t factor = (uint32_t)base;
+
+    if (exponent < 0) {
+        return base == 1 ? 1 : base == -1 ? (exponent % 2 == 0 ? 1 : -1) : 0;
+    }
+
+    for (; exponent > 0; exponent >>= 1) {
+        if (exponent & 1) {
+            result *= factor;
+        }
+
+        factor *= factor;
+    }
+
+    return (int32_t)result;
+}
+
+static const char *expressive_concat(const char *a, const char *b) {
+    size_t lengthA = strlen(a);
+    size_t lengthB = strlen(b);
+    char *result = malloc(lengthA + lengthB + 1);
+
+    if (result == NULL) {
+        abort();
+    }
+
+    memcpy(result, a, lengthA);
+    memcpy(result + lengthA, b, lengthB + 1);
+
+    return result;
+}
+
+int main(void) {
+    int32_t register_ = expressive_pow(2, 10);
+    double auto_ = pow(2.0, 3.0);
+    const char *expressive_name_ = expressive_concat(expressive_concat("who", "?\?"), "?");
+    const char *c = "c";
+    printf("%d %f %s\n", register_, auto_, expressive_name_);
+    for (int32_t i = 0; (i < 5); i = expressive_add(i, 1)) {
+        const char *s = (((i % 2) == 0) ? "even" : "odd");
+        {
+            const char *expressive_switch_0_test = s;
+            if ((strcmp(expressive_switch_0_test, "even") == 0)) goto switch_0_case_0;
+            goto switch_0_default;
+        switch_0_case_0:
+            {
+                if ((i == 4)) {
+                    goto switch_0_end;
+                }
+                printf("%d is even\n", i);
+            }
+        switch_0_default:
+            {
+                printf("%d after even\n", i);
+            }
+        }
+    switch_0_end:;
+        if ((i == 3)) {
+            break;
+        }
+    }
+    while ((strcmp(c, "c") == 0)) {
+        const char *c_2 = "d";
+        printf("%s shadows\n", c_2);
+        break;
+    }
+    int32_t x = 2147483647;
+    x = expressive_add(x, 1);
+    printf("%d %d\n", x, (expressive_subtract(0, 7) / 2));
+    if (true) {
+        int32_t x_2 = expressive_add(x, 1);
+        printf("%d\n", x_2);
+    }
+    return 0;
+}
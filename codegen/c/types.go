@@ -0,0 +1,95 @@
+package c
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carlcui/expressive/typing"
+)
+
+// cTypes maps the primitive types to C99 types. Chars are strings of one character, as in the
+// llvm backend, so that they print with %s.
+var cTypes = map[typing.Typing]string{
+	typing.INT:    "int32_t",
+	typing.FLOAT:  "double",
+	typing.BYTE:   "uint8_t",
+	typing.CHAR:   "const char *",
+	typing.STRING: "const char *",
+	typing.BOOL:   "bool",
+}
+
+func cType(t typing.Typing) string {
+	if cType, ok := cTypes[t]; ok {
+		return cType
+	}
+
+	panic(fmt.Sprintf("no C type for %v", t))
+}
+
+// declaration declares name with type t, e.g. "int32_t a" or "const char *s"
+func declaration(t typing.Typing, name string) string {
+	cType := cType(t)
+
+	if cType[len(cType)-1] == '*' {
+		return cType + name
+	}
+
+	return cType + " " + name
+}
+
+// prototype declares the C function name of type t, e.g. "int32_t puts(const char *);". C99
+// needs a parameter before "...", so a function only taking any arguments has no prototype.
+func prototype(t *typing.FunctionType, name string) string {
+	params := make([]string, 0, len(t.Params)+1)
+
+	for _, param := range t.Params {
+		params = append(params, cType(param))
+	}
+
+	switch {
+	case t.Variadic && len(params) > 0:
+		params = append(params, "...")
+	case !t.Variadic && len(params) == 0:
+		params = append(params, "void")
+	}
+
+	result := "void " + name
+
+	if t.Result != typing.VOID {
+		result = declaration(t.Result, name)
+	}
+
+	return fmt.Sprintf("%v(%v);", result, strings.Join(params, ", "))
+}
+
+// keywords are the C keywords, and the macros of stdbool.h
+var keywords = map[string]bool{
+	"auto": true, "break": true, "case": true, "char": true, "const": true, "continue": true,
+	"default": true, "do": true, "double": true, "else": true, "enum": true, "extern": true,
+	"float": true, "for": true, "goto": true, "if": true, "inline": true, "int": true,
+	"long": true, "register": true, "restrict": true, "return": true, "short": true,
+	"signed": true, "sizeof": true, "static": true, "struct": true, "switch": true,
+	"typedef": true, "union": true, "unsigned": true, "void": true, "volatile": true,
+	"while": true, "bool": true, "true": true, "false": true,
+}
+
+// libraryFunctions are main and the library functions the generated code calls, by the
+// prototype of those a program may declare too: their C library declares them the same way.
+var libraryFunctions = map[string]string{
+	"main":   "",
+	"printf": "int32_t printf(const char *, ...);",
+	"strcmp": "int32_t strcmp(const char *, const char *);",
+	"strlen": "",
+	"memcpy": "",
+	"malloc": "",
+	"abort":  "void abort(void);",
+	"pow":    "double pow(double, double);",
+}
+
+// isReserved tells whether name is a C keyword or a function of the generated code. A variable
+// with one of these names gets a trailing underscore.
+func isReserved(name string) bool {
+	_, ok := libraryFunctions[name]
+
+	return ok || keywords[name]
+}
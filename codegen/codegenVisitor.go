@@ -5,9 +5,11 @@ import (
 	"strconv"
 
 	"github.com/carlcui/expressive/signature"
+	"github.com/carlcui/expressive/symbolTable"
 	"github.com/carlcui/expressive/typing"
 
 	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/locator"
 	"github.com/carlcui/expressive/logger"
 
 	"github.com/llir/llvm/ir"
@@ -21,10 +23,17 @@ import (
 type CodegenVisitor struct {
 	logger                  logger.Logger
 	labeller                *Labeller
-	constants               []*ir.Global // global constants
-	externals               []*ir.Func   // external function declarations
+	constants               []*ir.Global        // global constants
+	externals               []*ir.Func          // external function declarations
+	functions               map[string]*ir.Func // the functions of externals, by name
 	codeMap                 map[ast.Node]Fragment
 	globalIdentifierTracker *GlobalIdentifierTracker
+	debug                   bool                                    // whether to generate debug information
+	debugInfo               *DebugInfo                              // nil unless debug information is generated
+	allocas                 map[*symbolTable.Binding]*ir.InstAlloca // declared variables
+	verifier                *Verifier
+	flush                   *ir.Func       // fflush, writing what was printed
+	checks                  *RuntimeChecks // nil unless runtime checks are generated
 }
 
 // Init with a logger
@@ -33,8 +42,22 @@ func (visitor *CodegenVisitor) Init(logger logger.Logger) {
 	visitor.labeller = &Labeller{0}
 	visitor.constants = make([]*ir.Global, 0)
 	visitor.externals = make([]*ir.Func, 0)
+	visitor.functions = make(map[string]*ir.Func)
 	visitor.codeMap = make(map[ast.Node]Fragment)
 	visitor.globalIdentifierTracker = &GlobalIdentifierTracker{index: 0}
+	visitor.allocas = make(map[*symbolTable.Binding]*ir.InstAlloca)
+}
+
+// EnableRuntimeChecks makes the visitor guard int operations against overflow and division by
+// zero
+func (visitor *CodegenVisitor) EnableRuntimeChecks() {
+	visitor.checks = NewRuntimeChecks(visitor)
+}
+
+// EnableDebugInfo makes the visitor describe the program with DWARF metadata. Programs that
+// were not read from a file have no lines to describe, and get none.
+func (visitor *CodegenVisitor) EnableDebugInfo() {
+	visitor.debug = true
 }
 
 func (visitor *CodegenVisitor) checkIfFragmentExists(node ast.Node) {
@@ -79,6 +102,16 @@ func (visitor *CodegenVisitor) getAndRemoveFragment(node ast.Node) Fragment {
 	return fragment
 }
 
+// locate records node as the origin of the instructions its fragment generated, and attaches
+// its location to them
+func (visitor *CodegenVisitor) locate(fragment Fragment, node ast.Node) {
+	visitor.verifier.track(fragment, node)
+
+	if visitor.debugInfo != nil {
+		visitor.debugInfo.locate(fragment, node)
+	}
+}
+
 func (visitor *CodegenVisitor) removeVoidFragment(node ast.Node) Fragment {
 	fragment := visitor.getAndRemoveFragment(node)
 
@@ -86,6 +119,8 @@ func (visitor *CodegenVisitor) removeVoidFragment(node ast.Node) Fragment {
 		panic(fmt.Sprintf("Code fragment does not produce void result: %v", node))
 	}
 
+	visitor.locate(fragment, node)
+
 	return fragment
 }
 
@@ -96,6 +131,8 @@ func (visitor *CodegenVisitor) removePointerFragment(node ast.Node) Fragment {
 		panic(fmt.Sprintf("Code fragment does not produce pointer result: %v", node))
 	}
 
+	visitor.locate(fragment, node)
+
 	return fragment
 }
 
@@ -110,6 +147,8 @@ func (visitor *CodegenVisitor) removeValueFragment(node ast.Node) Fragment {
 		visitor.dereferencePointer(fragment)
 	}
 
+	visitor.locate(fragment, node)
+
 	return fragment
 }
 
@@ -135,12 +174,104 @@ func (visitor *CodegenVisitor) dereferencePointer(fragment Fragment) {
 
 // VisitEnterProgramNode creates program scope
 func (visitor *CodegenVisitor) VisitEnterProgramNode(node *ast.ProgramNode) {
+	visitor.verifier = NewVerifier(node)
+
+	visitor.declare(libcFunction("printf"))
+	visitor.flush = visitor.declare(libcFunction("fflush"))
 
-	printfDeclaration := ir.NewFunc("printf", types.I32, ir.NewParam("", types.I8Ptr))
-	printfDeclaration.Sig.Variadic = true
-	printfDeclaration.FuncAttrs = append(printfDeclaration.FuncAttrs, enum.FuncAttrNoUnwind)
+	visitor.declareFunctions(node.GetScope())
 
-	visitor.externals = append(visitor.externals, printfDeclaration)
+	if location, ok := node.GetToken().Locator.(*locator.FileLocation); ok && visitor.debug {
+		visitor.debugInfo = NewDebugInfo(location)
+		visitor.declare(visitor.debugInfo.declare)
+	}
+}
+
+// declare adds function to the functions of the module, unless a function of the same name has
+// already been declared, which is returned instead
+func (visitor *CodegenVisitor) declare(function *ir.Func) *ir.Func {
+	if declared, ok := visitor.functions[function.Name()]; ok {
+		return declared
+	}
+
+	visitor.functions[function.Name()] = function
+	visitor.externals = append(visitor.externals, function)
+
+	return function
+}
+
+// declareFunctions declares the functions of scope, those of the host and the externs, as
+// externals for the linker to resolve
+func (visitor *CodegenVisitor) declareFunctions(scope *symbolTable.Scope) {
+	for _, name := range scope.Identifiers() {
+		binding := scope.FindBinding(name)
+		functionType, ok := binding.GetTyping().(*typing.FunctionType)
+
+		if !ok {
+			continue
+		}
+
+		params := make([]*ir.Param, len(functionType.Params))
+
+		for i, param := range functionType.Params {
+			params[i] = ir.NewParam("", param.IrType())
+		}
+
+		declaration := ir.NewFunc(name, functionType.Result.IrType(), params...)
+		declaration.Sig.Variadic = functionType.Variadic
+
+		if clashes(declaration) {
+			// the functions of the host are declared nowhere in the program
+			location := ""
+
+			if binding.GetLocator() != nil {
+				location = binding.GetLocator().Locate()
+			}
+
+			visitor.log(location, "function \""+name+"\" clashes with a function of the generated code")
+			continue
+		}
+
+		visitor.declare(declaration)
+	}
+}
+
+// clashes tells whether the generated code defines a function named like function, or calls a
+// function of the C library named like it with another signature
+func clashes(function *ir.Func) bool {
+	if function.Name() == "main" || function.Name() == panicFuncName {
+		return true
+	}
+
+	libc := libcFunction(function.Name())
+
+	return libc != nil && !libc.Sig.Equal(function.Sig)
+}
+
+// libcFunction declares the function of the C library named name that the generated code calls,
+// or returns nil if it calls none named so
+func libcFunction(name string) *ir.Func {
+	var function *ir.Func
+
+	switch name {
+	case "printf":
+		function = ir.NewFunc(name, types.I32, ir.NewParam("", types.I8Ptr))
+		function.Sig.Variadic = true
+	case "fflush":
+		function = ir.NewFunc(name, types.I32, ir.NewParam("", types.I8Ptr))
+	case "dprintf":
+		function = ir.NewFunc(name, types.I32, ir.NewParam("", types.I32), ir.NewParam("", types.I8Ptr))
+		function.Sig.Variadic = true
+	case "exit":
+		function = ir.NewFunc(name, types.Void, ir.NewParam("", types.I32))
+		function.FuncAttrs = append(function.FuncAttrs, enum.FuncAttrNoReturn)
+	default:
+		return nil
+	}
+
+	function.FuncAttrs = append(function.FuncAttrs, enum.FuncAttrNoUnwind)
+
+	return function
 }
 
 // VisitLeaveProgramNode closes program scope
@@ -175,11 +306,19 @@ func (visitor *CodegenVisitor) VisitLeaveProgramNode(node *ast.ProgramNode) {
 		lastBlock = mainFunc.Blocks[numberOfBlocks-1]
 	}
 
-	zeroConstant := constant.NewInt(types.I32, 0)
+	// main fails when what was printed cannot be written
+	flushed := lastBlock.NewCall(visitor.flush, constant.NewNull(types.I8Ptr))
+	failed := lastBlock.NewICmp(enum.IPredNE, flushed, constant.NewInt(types.I32, 0))
 
-	lastBlock.NewRet(zeroConstant)
+	lastBlock.NewRet(lastBlock.NewZExt(failed, types.I32))
+
+	visitor.locate(functionsFragment, node)
 
 	fragment.Append(functionsFragment)
+
+	if visitor.debugInfo != nil {
+		visitor.debugInfo.attach(fragment.Module, mainFunc)
+	}
 }
 
 func (visitor *CodegenVisitor) VisitEnterBlockNode(node *ast.BlockNode) {
@@ -212,17 +351,28 @@ func (visitor *CodegenVisitor) VisitLeaveVariableDeclarationNode(node *ast.Varia
 	fragment.NewBlock("")
 	allocaInstr := fragment.CurrentBlock.NewAlloca(irType)
 	allocaInstr.SetName(identifierNode.LocalIdentifier())
+	visitor.allocas[identifierNode.GetBinding()] = allocaInstr
+
+	if visitor.debugInfo != nil {
+		visitor.debugInfo.declareVariable(fragment.CurrentBlock, allocaInstr, identifierNode)
+	}
 
 	if node.Expr == nil {
-		// load default value
+		// Semantic analysis makes sure the variable is assigned before being read. The default
+		// value keeps the memory well-defined anyway.
 		var defaultValue value.Value
 
-		// TODO: finish default values for types
 		switch identifierTyping {
 		case typing.INT:
 			defaultValue = constant.NewInt(typing.INT.IrType().(*types.IntType), 0)
 		case typing.FLOAT:
 			defaultValue = constant.NewFloat(typing.FLOAT.IrType().(*types.FloatType), 0)
+		case typing.BOOL:
+			defaultValue = constant.False
+		case typing.CHAR, typing.STRING:
+			defaultValue = visitor.newStringPointer(fragment.CurrentBlock, "")
+		default:
+			panic(node.GetLocation() + ": no default value for type " + identifierTyping.String())
 		}
 
 		fragment.CurrentBlock.NewStore(defaultValue, allocaInstr)
@@ -273,6 +423,10 @@ func (visitor *CodegenVisitor) VisitLeaveAssignmentNode(node *ast.AssignmentNode
 			lhsExprFragment,
 			rhsExprFragment)
 
+		if visitor.checks != nil {
+			operatorCodegen.EnableChecks(visitor.checks, node)
+		}
+
 		operatorCodegen.GenerateCode()
 
 		operationResult := binaryOperationFragment.GetResult()
@@ -300,11 +454,21 @@ func (visitor *CodegenVisitor) VisitLeaveIncDecNode(node *ast.IncDecNode) {
 
 	intType := (typing.INT.IrType()).(*types.IntType)
 
-	if node.IsIncrement {
-		add := fragment.CurrentBlock.NewAdd(load, constant.NewInt(intType, 1))
+	one := constant.NewInt(intType, 1)
+
+	if visitor.checks != nil {
+		operation := "ssub"
+
+		if node.IsIncrement {
+			operation = "sadd"
+		}
+
+		fragment.CurrentBlock.NewStore(visitor.checks.overflowing(fragment, operation, load, one, node), lhsResult)
+	} else if node.IsIncrement {
+		add := fragment.CurrentBlock.NewAdd(load, one)
 		fragment.CurrentBlock.NewStore(add, lhsResult)
 	} else {
-		sub := fragment.CurrentBlock.NewSub(load, constant.NewInt(intType, 1))
+		sub := fragment.CurrentBlock.NewSub(load, one)
 		fragment.CurrentBlock.NewStore(sub, lhsResult)
 	}
 
@@ -343,7 +507,62 @@ func (visitor *CodegenVisitor) VisitLeavePrintNode(node *ast.PrintNode) {
 
 	args := append([]value.Value{stringExprResult}, argResults...)
 
-	fragment.CurrentBlock.NewCall(visitor.externals[0], args...)
+	fragment.CurrentBlock.NewCall(visitor.functions["printf"], args...)
+}
+
+func (visitor *CodegenVisitor) VisitEnterCallNode(node *ast.CallNode) {
+
+}
+
+// VisitLeaveCallNode calls the declaration of the function, discarding its result if the call
+// is a statement
+func (visitor *CodegenVisitor) VisitLeaveCallNode(node *ast.CallNode) {
+	resultType := VALUE
+
+	if node.IsStatement() {
+		resultType = VOID
+	}
+
+	fragment := visitor.newBlocksFragment(node, resultType)
+	fragment.NewBlock("")
+
+	function, ok := visitor.functions[node.Name()]
+
+	if !ok {
+		panic(node.GetLocation() + ": function " + node.Name() + " is not declared")
+	}
+
+	args := make([]value.Value, 0, len(node.Args))
+
+	for i, arg := range node.Args {
+		argFrag := visitor.removeValueFragment(arg)
+
+		fragment.Append(argFrag)
+
+		argResult := argFrag.GetResult()
+
+		// C promotes the bools passed as variadic arguments to int
+		if i >= len(function.Params) && arg.GetTyping() == typing.BOOL {
+			argResult = fragment.CurrentBlock.NewZExt(argResult, types.I32)
+		}
+
+		args = append(args, argResult)
+	}
+
+	call := fragment.CurrentBlock.NewCall(function, args...)
+
+	if resultType == VALUE {
+		fragment.resultValue = call
+	}
+}
+
+func (visitor *CodegenVisitor) VisitEnterExternNode(node *ast.ExternNode) {
+
+}
+
+// VisitLeaveExternNode generates no code: the function has been declared with the program
+func (visitor *CodegenVisitor) VisitLeaveExternNode(node *ast.ExternNode) {
+	visitor.newBlocksFragment(node, VOID)
 }
 
 func (visitor *CodegenVisitor) VisitEnterIfStmtNode(node *ast.IfStmtNode) {
@@ -652,6 +871,10 @@ func (visitor *CodegenVisitor) VisitLeaveBinaryOperatorNode(node *ast.BinaryOper
 
 	operatorCodegen := NewOperatorCodegen(fragment, operator, typing, visitor.labeller, fragment1, fragment2)
 
+	if visitor.checks != nil {
+		operatorCodegen.EnableChecks(visitor.checks, node)
+	}
+
 	operatorCodegen.GenerateCode()
 }
 
@@ -672,6 +895,10 @@ func (visitor *CodegenVisitor) VisitLeaveUnaryOperatorNode(node *ast.UnaryOperat
 
 	operatorCodegen := NewOperatorCodegen(fragment, operator, typing, visitor.labeller, fragment1)
 
+	if visitor.checks != nil {
+		operatorCodegen.EnableChecks(visitor.checks, node)
+	}
+
 	operatorCodegen.GenerateCode()
 }
 
@@ -699,25 +926,21 @@ func (visitor *CodegenVisitor) VisitFloatNode(node *ast.FloatNode) {
 func (visitor *CodegenVisitor) VisitCharacterNode(node *ast.CharacterNode) {
 	fragment := visitor.newBlocksFragment(node, VALUE)
 
-	stringConstant := constant.NewCharArrayFromString(node.StringValue())
-	stringGlobal := ir.NewGlobal(visitor.globalIdentifierTracker.NewIdentifier(), stringConstant.Type())
-	stringGlobal.Init = stringConstant
-	stringGlobal.Linkage = enum.LinkagePrivate
-	stringGlobal.Align = 1
-
-	visitor.constants = append(visitor.constants, stringGlobal)
-
 	fragment.NewBlock("")
-	result := fragment.CurrentBlock.NewGetElementPtr(stringConstant.Type(), stringGlobal, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 0))
-	fragment.resultValue = result
+	fragment.resultValue = visitor.newStringPointer(fragment.CurrentBlock, node.StringValue())
 }
 
 // VisitStringNode do something
 func (visitor *CodegenVisitor) VisitStringNode(node *ast.StringNode) {
 	fragment := visitor.newBlocksFragment(node, VALUE)
 
-	stringValue := node.StringValue()
+	fragment.NewBlock("")
+	fragment.resultValue = visitor.newStringPointer(fragment.CurrentBlock, node.StringValue())
+}
 
+// newStringPointer stores stringValue in a private global constant, and returns a pointer to
+// its first character
+func (visitor *CodegenVisitor) newStringPointer(block *ir.Block, stringValue string) value.Value {
 	stringConstant := constant.NewCharArrayFromString(stringValue)
 	stringGlobal := ir.NewGlobal(visitor.globalIdentifierTracker.NewIdentifier(), stringConstant.Type())
 	stringGlobal.Init = stringConstant
@@ -726,15 +949,19 @@ func (visitor *CodegenVisitor) VisitStringNode(node *ast.StringNode) {
 
 	visitor.constants = append(visitor.constants, stringGlobal)
 
-	fragment.NewBlock("")
-	result := fragment.CurrentBlock.NewGetElementPtr(stringConstant.Type(), stringGlobal, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 0))
-	fragment.resultValue = result
+	return block.NewGetElementPtr(stringConstant.Type(), stringGlobal, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 0))
 }
 
 // VisitIdentifierNode do something
 func (visitor *CodegenVisitor) VisitIdentifierNode(node *ast.IdentifierNode) {
 	fragment := visitor.newBlocksFragment(node, POINTER)
 
+	// uses of a variable refer to the alloca declaring it, so that passes can follow them
+	if allocaInstr, ok := visitor.allocas[node.GetBinding()]; ok {
+		fragment.resultValue = allocaInstr
+		return
+	}
+
 	identifier := node.LocalIdentifier()
 
 	allocaInstr := ir.NewAlloca(node.GetTyping().IrType())
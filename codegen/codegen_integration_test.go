@@ -2,50 +2,290 @@ package codegen
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/carlcui/expressive/ast"
-	"github.com/carlcui/expressive/input"
+	"github.com/carlcui/expressive/codegen/passes"
+	"github.com/carlcui/expressive/host"
+	"github.com/carlcui/expressive/internal/testutil"
 	"github.com/carlcui/expressive/logger"
-	"github.com/carlcui/expressive/parser"
-	"github.com/carlcui/expressive/scanner"
-	"github.com/carlcui/expressive/semanticAnalyser"
+	"github.com/carlcui/expressive/typing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
 )
 
-func parseFile(dirName string, fileName string) ast.Node {
-	var fileInput input.File
-	fileInput.Init(dirName, fileName)
+func newLogger() *logger.StdError {
+	var logger logger.StdError
+	return &logger
+}
+
+func TestCodegen(t *testing.T) {
+	root := testutil.AnalyzeFile("tests", "test1.exp", t)
+
+	result := Generate(root, newLogger())
+
+	fmt.Println(result)
+
+	// t.Error()
+}
+
+func TestDebugInfo(t *testing.T) {
+	root := testutil.AnalyzeFile("../e2e", "switch_6.exp", t)
 
-	var s scanner.ExpressiveScanner
-	s.Init(&fileInput)
+	result := GenerateWithDebugInfo(root, newLogger())
 
-	var p parser.Parser
-	p.Init(&s, newLogger())
+	expected := []string{
+		`!llvm.dbg.cu = !{`,
+		`distinct !DICompileUnit(language: DW_LANG_C99, file: `,
+		`!DIFile(filename: "switch_6.exp", directory: "`,
+		`distinct !DISubprogram(name: "main", `,
+		`!DILocalVariable(name: "a", scope: `,
+		`call void @llvm.dbg.declare(metadata i32* %a___scope___`,
+		`!DILocation(line: 27, column: 7, scope: `,
+		`!{i32 2, !"Debug Info Version", i32 3}`,
+	}
 
-	return p.Parse()
+	for _, e := range expected {
+		if !strings.Contains(result, e) {
+			t.Errorf("Expecting debug info to contain %v, got\n%v", e, result)
+		}
+	}
+
+	// every instruction of main is located
+	for _, line := range strings.Split(result, "\n") {
+		if strings.HasPrefix(line, "\t") && !strings.Contains(line, ", !dbg !") {
+			t.Errorf("Expecting a location on %v", line)
+		}
+	}
 }
 
-func newLogger() *logger.StdError {
-	var logger logger.StdError
-	return &logger
+func TestNoDebugInfo(t *testing.T) {
+	root := testutil.AnalyzeFile("../e2e", "switch_6.exp", t)
+
+	if result := Generate(root, newLogger()); strings.Contains(result, "!") {
+		t.Errorf("Expecting no metadata without debug info, got\n%v", result)
+	}
 }
 
-func getAnalyzedAst(dirName string, fileName string) ast.Node {
-	root := parseFile(dirName, fileName)
+func TestPasses(t *testing.T) {
+	files, err := filepath.Glob("../e2e/*.exp")
 
-	logger := newLogger()
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	semanticAnalyser.Analyze(root, logger)
+	for _, file := range files {
+		root := testutil.AnalyzeFile("../e2e", filepath.Base(file), t)
 
-	return root
+		result := GenerateWithOptions(root, Options{Passes: passes.Pipeline(2)}, newLogger())
+
+		// every variable is promoted to a register
+		for _, instruction := range []string{" alloca ", " load ", "\tstore "} {
+			if strings.Contains(result, instruction) {
+				t.Errorf("File %v: expecting no%vonce optimized, got\n%v", file, instruction, result)
+			}
+		}
+	}
 }
 
-func TestCodegen(t *testing.T) {
-	root := getAnalyzedAst("tests", "test1.exp")
+func TestPassesKeepDebugInfo(t *testing.T) {
+	root := testutil.AnalyzeFile("../e2e", "switch_6.exp", t)
 
-	result := Generate(root, newLogger())
+	result := GenerateWithOptions(root, Options{DebugInfo: true, Passes: passes.Pipeline(2)}, newLogger())
 
-	fmt.Println(result)
+	// variables described to a debugger stay in memory
+	if !strings.Contains(result, `call void @llvm.dbg.declare(metadata i32* %a___scope___`) {
+		t.Errorf("Expecting variables to keep their declaration, got\n%v", result)
+	}
+}
 
-	// t.Error()
+func TestRuntimeChecks(t *testing.T) {
+	root := testutil.AnalyzeFile("tests", "checks.exp", t)
+
+	result := GenerateWithOptions(root, Options{Checks: true}, newLogger())
+
+	expected := []string{
+		`call { i32, i1 } @llvm.sadd.with.overflow.i32(i32 %`,
+		`call { i32, i1 } @llvm.ssub.with.overflow.i32(i32 %`,
+		`call { i32, i1 } @llvm.ssub.with.overflow.i32(i32 0, i32 %`,
+		`call { i32, i1 } @llvm.smul.with.overflow.i32(i32 %`,
+		`define void @__expressive_panic(i8* %message) cold noinline noreturn nounwind {`,
+		`tests/checks.exp: row 6, column 16: runtime error: integer division by zero\0A\00"`,
+		`tests/checks.exp: row 3, column 0: runtime error: integer overflow\0A\00"`,
+	}
+
+	for _, e := range expected {
+		if !strings.Contains(result, e) {
+			t.Errorf("Expecting runtime checks to contain %v, got\n%v", e, result)
+		}
+	}
+
+	if strings.Contains(Generate(root, newLogger()), "__expressive_panic") {
+		t.Error("Expecting no runtime checks unless enabled")
+	}
+}
+
+// generateModule generates the module of a file without verifying it
+func generateModule(dirName string, fileName string, t *testing.T) (*CodegenVisitor, *ir.Module) {
+	root := testutil.AnalyzeFile(dirName, fileName, t)
+
+	var visitor CodegenVisitor
+	visitor.Init(newLogger())
+
+	root.Accept(&visitor)
+
+	return &visitor, visitor.removeVoidFragment(root).(*ModuleFragment).Module
+}
+
+func TestVerifier(t *testing.T) {
+	visitor, module := generateModule("tests", "verifier.exp", t)
+
+	var buffer logger.Buffer
+
+	if !visitor.verifier.Verify(module, &buffer) {
+		t.Errorf("Expecting the generated module to be valid, got %v", buffer.Messages())
+	}
+}
+
+func TestVerifierReportsAtSourceNode(t *testing.T) {
+	visitor, module := generateModule("tests", "verifier.exp", t)
+
+	for _, block := range module.Funcs[len(module.Funcs)-1].Blocks {
+		for _, instruction := range block.Insts {
+			if store, ok := instruction.(*ir.InstStore); ok {
+				if _, ok := store.Src.(*ir.InstAdd); ok {
+					store.Src = nil
+				}
+			}
+		}
+	}
+
+	var buffer logger.Buffer
+
+	if visitor.verifier.Verify(module, &buffer) {
+		t.Fatal("Expecting a store of nil to be reported")
+	}
+
+	messages := buffer.Messages()
+
+	if len(messages) != 1 || !strings.Contains(messages[0], "row 1, column 0: internal compiler error: store has no value") {
+		t.Errorf("Expecting the store to be reported at the declaration of b, got %v", messages)
+	}
+}
+
+func TestVerifierReportsInstructionAfterTerminator(t *testing.T) {
+	visitor, module := generateModule("tests", "verifier.exp", t)
+
+	for _, block := range module.Funcs[len(module.Funcs)-1].Blocks {
+		if _, ok := visitor.verifier.origins[block.Term].(*ast.BreakNode); ok {
+			block.NewAdd(constant.NewInt(types.I32, 1), constant.NewInt(types.I32, 1))
+		}
+	}
+
+	var buffer logger.Buffer
+
+	if visitor.verifier.Verify(module, &buffer) {
+		t.Fatal("Expecting an instruction following a break to be reported")
+	}
+
+	if messages := buffer.Messages(); len(messages) != 1 || !strings.Contains(messages[0], "internal compiler error: instruction follows the terminator of block") {
+		t.Errorf("Expecting an instruction following a terminator, got %v", messages)
+	}
+}
+
+func generateWithHost(src string, functions []*host.Function, t *testing.T) (string, *logger.Buffer) {
+	var buffer logger.Buffer
+
+	return Generate(testutil.AnalyzeSource(src, functions, t), &buffer), &buffer
+}
+
+func newHostFunction(name string, result typing.Typing, params ...typing.Typing) *host.Function {
+	return &host.Function{Name: name, Params: params, Result: result, Call: func(args []host.Value) (host.Value, error) {
+		return nil, nil
+	}}
+}
+
+func TestHostFunctions(t *testing.T) {
+	functions := []*host.Function{
+		newHostFunction("square", typing.INT, typing.INT),
+		newHostFunction("log", typing.VOID, typing.STRING, typing.FLOAT),
+	}
+
+	result, buffer := generateWithHost("log(\"x\", 1.5);\nprint \"%d\\n\", square(2);\n", functions, t)
+
+	if buffer.ErrorsCount() > 0 {
+		t.Fatalf("Unexpected errors %v", buffer.Messages())
+	}
+
+	expected := []string{
+		"declare i32 @printf(i8* %0, ...)",
+		"declare void @log(i8* %0, double %1)",
+		"declare i32 @square(i32 %0)",
+		"call void @log(i8* ",
+		"= call i32 @square(i32 2)",
+	}
+
+	for _, s := range expected {
+		if !strings.Contains(result, s) {
+			t.Errorf("Expecting the llvm IR to contain %q, got\n%v", s, result)
+		}
+	}
+
+	for _, name := range []string{"printf", "main"} {
+		result, buffer := generateWithHost("", []*host.Function{newHostFunction(name, typing.INT)}, t)
+
+		expected := fmt.Sprintf(": function %q clashes with a function of the generated code", name)
+
+		if result != "" || buffer.ErrorsCount() != 1 || buffer.Messages()[0] != expected {
+			t.Errorf("Expecting %q, got %v", expected, buffer.Messages())
+		}
+	}
+}
+
+func TestExternFunctions(t *testing.T) {
+	src := "extern func puts(s: string) -> int;\nextern func printf(format: string, ...) -> int;\nextern func srand(seed: int);\n" +
+		"srand(1);\nprintf(\"%d %d\\n\", puts(\"a\"), true);\nprint \"%d\\n\", 2;\n"
+
+	result, buffer := generateWithHost(src, nil, t)
+
+	if buffer.ErrorsCount() > 0 {
+		t.Fatalf("Unexpected errors %v", buffer.Messages())
+	}
+
+	expected := []string{
+		"declare i32 @puts(i8* %0)",
+		"declare void @srand(i32 %0)",
+		"call void @srand(i32 1)",
+		"= call i32 @puts(i8* ",
+		"= zext i1 true to i32",
+	}
+
+	for _, s := range expected {
+		if !strings.Contains(result, s) {
+			t.Errorf("Expecting the llvm IR to contain %q, got\n%v", s, result)
+		}
+	}
+
+	if count := strings.Count(result, "declare i32 @printf("); count != 1 {
+		t.Errorf("Expecting printf to be declared once for the program and the print statement, got %v in\n%v", count, result)
+	}
+
+	src = "extern func printf(format: string) -> int;\nextern func main() -> int;\n"
+
+	if result, buffer = generateWithHost(src, nil, t); result != "" || buffer.ErrorsCount() != 2 {
+		t.Fatalf("Expecting printf and main to clash, got %v", buffer.Messages())
+	}
+
+	expected = []string{
+		`at 55: function "main" clashes with a function of the generated code`,
+		`at 12: function "printf" clashes with a function of the generated code`,
+	}
+
+	if fmt.Sprint(buffer.Messages()) != fmt.Sprint(expected) {
+		t.Errorf("Expecting %q, got %q", expected, buffer.Messages())
+	}
 }
@@ -0,0 +1,273 @@
+package codegen
+
+import (
+	"path/filepath"
+	"reflect"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/locator"
+	"github.com/carlcui/expressive/typing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+const (
+	dwarfVersion     = 4
+	debugInfoVersion = 3 // version of the debug metadata format llvm reads
+)
+
+// DebugInfo builds the DWARF metadata of a module: the compile unit of the source file, the
+// subprogram of main, the location of every instruction and the variables declared in main.
+// Rows and columns of the ast start at 0, lines and columns of DWARF at 1.
+type DebugInfo struct {
+	definitions []metadata.Definition
+	file        *metadata.DIFile
+	unit        *metadata.DICompileUnit
+	subprogram  *metadata.DISubprogram
+	types       map[typing.Typing]metadata.Field
+	locations   map[locator.FileLocation]*metadata.DILocation
+	declare     *ir.Func // llvm.dbg.declare
+}
+
+// NewDebugInfo describes the program of the file at location
+func NewDebugInfo(location *locator.FileLocation) *DebugInfo {
+	debugInfo := &DebugInfo{
+		types:     make(map[typing.Typing]metadata.Field),
+		locations: make(map[locator.FileLocation]*metadata.DILocation),
+	}
+
+	directory, err := filepath.Abs(location.DirName)
+
+	if err != nil {
+		directory = location.DirName
+	}
+
+	debugInfo.file = &metadata.DIFile{MetadataID: -1, Filename: location.FileName, Directory: directory}
+
+	// DWARF has no language code for expressive. Debuggers know C, whose types and
+	// expressions are the closest.
+	debugInfo.unit = &metadata.DICompileUnit{
+		MetadataID:   -1,
+		Distinct:     true,
+		Language:     enum.DwarfLangC99,
+		File:         debugInfo.file,
+		Producer:     "expressive",
+		EmissionKind: enum.EmissionKindFullDebug,
+	}
+
+	mainType := &metadata.DISubroutineType{
+		MetadataID: -1,
+		Types:      debugInfo.tuple(debugInfo.typeOf(typing.INT)),
+	}
+
+	debugInfo.subprogram = &metadata.DISubprogram{
+		MetadataID:   -1,
+		Distinct:     true,
+		Scope:        debugInfo.file,
+		Name:         "main",
+		File:         debugInfo.file,
+		Line:         int64(location.Row + 1),
+		Type:         mainType,
+		IsDefinition: true,
+		ScopeLine:    int64(location.Row + 1),
+		SPFlags:      enum.DISPFlagDefinition,
+		Unit:         debugInfo.unit,
+	}
+
+	debugInfo.define(debugInfo.file, debugInfo.unit, mainType, debugInfo.subprogram)
+
+	debugInfo.declare = ir.NewFunc("llvm.dbg.declare", types.Void,
+		ir.NewParam("", types.Metadata), ir.NewParam("", types.Metadata), ir.NewParam("", types.Metadata))
+	debugInfo.declare.FuncAttrs = append(debugInfo.declare.FuncAttrs, enum.FuncAttrNoUnwind, enum.FuncAttrReadNone)
+
+	return debugInfo
+}
+
+func (debugInfo *DebugInfo) define(definitions ...metadata.Definition) {
+	debugInfo.definitions = append(debugInfo.definitions, definitions...)
+}
+
+func (debugInfo *DebugInfo) tuple(fields ...metadata.Field) *metadata.Tuple {
+	tuple := &metadata.Tuple{MetadataID: -1, Fields: fields}
+	debugInfo.define(tuple)
+
+	return tuple
+}
+
+// typeOf describes values of t. Strings and chars are pointers to null terminated characters.
+func (debugInfo *DebugInfo) typeOf(t typing.Typing) metadata.Field {
+	if field, ok := debugInfo.types[t]; ok {
+		return field
+	}
+
+	var field metadata.Definition
+
+	switch t {
+	case typing.INT:
+		field = &metadata.DIBasicType{MetadataID: -1, Tag: enum.DwarfTagBaseType, Name: "int", Size: 32, Encoding: enum.DwarfAttEncodingSigned}
+	case typing.FLOAT:
+		field = &metadata.DIBasicType{MetadataID: -1, Tag: enum.DwarfTagBaseType, Name: "float", Size: 64, Encoding: enum.DwarfAttEncodingFloat}
+	case typing.BYTE:
+		field = &metadata.DIBasicType{MetadataID: -1, Tag: enum.DwarfTagBaseType, Name: "byte", Size: 8, Encoding: enum.DwarfAttEncodingUnsignedChar}
+	case typing.BOOL:
+		field = &metadata.DIBasicType{MetadataID: -1, Tag: enum.DwarfTagBaseType, Name: "bool", Size: 8, Encoding: enum.DwarfAttEncodingBoolean}
+	case typing.CHAR, typing.STRING:
+		character := &metadata.DIBasicType{MetadataID: -1, Tag: enum.DwarfTagBaseType, Name: "char", Size: 8, Encoding: enum.DwarfAttEncodingSignedChar}
+		debugInfo.define(character)
+
+		field = &metadata.DIDerivedType{MetadataID: -1, Tag: enum.DwarfTagPointerType, Name: t.String(), BaseType: character, Size: 64}
+	default:
+		panic("no debug type for " + t.String())
+	}
+
+	debugInfo.define(field)
+	debugInfo.types[t] = field
+
+	return field
+}
+
+// location of node in main, or nil if its token has no file location
+func (debugInfo *DebugInfo) location(node ast.Node) *metadata.DILocation {
+	if node.GetToken() == nil {
+		return nil
+	}
+
+	fileLocation, ok := node.GetToken().Locator.(*locator.FileLocation)
+
+	if !ok {
+		return nil
+	}
+
+	if location, ok := debugInfo.locations[*fileLocation]; ok {
+		return location
+	}
+
+	location := &metadata.DILocation{
+		MetadataID: -1,
+		Line:       int64(fileLocation.Row + 1),
+		Column:     int64(fileLocation.Col + 1),
+		Scope:      debugInfo.subprogram,
+	}
+
+	debugInfo.define(location)
+	debugInfo.locations[*fileLocation] = location
+
+	return location
+}
+
+// locate attaches the location of node to the instructions of fragment that have none yet.
+// Instructions are located when their node's fragment is removed, so the instructions of
+// children keep their own locations.
+func (debugInfo *DebugInfo) locate(fragment Fragment, node ast.Node) {
+	var blocks []*ir.Block
+
+	switch f := fragment.(type) {
+	case *BlocksFragment:
+		blocks = f.Blocks
+	case *FunctionsFragment:
+		for _, function := range f.Functions {
+			blocks = append(blocks, function.Blocks...)
+		}
+	}
+
+	if len(blocks) == 0 {
+		return
+	}
+
+	location := debugInfo.location(node)
+
+	if location == nil {
+		return
+	}
+
+	for _, block := range blocks {
+		for _, instruction := range block.Insts {
+			attachLocation(instruction, location)
+		}
+
+		if block.Term != nil {
+			attachLocation(block.Term, location)
+		}
+	}
+}
+
+// attachLocation sets the !dbg attachment of an instruction or terminator, unless it has one.
+// Every kind of instruction embeds ir.Metadata, which llir exposes without a setter.
+func attachLocation(instruction interface{}, location *metadata.DILocation) {
+	field := reflect.ValueOf(instruction).Elem().FieldByName("Metadata")
+
+	if !field.IsValid() {
+		return
+	}
+
+	attachments := field.Interface().(ir.Metadata)
+
+	for _, attachment := range attachments {
+		if attachment.Name == "dbg" {
+			return
+		}
+	}
+
+	field.Set(reflect.ValueOf(append(attachments, &metadata.Attachment{Name: "dbg", Node: location})))
+}
+
+// declareVariable describes the variable of identifier, stored by alloca, with a call to
+// llvm.dbg.declare appended to block
+func (debugInfo *DebugInfo) declareVariable(block *ir.Block, alloca *ir.InstAlloca, identifier *ast.IdentifierNode) {
+	location := debugInfo.location(identifier)
+
+	if location == nil {
+		return
+	}
+
+	variable := &metadata.DILocalVariable{
+		MetadataID: -1,
+		Scope:      debugInfo.subprogram,
+		Name:       identifier.Tok.Raw,
+		File:       debugInfo.file,
+		Line:       location.Line,
+		Type:       debugInfo.typeOf(identifier.GetTyping()),
+	}
+
+	expression := &metadata.DIExpression{MetadataID: -1}
+
+	debugInfo.define(variable, expression)
+
+	call := block.NewCall(debugInfo.declare,
+		&metadata.Value{Value: alloca}, &metadata.Value{Value: variable}, &metadata.Value{Value: expression})
+
+	attachLocation(call, location)
+}
+
+// attach adds the metadata to module, whose main function is described by the subprogram
+func (debugInfo *DebugInfo) attach(module *ir.Module, main *ir.Func) {
+	main.Metadata = append(main.Metadata, &metadata.Attachment{Name: "dbg", Node: debugInfo.subprogram})
+
+	moduleFlag := func(behavior int64, name string, value int64) *metadata.Tuple {
+		return debugInfo.tuple(
+			constant.NewInt(types.I32, behavior),
+			&metadata.String{Value: name},
+			constant.NewInt(types.I32, value))
+	}
+
+	const warning = 2 // behavior of a flag on conflicting values when modules are linked
+
+	module.NamedMetadataDefs["llvm.dbg.cu"] = &metadata.NamedDef{
+		Name:  "llvm.dbg.cu",
+		Nodes: []metadata.Node{debugInfo.unit},
+	}
+
+	module.NamedMetadataDefs["llvm.module.flags"] = &metadata.NamedDef{
+		Name: "llvm.module.flags",
+		Nodes: []metadata.Node{
+			moduleFlag(warning, "Dwarf Version", dwarfVersion),
+			moduleFlag(warning, "Debug Info Version", debugInfoVersion),
+		},
+	}
+
+	module.MetadataDefs = append(module.MetadataDefs, debugInfo.definitions...)
+}
@@ -2,23 +2,82 @@ package codegen
 
 import (
 	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/codegen/passes"
 	"github.com/carlcui/expressive/logger"
+
+	"github.com/llir/llvm/ir"
 )
 
+// Options of the generated llvm IR
+type Options struct {
+	DebugInfo bool          // describe the program with DWARF metadata
+	Checks    bool          // guard int operations against overflow and division by zero
+	Passes    []passes.Pass // run over the module once generated, in order
+}
+
 // Generate llvm IR for ast
 func Generate(node ast.Node, logger logger.Logger) string {
+	return GenerateWithOptions(node, Options{}, logger)
+}
+
+// GenerateWithDebugInfo generates llvm IR for ast, described with DWARF metadata so that a
+// debugger can step through the compiled program and show its variables
+func GenerateWithDebugInfo(node ast.Node, logger logger.Logger) string {
+	return GenerateWithOptions(node, Options{DebugInfo: true}, logger)
+}
+
+// GenerateWithOptions generates llvm IR for ast as options tell
+func GenerateWithOptions(node ast.Node, options Options, logger logger.Logger) string {
+	module := GenerateModule(node, options, logger)
+
+	if module == nil {
+		return ""
+	}
+
+	return module.String()
+}
+
+// GenerateModule generates the llvm IR module of ast as options tell. It returns nil if the
+// program cannot be generated or the module fails verification, which is reported to logger.
+func GenerateModule(node ast.Node, options Options, logger logger.Logger) *ir.Module {
 	var visitor CodegenVisitor
 	visitor.Init(logger)
 
+	if options.DebugInfo {
+		visitor.EnableDebugInfo()
+	}
+
+	if options.Checks {
+		visitor.EnableRuntimeChecks()
+	}
+
+	errorsCount := logger.ErrorsCount()
+
 	node.Accept(&visitor)
 
+	if logger.ErrorsCount() > errorsCount {
+		return nil
+	}
+
 	rootFragment := visitor.removeVoidFragment(node)
 
 	globalConstants := visitor.constants
 
 	moduleFragment := rootFragment.(*ModuleFragment)
 
-	moduleFragment.Module.Globals = append(globalConstants)
+	moduleFragment.Module.Globals = globalConstants
+
+	if !visitor.verifier.Verify(moduleFragment.Module, logger) {
+		return nil
+	}
+
+	if len(options.Passes) > 0 {
+		passes.Run(moduleFragment.Module, options.Passes)
+
+		if !visitor.verifier.VerifyPasses(moduleFragment.Module, logger) {
+			return nil
+		}
+	}
 
-	return moduleFragment.Module.String()
+	return moduleFragment.Module
 }
@@ -0,0 +1,604 @@
+package js
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/locator"
+	"github.com/carlcui/expressive/signature"
+	"github.com/carlcui/expressive/symbolTable"
+	"github.com/carlcui/expressive/typing"
+)
+
+const indentation = "  "
+
+// Generator translates an analysed ast into ES2015. Code is written in order, so that the
+// position of every node in the generated code is known for the source map.
+type Generator struct {
+	out       strings.Builder
+	line      int // generated position, in UTF-16 code units as source maps count
+	column    int
+	indent    int
+	sourceMap *SourceMap
+	names     map[*symbolTable.Binding]string
+	usedNames map[string]bool
+	labels    map[ast.Node]string // loops and switches some break exits, to their label
+
+	unsupported ast.Node // the first call of a host function or extern declaration, nil if there is none
+}
+
+func (generator *Generator) Init() {
+	generator.sourceMap = newSourceMap()
+	generator.names = make(map[*symbolTable.Binding]string)
+	generator.usedNames = make(map[string]bool)
+	generator.labels = make(map[ast.Node]string)
+}
+
+// JavaScript precedences of the generated expressions, from the loosest
+const (
+	conditionalPrecedence = iota + 1
+	orPrecedence
+	andPrecedence
+	bitwiseOrPrecedence
+	equalityPrecedence
+	relationalPrecedence
+	additivePrecedence
+	multiplicativePrecedence
+	unaryPrecedence
+	primaryPrecedence
+)
+
+// binaryOperators are the JavaScript operators of the operators that map to one
+var binaryOperators = map[signature.Operator]string{
+	signature.ADD:               "+",
+	signature.SUBTRACT:          "-",
+	signature.MULTIPLY:          "*",
+	signature.DIVIDE:            "/",
+	signature.MODULO:            "%",
+	signature.LOGIC_AND:         "&&",
+	signature.LOGIC_OR:          "||",
+	signature.GREATER:           ">",
+	signature.GREATER_OR_EQUAL:  ">=",
+	signature.LESS:              "<",
+	signature.LESS_OR_EQUAL:     "<=",
+	signature.SHALLOW_EQUAL:     "===",
+	signature.DEEP_EQUAL:        "===",
+	signature.SHALLOW_NOT_EQUAL: "!==",
+	signature.DEEP_NOT_EQUAL:    "!==",
+}
+
+var binaryPrecedences = map[signature.Operator]int{
+	signature.ADD:               additivePrecedence,
+	signature.SUBTRACT:          additivePrecedence,
+	signature.MULTIPLY:          multiplicativePrecedence,
+	signature.DIVIDE:            multiplicativePrecedence,
+	signature.MODULO:            multiplicativePrecedence,
+	signature.LOGIC_AND:         andPrecedence,
+	signature.LOGIC_OR:          orPrecedence,
+	signature.GREATER:           relationalPrecedence,
+	signature.GREATER_OR_EQUAL:  relationalPrecedence,
+	signature.LESS:              relationalPrecedence,
+	signature.LESS_OR_EQUAL:     relationalPrecedence,
+	signature.SHALLOW_EQUAL:     equalityPrecedence,
+	signature.DEEP_EQUAL:        equalityPrecedence,
+	signature.SHALLOW_NOT_EQUAL: equalityPrecedence,
+	signature.DEEP_NOT_EQUAL:    equalityPrecedence,
+}
+
+// reservedNames are the JavaScript reserved words and the globals the generated code uses. A
+// variable with one of these names gets a trailing underscore.
+var reservedNames = map[string]bool{
+	"await": true, "break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "debugger": true, "default": true, "delete": true, "do": true,
+	"else": true, "enum": true, "export": true, "extends": true, "false": true,
+	"finally": true, "for": true, "function": true, "if": true, "implements": true,
+	"import": true, "in": true, "instanceof": true, "interface": true, "let": true,
+	"new": true, "null": true, "package": true, "private": true, "protected": true,
+	"public": true, "return": true, "static": true, "super": true, "switch": true,
+	"this": true, "throw": true, "true": true, "try": true, "typeof": true, "var": true,
+	"void": true, "while": true, "with": true, "yield": true,
+	"arguments": true, "eval": true, "undefined": true, "NaN": true, "Infinity": true,
+	"Math": true, "Number": true, "String": true, "JSON": true, "Error": true,
+	"console": true, "process": true, "isNaN": true, "isFinite": true,
+}
+
+func (generator *Generator) generateProgram(node *ast.ProgramNode) {
+	generator.write(runtime)
+	generator.labelBreakTargets(node.Chilren)
+	generator.generateStmts(node.Chilren)
+	generator.write("$expressive.flush();\n")
+}
+
+// labelBreakTargets labels the loops and switches exited by a break, so that every break names
+// the statement it exits
+func (generator *Generator) labelBreakTargets(stmts []ast.Node) {
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *ast.BlockNode:
+			generator.labelBreakTargets(stmt.Stmts)
+		case *ast.IfStmtNode:
+			generator.labelBreakTargets(stmt.ConditionBlocks)
+			generator.labelBreakTargets([]ast.Node{stmt.ElseBlock})
+		case *ast.WhileStmtNode:
+			generator.labelBreakTargets([]ast.Node{stmt.Block})
+		case *ast.ForStmtNode:
+			generator.labelBreakTargets([]ast.Node{stmt.Block})
+		case *ast.SwitchStmtNode:
+			generator.labelBreakTargets(stmt.CaseBlocks)
+			generator.labelBreakTargets([]ast.Node{stmt.DefaultBlock})
+		case *ast.BreakNode:
+			target := stmt.FindNearestValidStatementNode()
+
+			if _, ok := generator.labels[target]; !ok {
+				generator.labels[target] = fmt.Sprintf("%v_%d", statementName(target), len(generator.labels))
+			}
+		}
+	}
+}
+
+func statementName(node ast.Node) string {
+	switch node.(type) {
+	case *ast.WhileStmtNode:
+		return "while"
+	case *ast.ForStmtNode:
+		return "for"
+	default:
+		return "switch"
+	}
+}
+
+func (generator *Generator) generateStmts(stmts []ast.Node) {
+	for _, stmt := range stmts {
+		generator.generateStmt(stmt)
+	}
+}
+
+func (generator *Generator) generateStmt(node ast.Node) {
+	generator.startLine()
+	generator.mark(node)
+
+	switch stmt := node.(type) {
+	case *ast.BlockNode:
+		generator.write("{\n")
+		generator.generateBlockBody(stmt)
+		generator.startLine()
+		generator.write("}\n")
+	case *ast.VariableDeclarationNode, *ast.AssignmentNode, *ast.IncDecNode:
+		generator.generateSimpleStmt(stmt)
+		generator.write(";\n")
+	case *ast.PrintNode:
+		generator.write("$expressive.printf(")
+		generator.generateExpr(stmt.StringExpr, conditionalPrecedence)
+
+		for _, arg := range stmt.Args {
+			generator.write(", ")
+			generator.generateExpr(arg, conditionalPrecedence)
+		}
+
+		generator.write(");\n")
+	case *ast.IfStmtNode:
+		for i, conditionExpr := range stmt.ConditionExprs {
+			if i > 0 {
+				generator.write(" else ")
+				generator.mark(conditionExpr)
+			}
+
+			generator.write("if (")
+			generator.generateExpr(conditionExpr, 0)
+			generator.write(") {\n")
+			generator.generateBlockBody(stmt.ConditionBlocks[i])
+			generator.startLine()
+			generator.write("}")
+		}
+
+		if stmt.ElseBlock != nil {
+			generator.write(" else {\n")
+			generator.generateBlockBody(stmt.ElseBlock)
+			generator.startLine()
+			generator.write("}")
+		}
+
+		generator.write("\n")
+	case *ast.WhileStmtNode:
+		generator.writeLabel(stmt)
+		generator.write("while (")
+		generator.generateExpr(stmt.ConditionExpr, 0)
+		generator.write(") {\n")
+		generator.generateBlockBody(stmt.Block)
+		generator.startLine()
+		generator.write("}\n")
+	case *ast.ForStmtNode:
+		generator.writeLabel(stmt)
+		generator.write("for (")
+
+		if stmt.InitializationStmt != nil {
+			generator.generateSimpleStmt(stmt.InitializationStmt)
+		}
+
+		generator.write(";")
+
+		if stmt.ConditionExpr != nil {
+			generator.write(" ")
+			generator.generateExpr(stmt.ConditionExpr, 0)
+		}
+
+		generator.write(";")
+
+		if stmt.IterationStmt != nil {
+			generator.write(" ")
+			generator.generateSimpleStmt(stmt.IterationStmt)
+		}
+
+		generator.write(") {\n")
+		generator.generateBlockBody(stmt.Block)
+		generator.startLine()
+		generator.write("}\n")
+	case *ast.SwitchStmtNode:
+		generator.generateSwitchStmt(stmt)
+	case *ast.BreakNode:
+		generator.write(fmt.Sprintf("break %v;\n", generator.labels[stmt.FindNearestValidStatementNode()]))
+	case *ast.CallNode:
+		generator.unsupportedFunction(stmt)
+	case *ast.ExternNode:
+		generator.unsupportedFunction(stmt)
+	default:
+		panic(fmt.Sprintf("%v: unexpected statement %T", node.GetLocation(), node))
+	}
+}
+
+// generateSwitchStmt maps to a JavaScript switch, which also compares the cases in order with
+// strict equality and falls through cases without break
+func (generator *Generator) generateSwitchStmt(node *ast.SwitchStmtNode) {
+	generator.writeLabel(node)
+	generator.write("switch (")
+	generator.generateExpr(node.TestExpr, 0)
+	generator.write(") {\n")
+
+	generator.indent++
+
+	for i, caseExpr := range node.CaseExprs {
+		generator.startLine()
+		generator.mark(caseExpr)
+		generator.write("case ")
+		generator.generateExpr(caseExpr, 0)
+		generator.write(":")
+		generator.generateCaseBlock(node.CaseBlocks[i])
+	}
+
+	if !node.IsEmptyDefaultBlock() {
+		generator.startLine()
+		generator.write("default:")
+		generator.generateCaseBlock(node.DefaultBlock)
+	}
+
+	generator.indent--
+
+	generator.startLine()
+	generator.write("}\n")
+}
+
+// generateCaseBlock braces the block of a case, which is a scope of its own
+func (generator *Generator) generateCaseBlock(block ast.Node) {
+	if block.(*ast.BlockNode).IsEmptyBlock() {
+		generator.write("\n")
+		return
+	}
+
+	generator.write(" {\n")
+	generator.generateBlockBody(block)
+	generator.startLine()
+	generator.write("}\n")
+}
+
+func (generator *Generator) writeLabel(node ast.Node) {
+	if label, ok := generator.labels[node]; ok {
+		generator.write(label + ": ")
+	}
+}
+
+func (generator *Generator) generateBlockBody(node ast.Node) {
+	generator.indent++
+	generator.generateStmts(node.(*ast.BlockNode).Stmts)
+	generator.indent--
+}
+
+// generateSimpleStmt generates a statement that can appear in a for header, without semicolon
+func (generator *Generator) generateSimpleStmt(node ast.Node) {
+	switch stmt := node.(type) {
+	case *ast.VariableDeclarationNode:
+		identifier := stmt.Identifier.(*ast.IdentifierNode)
+
+		if stmt.Expr != nil && !identifier.GetBinding().IsVariable {
+			generator.write("const ")
+		} else {
+			generator.write("let ")
+		}
+
+		generator.mark(identifier)
+		generator.write(generator.declareName(identifier) + " = ")
+
+		if stmt.Expr == nil {
+			// Semantic analysis makes sure the variable is assigned before being read. The
+			// default value keeps its type anyway.
+			generator.write(defaultValue(identifier))
+		} else {
+			generator.generateExpr(stmt.Expr, conditionalPrecedence)
+		}
+	case *ast.AssignmentNode:
+		identifier := stmt.LHS.(*ast.IdentifierNode)
+
+		generator.generateExpr(identifier, primaryPrecedence)
+		generator.write(" = ")
+
+		if stmt.Operator == signature.VOID_OPERATOR {
+			generator.generateExpr(stmt.RHS, conditionalPrecedence)
+		} else {
+			generator.generateOperation(stmt, stmt.Operator, identifier.GetTyping(), identifier, stmt.RHS, conditionalPrecedence)
+		}
+	case *ast.IncDecNode:
+		identifier := stmt.LHS.(*ast.IdentifierNode)
+		operator := " - "
+
+		if stmt.IsIncrement {
+			operator = " + "
+		}
+
+		generator.generateExpr(identifier, primaryPrecedence)
+		generator.write(" = (")
+		generator.generateExpr(identifier, additivePrecedence)
+		generator.write(operator + "1) | 0")
+	default:
+		panic(fmt.Sprintf("%v: unexpected statement %T", node.GetLocation(), node))
+	}
+}
+
+// defaultValue is the value of a variable declared without initializer
+func defaultValue(identifier *ast.IdentifierNode) string {
+	switch identifier.GetTyping() {
+	case typing.INT, typing.FLOAT:
+		return "0"
+	case typing.BOOL:
+		return "false"
+	case typing.CHAR, typing.STRING:
+		return `""`
+	default:
+		panic(fmt.Sprintf("%v: no default value for type %v", identifier.GetLocation(), identifier.GetTyping()))
+	}
+}
+
+// declareName chooses the JavaScript name of a variable: its own name, unless it is reserved or
+// taken by another variable. Shadowing variables are renamed too, since a let is in its temporal
+// dead zone within its own initializer.
+func (generator *Generator) declareName(identifier *ast.IdentifierNode) string {
+	base := identifier.Tok.Raw
+
+	if reservedNames[base] {
+		base += "_"
+	}
+
+	name := base
+
+	for i := 2; generator.usedNames[name]; i++ {
+		name = fmt.Sprintf("%v_%d", base, i)
+	}
+
+	generator.usedNames[name] = true
+	generator.names[identifier.GetBinding()] = name
+
+	return name
+}
+
+// generateExpr generates node, parenthesized if its precedence is below the given one
+func (generator *Generator) generateExpr(node ast.Node, precedence int) {
+	generator.mark(node)
+
+	switch expr := node.(type) {
+	case *ast.IntegerNode:
+		generator.generateNumber(strconv.Itoa(expr.Val), precedence)
+	case *ast.FloatNode:
+		generator.generateNumber(strconv.FormatFloat(float64(expr.Val), 'g', -1, 64), precedence)
+	case *ast.BooleanNode:
+		generator.write(strconv.FormatBool(expr.Val))
+	case *ast.CharacterNode:
+		generator.write(stringLiteral(string(expr.Val)))
+	case *ast.StringNode:
+		stringValue := expr.StringValue()
+		generator.write(stringLiteral(stringValue[:len(stringValue)-1])) // without terminating character
+	case *ast.IdentifierNode:
+		name, ok := generator.names[expr.GetBinding()]
+
+		if !ok {
+			panic(fmt.Sprintf("%v: variable %v is not declared", expr.GetLocation(), expr.Tok.Raw))
+		}
+
+		generator.write(name)
+	case *ast.UnaryOperatorNode:
+		switch {
+		case expr.Operator == signature.LOGIC_NOT:
+			generator.parenthesize(precedence > unaryPrecedence, func() {
+				generator.write("!")
+				generator.generateExpr(expr.Expr, unaryPrecedence)
+			})
+		case expr.Operator == signature.NEGATE && expr.GetTyping() == typing.INT:
+			// the negation of the smallest int wraps around to itself
+			generator.parenthesize(precedence > bitwiseOrPrecedence, func() {
+				generator.write("(")
+				generator.generateNegation(expr.Expr)
+				generator.write(") | 0")
+			})
+		case expr.Operator == signature.NEGATE && expr.GetTyping() == typing.FLOAT:
+			generator.parenthesize(precedence > unaryPrecedence, func() {
+				generator.generateNegation(expr.Expr)
+			})
+		default:
+			panic(fmt.Sprintf("%v: cannot generate %v", node.GetLocation(), expr.Operator))
+		}
+	case *ast.BinaryOperatorNode:
+		generator.generateOperation(expr, expr.Operator, expr.Lhs.GetTyping(), expr.Lhs, expr.Rhs, precedence)
+	case *ast.TernaryOperatorNode:
+		generator.parenthesize(precedence > conditionalPrecedence, func() {
+			generator.generateExpr(expr.Expr1, orPrecedence)
+			generator.write(" ? ")
+			generator.generateExpr(expr.Expr2, conditionalPrecedence)
+			generator.write(" : ")
+			generator.generateExpr(expr.Expr3, conditionalPrecedence)
+		})
+	case *ast.CallNode:
+		generator.unsupportedFunction(expr)
+	default:
+		panic(fmt.Sprintf("%v: unexpected expression %T", node.GetLocation(), node))
+	}
+}
+
+// generateOperation applies a binary operator. Int results are truncated to 32 bits with | 0,
+// which also rounds divisions towards zero; int multiplications use Math.imul, whose result
+// would otherwise lose precision.
+func (generator *Generator) generateOperation(node ast.Node, operator signature.Operator, operandTyping typing.Typing, lhs ast.Node, rhs ast.Node, precedence int) {
+	call := func(function string) {
+		generator.write(function + "(")
+		generator.generateExpr(lhs, conditionalPrecedence)
+		generator.write(", ")
+		generator.generateExpr(rhs, conditionalPrecedence)
+		generator.write(")")
+	}
+
+	switch {
+	case operator == signature.EXPONENTIATE && operandTyping == typing.INT:
+		call("$expressive.powInt")
+		return
+	case operator == signature.EXPONENTIATE && operandTyping == typing.FLOAT:
+		call("Math.pow")
+		return
+	case operator == signature.MULTIPLY && operandTyping == typing.INT:
+		call("Math.imul")
+		return
+	}
+
+	jsOperator, ok := binaryOperators[operator]
+
+	if !ok {
+		panic(fmt.Sprintf("%v: cannot generate %v on %v", node.GetLocation(), operator, operandTyping))
+	}
+
+	operatorPrecedence := binaryPrecedences[operator]
+
+	generateBinary := func() {
+		generator.generateExpr(lhs, operatorPrecedence)
+		generator.write(" " + jsOperator + " ")
+		generator.generateExpr(rhs, operatorPrecedence+1)
+	}
+
+	if operandTyping == typing.INT && operatorPrecedence >= additivePrecedence {
+		generator.parenthesize(precedence > bitwiseOrPrecedence, func() {
+			generator.write("(")
+			generateBinary()
+			generator.write(") | 0")
+		})
+
+		return
+	}
+
+	generator.parenthesize(precedence > operatorPrecedence, generateBinary)
+}
+
+// generateNumber writes a number literal. A negative one is a negation in JavaScript.
+func (generator *Generator) generateNumber(literal string, precedence int) {
+	generator.parenthesize(strings.HasPrefix(literal, "-") && precedence > unaryPrecedence, func() {
+		generator.write(literal)
+	})
+}
+
+// generateNegation writes the negation of operand, parenthesizing an operand starting with a
+// minus, which would make a decrement
+func (generator *Generator) generateNegation(operand ast.Node) {
+	generator.write("-")
+	generator.generateExpr(operand, primaryPrecedence)
+}
+
+func (generator *Generator) parenthesize(parenthesize bool, generate func()) {
+	if parenthesize {
+		generator.write("(")
+	}
+
+	generate()
+
+	if parenthesize {
+		generator.write(")")
+	}
+}
+
+// mark maps the current position of the generated code to the position of node in its source
+func (generator *Generator) mark(node ast.Node) {
+	if node == nil || node.GetToken() == nil {
+		return
+	}
+
+	location, ok := node.GetToken().Locator.(*locator.FileLocation)
+
+	if !ok {
+		return
+	}
+
+	source := path.Join(location.DirName, location.FileName)
+	generator.sourceMap.add(generator.line, generator.column, source, location.Row, location.Col)
+}
+
+// startLine indents a new line
+func (generator *Generator) startLine() {
+	generator.write(strings.Repeat(indentation, generator.indent))
+}
+
+// unsupportedFunction records a call of a host function or an extern declaration, which
+// JavaScript programs cannot have
+func (generator *Generator) unsupportedFunction(node ast.Node) {
+	if generator.unsupported == nil {
+		generator.unsupported = node
+	}
+}
+
+func (generator *Generator) write(code string) {
+	generator.out.WriteString(code)
+
+	for _, r := range code {
+		if r == '\n' {
+			generator.line++
+			generator.column = 0
+		} else {
+			generator.column += len(utf16.Encode([]rune{r}))
+		}
+	}
+}
+
+// stringLiteral quotes s as a JavaScript string literal. Characters outside printable ascii are
+// escaped, including the line terminators U+2028 and U+2029.
+func stringLiteral(s string) string {
+	var literal strings.Builder
+
+	literal.WriteByte('"')
+
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			literal.WriteByte('\\')
+			literal.WriteRune(r)
+		case r == '\n':
+			literal.WriteString(`\n`)
+		case r == '\t':
+			literal.WriteString(`\t`)
+		case r >= ' ' && r < 0x7f:
+			literal.WriteRune(r)
+		default:
+			for _, unit := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&literal, "\\u%04x", unit)
+			}
+		}
+	}
+
+	literal.WriteByte('"')
+
+	return literal.String()
+}
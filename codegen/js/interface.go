@@ -0,0 +1,30 @@
+package js
+
+import (
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/logger"
+)
+
+// Generate ES2015 for ast. The bundled runtime comes first, and the source map is embedded at
+// the end. The program runs under node or in a browser.
+func Generate(node ast.Node, logger logger.Logger) string {
+	code, sourceMap, unsupported := generate(node)
+
+	if unsupported != nil {
+		logger.Log(unsupported.GetLocation(), "host and extern functions are not supported by the js target")
+		return ""
+	}
+
+	return code + "//# sourceMappingURL=" + sourceMap.dataURL() + "\n"
+}
+
+// generate returns the code and the source map of ast, and its first call of a host function
+// or extern declaration, which cannot be generated
+func generate(node ast.Node) (string, *SourceMap, ast.Node) {
+	var generator Generator
+	generator.Init()
+
+	generator.generateProgram(node.(*ast.ProgramNode))
+
+	return generator.out.String(), generator.sourceMap, generator.unsupported
+}
@@ -0,0 +1,101 @@
+package js
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/internal/testutil"
+	"github.com/carlcui/expressive/logger"
+)
+
+// testGolden compares the JavaScript generated for every program of dirName, without the
+// runtime, with the .js file of the same name in expectedDirName, and its source map with the
+// .js.map file
+func testGolden(dirName string, expectedDirName string, t *testing.T) {
+	testutil.Golden(dirName, expectedDirName, func(root ast.Node, fileName string) map[string]string {
+		code, sourceMap, _ := generate(root)
+
+		if !strings.HasPrefix(code, runtime) {
+			t.Fatalf("File %v: the runtime does not come first", fileName)
+		}
+
+		encoded, err := json.MarshalIndent(sourceMap, "", "  ")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return map[string]string{".js": strings.TrimPrefix(code, runtime), ".js.map": string(encoded) + "\n"}
+	}, t)
+}
+
+func TestE2ePrograms(t *testing.T) {
+	testGolden("../../e2e", "./testFiles/e2e", t)
+}
+
+func TestPrograms(t *testing.T) {
+	testGolden("./testFiles", "./testFiles", t)
+}
+
+func TestSourceMappingURL(t *testing.T) {
+	var buffer logger.Buffer
+
+	actual := Generate(testutil.AnalyzeFile("./testFiles", "features.exp", t), &buffer)
+
+	if !strings.Contains(actual, "\n//# sourceMappingURL=data:application/json;charset=utf-8;base64,") {
+		t.Errorf("Expecting the source map to be embedded, got\n%v", actual)
+	}
+}
+
+func TestVLQ(t *testing.T) {
+	cases := map[int]string{
+		0:    "A",
+		1:    "C",
+		-1:   "D",
+		15:   "e",
+		16:   "gB",
+		-16:  "hB",
+		1000: "w+B",
+	}
+
+	for value, expected := range cases {
+		var out strings.Builder
+		writeVLQ(&out, value)
+
+		if out.String() != expected {
+			t.Errorf("Expecting %v to be encoded as %v, got %v", value, expected, out.String())
+		}
+	}
+}
+
+func TestStringLiteral(t *testing.T) {
+	cases := map[string]string{
+		"plain":            `"plain"`,
+		"a\"b\\c\n":        `"a\"b\\c\n"`,
+		"\x01\u00e9":       `"\u0001\u00e9"`,
+		"\U0001f600\u2028": `"\ud83d\ude00\u2028"`,
+		"tab\there\r":      `"tab\there\u000d"`,
+	}
+
+	for s, expected := range cases {
+		if actual := stringLiteral(s); actual != expected {
+			t.Errorf("Expecting %q to be quoted as %v, got %v", s, expected, actual)
+		}
+	}
+}
+
+func TestUnsupportedFunctions(t *testing.T) {
+	src := "let a = 1;\nextern func puts(s: string) -> int;\nputs(\"x\");\nprint \"%d\", puts(\"y\");"
+
+	var buffer logger.Buffer
+
+	actual := Generate(testutil.AnalyzeSource(src, nil, t), &buffer)
+
+	// calls follow the declaration of their function, which is reported
+	if expected := "at 11: host and extern functions are not supported by the js target"; actual != "" || fmt.Sprint(buffer.Messages()) != fmt.Sprint([]string{expected}) {
+		t.Errorf("Expecting %q, got %v", expected, buffer.Messages())
+	}
+}
@@ -0,0 +1,276 @@
+package js
+
+// runtime is bundled at the top of every generated program. It implements print like C printf,
+// ints raised to a power, and writes output to stdout under node or line by line to the console
+// elsewhere. It only uses ES2015.
+const runtime = `"use strict";
+
+const $expressive = (function () {
+  let pending = "";
+
+  const hasStdout = typeof process === "object" && process !== null &&
+    typeof process.stdout === "object" && typeof process.stdout.write === "function";
+
+  function write(text) {
+    if (hasStdout) {
+      process.stdout.write(text);
+      return;
+    }
+
+    pending += text;
+
+    for (let newline = pending.indexOf("\n"); newline >= 0; newline = pending.indexOf("\n")) {
+      console.log(pending.slice(0, newline));
+      pending = pending.slice(newline + 1);
+    }
+  }
+
+  // flush writes the last line when it does not end with a new line
+  function flush() {
+    if (pending !== "") {
+      console.log(pending);
+      pending = "";
+    }
+  }
+
+  function repeat(text, count) {
+    return count > 0 ? text.repeat(count) : "";
+  }
+
+  function sign(negative, flags) {
+    if (negative) {
+      return "-";
+    }
+
+    if (flags.indexOf("+") >= 0) {
+      return "+";
+    }
+
+    return flags.indexOf(" ") >= 0 ? " " : "";
+  }
+
+  // pad applies the width of a conversion. Zeros go after the sign and radix prefix.
+  function pad(text, flags, width, zeros) {
+    if (text.length >= width) {
+      return text;
+    }
+
+    if (flags.indexOf("-") >= 0) {
+      return text + repeat(" ", width - text.length);
+    }
+
+    if (zeros && flags.indexOf("0") >= 0) {
+      const prefix = /^[-+ ]?(0[xX])?/.exec(text)[0];
+      return prefix + repeat("0", width - text.length) + text.slice(prefix.length);
+    }
+
+    return repeat(" ", width - text.length) + text;
+  }
+
+  function formatInt(verb, value, flags, precision) {
+    value = typeof value === "boolean" ? Number(value) : value | 0;
+
+    let prefix = "";
+    let digits;
+
+    if (verb === "d" || verb === "i") {
+      prefix = sign(value < 0, flags);
+      digits = String(Math.abs(value));
+    } else {
+      const unsigned = value >>> 0;
+
+      digits = unsigned.toString(verb === "o" ? 8 : verb === "u" ? 10 : 16);
+
+      if (verb === "X") {
+        digits = digits.toUpperCase();
+      }
+
+      if (flags.indexOf("#") >= 0 && unsigned !== 0 && (verb === "x" || verb === "X")) {
+        prefix = "0" + verb;
+      }
+    }
+
+    if (precision !== undefined) {
+      digits = precision === 0 && value === 0 ? "" : repeat("0", precision - digits.length) + digits;
+    }
+
+    if (verb === "o" && flags.indexOf("#") >= 0 && digits.charAt(0) !== "0") {
+      digits = "0" + digits;
+    }
+
+    return prefix + digits;
+  }
+
+  // toFixed and toExponential take at most 20 digits in ES2015
+  function fixed(value, precision) {
+    return value.toFixed(Math.min(precision, 20)) + repeat("0", precision - 20);
+  }
+
+  function exponential(value, precision) {
+    const text = value.toExponential(Math.min(precision, 20));
+    const e = text.indexOf("e");
+    const exponent = text.slice(e + 2);
+
+    return text.slice(0, e) + repeat("0", precision - 20) + "e" + text.charAt(e + 1) +
+      repeat("0", 2 - exponent.length) + exponent;
+  }
+
+  function general(value, precision, alternate) {
+    if (precision === 0) {
+      precision = 1;
+    }
+
+    const exponent = value === 0 ? 0 : Number(value.toExponential(precision - 1).split("e")[1]);
+
+    let text = exponent < -4 || exponent >= precision ?
+      exponential(value, precision - 1) : fixed(value, precision - 1 - exponent);
+
+    if (!alternate && text.indexOf(".") >= 0) {
+      const e = text.indexOf("e") >= 0 ? text.indexOf("e") : text.length;
+      text = text.slice(0, e).replace(/0+$/, "").replace(/\.$/, "") + text.slice(e);
+    }
+
+    return text;
+  }
+
+  function formatFloat(verb, value, flags, precision) {
+    const upper = verb === "F" || verb === "E" || verb === "G";
+    let text;
+
+    if (isNaN(value)) {
+      text = "nan";
+    } else if (!isFinite(value)) {
+      text = sign(value < 0, flags) + "inf";
+    } else {
+      const negative = value < 0 || (value === 0 && 1 / value < 0);
+      const magnitude = Math.abs(value);
+
+      if (precision === undefined) {
+        precision = 6;
+      }
+
+      switch (verb.toLowerCase()) {
+        case "f":
+          text = fixed(magnitude, precision);
+          break;
+        case "e":
+          text = exponential(magnitude, precision);
+          break;
+        default:
+          text = general(magnitude, precision, flags.indexOf("#") >= 0);
+      }
+
+      text = sign(negative, flags) + text;
+    }
+
+    return upper ? text.toUpperCase() : text;
+  }
+
+  function convert(verb, value, flags, width, precision) {
+    switch (verb) {
+      case "c":
+        return pad(typeof value === "string" ? value : String.fromCharCode(value & 0xff), flags, width, false);
+      case "s": {
+        const text = String(value);
+        return pad(precision === undefined ? text : text.slice(0, precision), flags, width, false);
+      }
+      case "f": case "F": case "e": case "E": case "g": case "G":
+        return pad(formatFloat(verb, value, flags, precision), flags, width, isFinite(value));
+      default:
+        return pad(formatInt(verb, value, flags, precision), flags, width, precision === undefined);
+    }
+  }
+
+  const conversion = /%([-+ #0]*)(\*|\d+)?(?:\.(\*|\d*))?[hlLqjzt]*([diuoxXcsfFeEgG%])/y;
+
+  function printf(format, ...args) {
+    let text = "";
+    let index = 0;
+    let next = 0;
+
+    function nextArg(verb) {
+      if (next >= args.length) {
+        throw new Error("missing argument for %" + verb + " in format " + JSON.stringify(format));
+      }
+
+      return args[next++];
+    }
+
+    while (index < format.length) {
+      const percent = format.indexOf("%", index);
+
+      if (percent < 0) {
+        text += format.slice(index);
+        break;
+      }
+
+      text += format.slice(index, percent);
+
+      conversion.lastIndex = percent;
+      const match = conversion.exec(format);
+
+      if (match === null) {
+        throw new Error("unsupported conversion in format " + JSON.stringify(format));
+      }
+
+      index = conversion.lastIndex;
+
+      let flags = match[1];
+      const verb = match[4];
+
+      if (verb === "%") {
+        text += "%";
+        continue;
+      }
+
+      // a width or precision given as * is taken from the arguments
+      let width = match[2] === "*" ? nextArg(verb) | 0 : Number(match[2] || 0);
+      let precision = match[3] === undefined ? undefined : match[3] === "*" ? nextArg(verb) | 0 : Number(match[3]);
+
+      if (width < 0) {
+        flags += "-";
+        width = -width;
+      }
+
+      if (precision < 0) {
+        precision = undefined;
+      }
+
+      text += convert(verb, nextArg(verb), flags, width, precision);
+    }
+
+    write(text);
+  }
+
+  // powInt raises base to exponent, wrapping around at 32 bits. A negative exponent truncates
+  // the result towards zero, as integer division does.
+  function powInt(base, exponent) {
+    if (exponent < 0) {
+      if (base === 1) {
+        return 1;
+      }
+
+      if (base === -1) {
+        return exponent % 2 === 0 ? 1 : -1;
+      }
+
+      return 0;
+    }
+
+    let result = 1;
+
+    for (; exponent > 0; exponent >>>= 1) {
+      if (exponent & 1) {
+        result = Math.imul(result, base);
+      }
+
+      base = Math.imul(base, base);
+    }
+
+    return result;
+  }
+
+  return { printf: printf, powInt: powInt, flush: flush };
+})();
+
+`
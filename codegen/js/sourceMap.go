@@ -0,0 +1,117 @@
+package js
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// SourceMap is a source map, revision 3, mapping positions of the generated code back to the
+// sources. Lines and columns start at 0.
+type SourceMap struct {
+	Version  int      `json:"version"`
+	Sources  []string `json:"sources"`
+	Names    []string `json:"names"`
+	Mappings string   `json:"mappings"`
+
+	sourceIndexes map[string]int
+	mappings      strings.Builder
+	line          int  // generated line of the last mapping
+	lineMapped    bool // whether the line has mappings yet
+	previous      mapping
+}
+
+// mapping maps a generated column to a position in a source. The fields of each mapping are
+// encoded relative to the previous one, the column only within its line.
+type mapping struct {
+	column       int
+	source       int
+	sourceLine   int
+	sourceColumn int
+}
+
+func newSourceMap() *SourceMap {
+	return &SourceMap{
+		Version:       3,
+		Sources:       make([]string, 0),
+		Names:         make([]string, 0),
+		sourceIndexes: make(map[string]int),
+	}
+}
+
+// add maps the generated position line:column to sourceLine:sourceColumn of source. Positions
+// must be added in the order of the generated code.
+func (sourceMap *SourceMap) add(line int, column int, source string, sourceLine int, sourceColumn int) {
+	sourceIndex, ok := sourceMap.sourceIndexes[source]
+
+	if !ok {
+		sourceIndex = len(sourceMap.Sources)
+		sourceMap.Sources = append(sourceMap.Sources, source)
+		sourceMap.sourceIndexes[source] = sourceIndex
+	}
+
+	if line > sourceMap.line {
+		sourceMap.mappings.WriteString(strings.Repeat(";", line-sourceMap.line))
+		sourceMap.line = line
+		sourceMap.lineMapped = false
+		sourceMap.previous.column = 0
+	}
+
+	if sourceMap.lineMapped {
+		if column == sourceMap.previous.column {
+			return // the position is already mapped
+		}
+
+		sourceMap.mappings.WriteByte(',')
+	}
+
+	sourceMap.lineMapped = true
+
+	current := mapping{column, sourceIndex, sourceLine, sourceColumn}
+
+	writeVLQ(&sourceMap.mappings, current.column-sourceMap.previous.column)
+	writeVLQ(&sourceMap.mappings, current.source-sourceMap.previous.source)
+	writeVLQ(&sourceMap.mappings, current.sourceLine-sourceMap.previous.sourceLine)
+	writeVLQ(&sourceMap.mappings, current.sourceColumn-sourceMap.previous.sourceColumn)
+
+	sourceMap.previous = current
+	sourceMap.Mappings = sourceMap.mappings.String()
+}
+
+// dataURL embeds the source map in a comment of the generated code
+func (sourceMap *SourceMap) dataURL() string {
+	encoded, err := json.Marshal(sourceMap)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return "data:application/json;charset=utf-8;base64," + base64.StdEncoding.EncodeToString(encoded)
+}
+
+const base64Digits = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// writeVLQ writes value as a base64 variable length quantity: the sign in the lowest bit, then
+// groups of 5 bits from the lowest, each with a continuation bit
+func writeVLQ(out *strings.Builder, value int) {
+	vlq := value << 1
+
+	if value < 0 {
+		vlq = (-value << 1) | 1
+	}
+
+	for {
+		digit := vlq & 31
+		vlq >>= 5
+
+		if vlq > 0 {
+			digit |= 32
+		}
+
+		out.WriteByte(base64Digits[digit])
+
+		if vlq == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,3 @@
+let a = 1.100000023841858 + 2.200000047683716;
+$expressive.printf("1.1 + 2.2 = %.1f\n", a);
+$expressive.flush();
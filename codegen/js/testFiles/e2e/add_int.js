@@ -0,0 +1,3 @@
+let a = (5 + 6) | 0;
+$expressive.printf("5 + 6 = %d\n", a);
+$expressive.flush();
@@ -0,0 +1,21 @@
+let a = true;
+let b = false;
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("\n");
+a = true;
+b = true;
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("\n");
+a = false;
+b = false;
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.flush();
@@ -0,0 +1,12 @@
+let i = 0;
+let j = 0;
+for (i = 0; i < 5; i = (i + 1) | 0) {
+  for_0: for (j = 0; j < 5; j = (j + 1) | 0) {
+    $expressive.printf("%d %d\n", i, j);
+    if (i > 2) {
+      break for_0;
+    }
+  }
+}
+$expressive.printf("%d %d\n", i, j);
+$expressive.flush();
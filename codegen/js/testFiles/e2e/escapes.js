@@ -0,0 +1,4 @@
+$expressive.printf("tab:\t|quotes:\"'|backslash:\\|not a new line:\\n|\n");
+$expressive.printf("hex:Ab unicode:\u00e9\ud83d\ude00\n");
+$expressive.printf("%s%s%s%s%s\n", "a", "B", "'", "\"", "\u263a");
+$expressive.flush();
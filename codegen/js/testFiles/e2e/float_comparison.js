@@ -0,0 +1,33 @@
+let a = 5.5;
+let b = 6.599999904632568;
+$expressive.printf("%d\n", a > b);
+$expressive.printf("%d\n", a < b);
+$expressive.printf("%d\n", a >= b);
+$expressive.printf("%d\n", a <= b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("\n");
+a = 6.599999904632568;
+b = 5.5;
+$expressive.printf("%d\n", a > b);
+$expressive.printf("%d\n", a < b);
+$expressive.printf("%d\n", a >= b);
+$expressive.printf("%d\n", a <= b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("\n");
+a = 5;
+b = 5;
+$expressive.printf("%d\n", a > b);
+$expressive.printf("%d\n", a < b);
+$expressive.printf("%d\n", a >= b);
+$expressive.printf("%d\n", a <= b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.flush();
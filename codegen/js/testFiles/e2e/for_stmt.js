@@ -0,0 +1,13 @@
+let i = 0;
+for (i = 0; i < 5; i = (i + 1) | 0) {
+  $expressive.printf("%d\n", i);
+}
+for (let i_2 = 5; i_2 >= 0; i_2 = (i_2 - 1) | 0) {
+  $expressive.printf("%d\n", i_2);
+}
+for (let j = 0; j < 10; j = (j + 1) | 0) {
+  if (j > 5) {
+    $expressive.printf("%d\n", j);
+  }
+}
+$expressive.flush();
@@ -0,0 +1,69 @@
+let a = 5;
+if (a > 4) {
+  $expressive.printf("test1-true\n");
+} else {
+  $expressive.printf("test1-false");
+}
+if (a > 5) {
+  $expressive.printf("test2-false");
+} else {
+  $expressive.printf("test2-true\n");
+}
+if (a > 1) {
+  $expressive.printf("test3-true\n");
+}
+if (a < 1) {
+  $expressive.printf("test4-false");
+}
+if (a < 3) {
+  $expressive.printf("test5-false");
+} else if (a > 4) {
+  $expressive.printf("test5-true\n");
+}
+if (a < 3) {
+  $expressive.printf("test6-false");
+} else if (a > 4) {
+  $expressive.printf("test6-true\n");
+} else {
+  $expressive.printf("test6-false");
+}
+if (a < 3) {
+  $expressive.printf("test7-false");
+} else if (a < 2) {
+  $expressive.printf("test7-false");
+} else {
+  $expressive.printf("test7-true\n");
+}
+if (a < 3) {
+  $expressive.printf("test8-false");
+} else if (a < 2) {
+  $expressive.printf("test8-false");
+} else if (a < 2) {
+  $expressive.printf("test8-false");
+} else if (a < 2) {
+  $expressive.printf("test8-false");
+} else if (a < 2) {
+  $expressive.printf("test8-false");
+} else if (a < 2) {
+  $expressive.printf("test8-false");
+} else if (a < 2) {
+  $expressive.printf("test8-false");
+} else if (a < 2) {
+  $expressive.printf("test8-false");
+} else if (a < 2) {
+  $expressive.printf("test8-false");
+} else if (a < 2) {
+  $expressive.printf("test8-false");
+} else {
+  $expressive.printf("test8-true\n");
+}
+$expressive.printf("%d\n", a);
+if (a > 2) {
+  $expressive.printf("%d\n", a);
+}
+if (a > 2) {
+  let a_2 = 7;
+  $expressive.printf("%d\n", a_2);
+}
+$expressive.printf("%d\n", a);
+$expressive.flush();
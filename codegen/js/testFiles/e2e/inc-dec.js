@@ -0,0 +1,7 @@
+let a = 5;
+$expressive.printf("%d\n", a);
+a = (a + 1) | 0;
+$expressive.printf("%d\n", a);
+a = (a - 1) | 0;
+$expressive.printf("%d\n", a);
+$expressive.flush();
@@ -0,0 +1,58 @@
+let a = 0;
+let b = 0;
+a = 5;
+b = 6;
+$expressive.printf("%d\n", a > b);
+$expressive.printf("%d\n", a < b);
+$expressive.printf("%d\n", a >= b);
+$expressive.printf("%d\n", a <= b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("\n");
+a = 6;
+b = 5;
+$expressive.printf("%d\n", a > b);
+$expressive.printf("%d\n", a < b);
+$expressive.printf("%d\n", a >= b);
+$expressive.printf("%d\n", a <= b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("\n");
+a = 5;
+b = 5;
+$expressive.printf("%d\n", a > b);
+$expressive.printf("%d\n", a < b);
+$expressive.printf("%d\n", a >= b);
+$expressive.printf("%d\n", a <= b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("\n");
+a = -5;
+b = 6;
+$expressive.printf("%d\n", a > b);
+$expressive.printf("%d\n", a < b);
+$expressive.printf("%d\n", a >= b);
+$expressive.printf("%d\n", a <= b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("\n");
+a = 5;
+b = -6;
+$expressive.printf("%d\n", a > b);
+$expressive.printf("%d\n", a < b);
+$expressive.printf("%d\n", a >= b);
+$expressive.printf("%d\n", a <= b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("%d\n", a === b);
+$expressive.printf("%d\n", a !== b);
+$expressive.printf("\n");
+$expressive.flush();
@@ -0,0 +1,20 @@
+$expressive.printf("%d\n", true);
+$expressive.printf("%d\n", false);
+$expressive.printf("\n");
+$expressive.printf("%d\n", true && true);
+$expressive.printf("%d\n", true && false);
+$expressive.printf("%d\n", false && false);
+$expressive.printf("%d\n", false && true);
+$expressive.printf("\n");
+$expressive.printf("%d\n", true || true);
+$expressive.printf("%d\n", true || false);
+$expressive.printf("%d\n", false || false);
+$expressive.printf("%d\n", false || true);
+$expressive.printf("\n");
+$expressive.printf("%d\n", !true);
+$expressive.printf("%d\n", !false);
+$expressive.printf("\n");
+$expressive.printf("%d\n", !(true && false));
+$expressive.printf("%d\n", true && !false || false);
+$expressive.printf("%d\n", true && !false || (false || true));
+$expressive.flush();
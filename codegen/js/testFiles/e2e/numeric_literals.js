@@ -0,0 +1,9 @@
+let flags = 10;
+let mask = 31;
+let mode = 493;
+let million = 1000000;
+$expressive.printf("%d %d %d %d\n", flags, mask, mode, million);
+$expressive.printf("%d %d\n", 65535, -2147483648);
+$expressive.printf("%f %f %f %f\n", 0.5, 5, 2500, 1000.25);
+$expressive.printf("%d\n", 0.0010000000474974513 < 0.009999999776482582);
+$expressive.flush();
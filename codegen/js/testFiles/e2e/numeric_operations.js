@@ -0,0 +1,22 @@
+$expressive.printf("%d\n", (5 + 6) | 0);
+$expressive.printf("%d\n", (((5 + 6) | 0) + 7) | 0);
+$expressive.printf("%d\n", (((5 + 6) | 0) - 4) | 0);
+$expressive.printf("%d\n", (((5 - 5) | 0) + 5) | 0);
+$expressive.printf("%d\n", (5 - 6) | 0);
+$expressive.printf("%d\n", (((5 - 6) | 0) - 5) | 0);
+$expressive.printf("%d\n", (5 + ((4 - 3) | 0)) | 0);
+$expressive.printf("%d\n", Math.imul(5, 6));
+$expressive.printf("%d\n", (5 / 6) | 0);
+$expressive.printf("%d\n", (6 / 6) | 0);
+$expressive.printf("%d\n", (6 % 5) | 0);
+$expressive.printf("%d\n", (12 / 4) | 0);
+$expressive.printf("%d\n", Math.imul(3, (5 + 6) | 0));
+$expressive.printf("%d\n", Math.imul((5 + 6) | 0, (4 + 3) | 0));
+$expressive.printf("%d\n", (5 + Math.imul(4, 2)) | 0);
+$expressive.printf("\n");
+$expressive.printf("%.1f\n", 1.100000023841858 + 2.200000047683716);
+$expressive.printf("%.1f\n", 1.100000023841858 - 2.200000047683716);
+$expressive.printf("%.1f\n", 2.200000047683716 - 1.100000023841858);
+$expressive.printf("%.1f\n", 1 * 3);
+$expressive.printf("%.1f\n", 3 / 1);
+$expressive.flush();
@@ -0,0 +1,17 @@
+let a = 5;
+let b = 0;
+switch_0: switch ((a + 1) | 0) {
+  case 1: {
+    b = 3;
+    break switch_0;
+  }
+  case 6: {
+    b = 6;
+    break switch_0;
+  }
+  default: {
+    b = 7;
+  }
+}
+$expressive.printf("%d\n", b);
+$expressive.flush();
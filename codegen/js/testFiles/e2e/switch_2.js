@@ -0,0 +1,14 @@
+let a = 5;
+let b = 0;
+switch_0: switch (true) {
+  case a > 7: {
+    b = 7;
+    break switch_0;
+  }
+  case a >= 0 && a <= 7: {
+    b = 10;
+    break switch_0;
+  }
+}
+$expressive.printf("%d\n", b);
+$expressive.flush();
@@ -0,0 +1,16 @@
+let a = 5;
+let b = 0;
+switch_0: switch (a) {
+  case 5:
+  case 6:
+  case 7: {
+    b = 7;
+    break switch_0;
+  }
+  case 8: {
+    b = 8;
+    break switch_0;
+  }
+}
+$expressive.printf("%d\n", b);
+$expressive.flush();
@@ -0,0 +1,17 @@
+let a = 5;
+let b = 0;
+switch_0: switch (a) {
+  case 1: {
+    b = 1;
+    break switch_0;
+  }
+  case 2: {
+    b = 2;
+    break switch_0;
+  }
+  default: {
+    b = 3;
+  }
+}
+$expressive.printf("%d\n", b);
+$expressive.flush();
@@ -0,0 +1,29 @@
+let a = 5;
+let b = 0;
+switch (a) {
+  case 1: {
+    b = 1;
+  }
+  case 5: {
+    b = 5;
+    $expressive.printf("%d\n", b);
+  }
+  case 6: {
+    b = 6;
+    $expressive.printf("%d\n", b);
+  }
+  case 7: {
+    b = 7;
+    $expressive.printf("%d\n", b);
+  }
+  case 8: {
+    b = 8;
+    $expressive.printf("%d\n", b);
+  }
+  default: {
+    b = 9;
+    $expressive.printf("%d\n", b);
+  }
+}
+$expressive.printf("%d\n", b);
+$expressive.flush();
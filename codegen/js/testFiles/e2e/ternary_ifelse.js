@@ -0,0 +1,4 @@
+const a = 55;
+const b = 66;
+$expressive.printf("%d\n", a > b ? a : b);
+$expressive.flush();
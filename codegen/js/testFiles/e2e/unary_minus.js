@@ -0,0 +1,9 @@
+let a = 5;
+let x = 2.5;
+let smallest = -2147483648;
+$expressive.printf("%d %d %d\n", (a - 1) | 0, (a - 1) | 0, (a - -1) | 0);
+$expressive.printf("%d %d %d\n", (-a) | 0, (-((-a) | 0)) | 0, Math.imul((-((a + 1) | 0)) | 0, 2));
+$expressive.printf("%d %d\n", smallest, Math.imul((-a) | 0, (-a) | 0));
+$expressive.printf("%f %f %f\n", -x, x - 1.5, -(x * 2));
+$expressive.printf("%d %d\n", ((-a) | 0) < 0, !(((-a) | 0) > 0));
+$expressive.flush();
@@ -0,0 +1,11 @@
+let a = 5;
+$expressive.printf("%d\n", a);
+a = 6;
+$expressive.printf("%d\n", a);
+a = (5 + 7) | 0;
+$expressive.printf("%d\n", a);
+a = (a + 3) | 0;
+$expressive.printf("%d\n", a);
+a = (a - 5) | 0;
+$expressive.printf("%d\n", a);
+$expressive.flush();
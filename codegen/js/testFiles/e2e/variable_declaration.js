@@ -0,0 +1,7 @@
+let a = 0;
+let b = 5;
+let c = 5;
+let d = 0;
+const e = 5.5;
+const f = 5.599999904632568;
+$expressive.flush();
@@ -0,0 +1,7 @@
+let a = 5;
+while (a < 10) {
+  $expressive.printf("%d", a);
+  a = (a + 1) | 0;
+}
+$expressive.printf("\n");
+$expressive.flush();
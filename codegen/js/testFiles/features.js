@@ -0,0 +1,35 @@
+let register = $expressive.powInt(2, 10);
+let auto = Math.pow(2, 3);
+let expressive_name = "who" + "??" + "?";
+let c = "c";
+$expressive.printf("%d %f %s\n", register, auto, expressive_name);
+for_1: for (let i = 0; i < 5; i = (i + 1) | 0) {
+  let s = ((i % 2) | 0) === 0 ? "even" : "odd";
+  switch_0: switch (s) {
+    case "even": {
+      if (i === 4) {
+        break switch_0;
+      }
+      $expressive.printf("%d is even\n", i);
+    }
+    default: {
+      $expressive.printf("%d after even\n", i);
+    }
+  }
+  if (i === 3) {
+    break for_1;
+  }
+}
+while_2: while (c === "c") {
+  let c_2 = "d";
+  $expressive.printf("%s shadows\n", c_2);
+  break while_2;
+}
+let x = 2147483647;
+x = (x + 1) | 0;
+$expressive.printf("%d %d\n", x, (((0 - 7) | 0) / 2) | 0);
+if (true) {
+  let x_2 = (x + 1) | 0;
+  $expressive.printf("%d\n", x_2);
+}
+$expressive.flush();
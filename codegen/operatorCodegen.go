@@ -3,11 +3,13 @@ package codegen
 import (
 	"fmt"
 
+	"github.com/carlcui/expressive/ast"
 	"github.com/carlcui/expressive/signature"
 	"github.com/carlcui/expressive/typing"
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
 )
 
@@ -19,6 +21,8 @@ type OperatorCodegen struct {
 	labeller   *Labeller
 	compIPreds map[string]enum.IPred
 	compFPreds map[string]enum.FPred
+	checks     *RuntimeChecks // nil unless int operations are checked
+	node       ast.Node       // where failed checks are located
 }
 
 func NewOperatorCodegen(fragment *BlocksFragment, operator signature.Operator, typing typing.Typing, labeller *Labeller, operands ...Fragment) *OperatorCodegen {
@@ -55,9 +59,17 @@ func NewOperatorCodegen(fragment *BlocksFragment, operator signature.Operator, t
 		labeller,
 		compIPreds,
 		compFPreds,
+		nil,
+		nil,
 	}
 }
 
+// EnableChecks guards the int operations generated for node with checks
+func (gen *OperatorCodegen) EnableChecks(checks *RuntimeChecks, node ast.Node) {
+	gen.checks = checks
+	gen.node = node
+}
+
 func (gen *OperatorCodegen) GenerateCode() {
 	switch gen.operator {
 	case signature.ADD:
@@ -76,6 +88,8 @@ func (gen *OperatorCodegen) GenerateCode() {
 		gen.generateLogicalOr()
 	case signature.LOGIC_NOT:
 		gen.generateLogicalNot()
+	case signature.NEGATE:
+		gen.generateNegate()
 	case signature.IF_ELSE:
 		gen.generateIfElse()
 	case signature.GREATER, signature.GREATER_OR_EQUAL,
@@ -167,6 +181,18 @@ func (gen *OperatorCodegen) generateComparison() {
 
 func (gen *OperatorCodegen) generateBinary(instrFunction func(value.Value, value.Value) value.Value) {
 	frag := gen.fragment
+	op1, op2 := gen.appendOperands()
+
+	result := instrFunction(op1, op2)
+	instr := result.(ir.Instruction)
+	frag.CurrentBlock.Insts = append(frag.CurrentBlock.Insts, instr)
+
+	frag.resultValue = result
+}
+
+// appendOperands appends the fragments of two operands, and returns their results
+func (gen *OperatorCodegen) appendOperands() (value.Value, value.Value) {
+	frag := gen.fragment
 
 	if len(frag.Blocks) == 0 {
 		frag.NewBlock("")
@@ -183,14 +209,33 @@ func (gen *OperatorCodegen) generateBinary(instrFunction func(value.Value, value
 	frag.Append(frag1)
 	frag.Append(frag2)
 
-	result := instrFunction(op1, op2)
-	instr := result.(ir.Instruction)
-	frag.CurrentBlock.Insts = append(frag.CurrentBlock.Insts, instr)
+	return op1, op2
+}
 
-	frag.resultValue = result
+// generateChecked computes an int operation with an overflow intrinsic, one of sadd, ssub or
+// smul, failing on overflow
+func (gen *OperatorCodegen) generateChecked(operation string) {
+	op1, op2 := gen.appendOperands()
+
+	gen.fragment.resultValue = gen.checks.overflowing(gen.fragment, operation, op1, op2, gen.node)
+}
+
+// checkDivisor makes a division fail, instead of being undefined, when its operands cannot
+// be divided
+func (gen *OperatorCodegen) checkDivisor(instrFunction func(value.Value, value.Value) value.Value) func(value.Value, value.Value) value.Value {
+	return func(op1, op2 value.Value) value.Value {
+		gen.checks.divisor(gen.fragment, op1, op2, gen.node)
+
+		return instrFunction(op1, op2)
+	}
 }
 
 func (gen *OperatorCodegen) generateAdd() {
+	if gen.checks != nil && gen.typing == typing.INT {
+		gen.generateChecked("sadd")
+		return
+	}
+
 	var instr func(value.Value, value.Value) value.Value
 
 	switch gen.typing {
@@ -210,6 +255,11 @@ func (gen *OperatorCodegen) generateAdd() {
 }
 
 func (gen *OperatorCodegen) generateSubtract() {
+	if gen.checks != nil && gen.typing == typing.INT {
+		gen.generateChecked("ssub")
+		return
+	}
+
 	var instr func(value.Value, value.Value) value.Value
 
 	switch gen.typing {
@@ -229,6 +279,11 @@ func (gen *OperatorCodegen) generateSubtract() {
 }
 
 func (gen *OperatorCodegen) generateMultiply() {
+	if gen.checks != nil && gen.typing == typing.INT {
+		gen.generateChecked("smul")
+		return
+	}
+
 	var instr func(value.Value, value.Value) value.Value
 	switch gen.typing {
 	case typing.INT:
@@ -261,6 +316,10 @@ func (gen *OperatorCodegen) generateDivide() {
 		gen.panicOnMismatchCodegen()
 	}
 
+	if gen.checks != nil && gen.typing == typing.INT {
+		instr = gen.checkDivisor(instr)
+	}
+
 	gen.generateBinary(instr)
 }
 
@@ -275,6 +334,10 @@ func (gen *OperatorCodegen) generateRemainder() {
 		gen.panicOnMismatchCodegen()
 	}
 
+	if gen.checks != nil && gen.typing == typing.INT {
+		instr = gen.checkDivisor(instr)
+	}
+
 	gen.generateBinary(instr)
 }
 
@@ -403,6 +466,40 @@ func (gen *OperatorCodegen) generateLogicalNot() {
 	frag.resultValue = result
 }
 
+// generateNegate subtracts an int from zero, which overflows for the smallest int, and flips the
+// sign of a float
+func (gen *OperatorCodegen) generateNegate() {
+	gen.checkOperandsLength(1)
+
+	frag := gen.fragment
+
+	if len(frag.Blocks) == 0 {
+		frag.NewBlock("")
+	}
+
+	frag1 := gen.operands[0]
+
+	op1 := frag1.GetResult()
+
+	frag.Append(frag1)
+
+	switch gen.typing {
+	case typing.INT:
+		zero := constant.NewInt(gen.typing.IrType().(*types.IntType), 0)
+
+		if gen.checks != nil {
+			frag.resultValue = gen.checks.overflowing(frag, "ssub", zero, op1, gen.node)
+			return
+		}
+
+		frag.resultValue = frag.CurrentBlock.NewSub(zero, op1)
+	case typing.FLOAT:
+		frag.resultValue = frag.CurrentBlock.NewFNeg(op1)
+	default:
+		gen.panicOnMismatchCodegen()
+	}
+}
+
 func (gen *OperatorCodegen) generateIfElse() {
 
 	gen.checkOperandsLength(3)
@@ -0,0 +1,183 @@
+package passes
+
+import (
+	"github.com/llir/llvm/ir"
+)
+
+// cfg is the control flow graph of the blocks of a function reachable from its entry
+type cfg struct {
+	order []*ir.Block // reverse postorder, starting with the entry
+	index map[*ir.Block]int
+	preds map[*ir.Block][]*ir.Block // without duplicates
+	succs map[*ir.Block][]*ir.Block // without duplicates
+}
+
+func newCFG(function *ir.Func) *cfg {
+	graph := &cfg{
+		index: make(map[*ir.Block]int),
+		preds: make(map[*ir.Block][]*ir.Block),
+		succs: make(map[*ir.Block][]*ir.Block),
+	}
+
+	visited := make(map[*ir.Block]bool)
+
+	var postorder []*ir.Block
+
+	var visit func(block *ir.Block)
+
+	visit = func(block *ir.Block) {
+		visited[block] = true
+
+		for _, succ := range block.Term.Succs() {
+			if !contains(graph.succs[block], succ) {
+				graph.succs[block] = append(graph.succs[block], succ)
+				graph.preds[succ] = append(graph.preds[succ], block)
+			}
+
+			if !visited[succ] {
+				visit(succ)
+			}
+		}
+
+		postorder = append(postorder, block)
+	}
+
+	visit(function.Blocks[0])
+
+	for i := len(postorder) - 1; i >= 0; i-- {
+		graph.index[postorder[i]] = len(graph.order)
+		graph.order = append(graph.order, postorder[i])
+	}
+
+	return graph
+}
+
+func (graph *cfg) reachable(block *ir.Block) bool {
+	_, ok := graph.index[block]
+
+	return ok
+}
+
+// dominators returns the immediate dominator of every block but the entry, computed as in
+// "A Simple, Fast Dominance Algorithm" by Cooper, Harvey and Kennedy
+func (graph *cfg) dominators() map[*ir.Block]*ir.Block {
+	entry := graph.order[0]
+	idom := map[*ir.Block]*ir.Block{entry: entry}
+
+	intersect := func(a *ir.Block, b *ir.Block) *ir.Block {
+		for a != b {
+			for graph.index[a] > graph.index[b] {
+				a = idom[a]
+			}
+
+			for graph.index[b] > graph.index[a] {
+				b = idom[b]
+			}
+		}
+
+		return a
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, block := range graph.order[1:] {
+			var newIdom *ir.Block
+
+			for _, pred := range graph.preds[block] {
+				if _, ok := idom[pred]; !ok {
+					continue
+				}
+
+				if newIdom == nil {
+					newIdom = pred
+				} else {
+					newIdom = intersect(pred, newIdom)
+				}
+			}
+
+			if idom[block] != newIdom {
+				idom[block] = newIdom
+				changed = true
+			}
+		}
+	}
+
+	delete(idom, entry)
+
+	return idom
+}
+
+// frontiers returns the dominance frontier of every block: the blocks it does not strictly
+// dominate but dominates a predecessor of
+func (graph *cfg) frontiers(idom map[*ir.Block]*ir.Block) map[*ir.Block][]*ir.Block {
+	frontiers := make(map[*ir.Block][]*ir.Block)
+
+	for _, block := range graph.order {
+		preds := graph.preds[block]
+
+		if len(preds) < 2 {
+			continue
+		}
+
+		for _, runner := range preds {
+			for runner != idom[block] && !contains(frontiers[runner], block) {
+				frontiers[runner] = append(frontiers[runner], block)
+
+				if runner == graph.order[0] {
+					break
+				}
+
+				runner = idom[runner]
+			}
+		}
+	}
+
+	return frontiers
+}
+
+func contains(blocks []*ir.Block, block *ir.Block) bool {
+	for _, b := range blocks {
+		if b == block {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeUnreachableBlocks removes the blocks of function that are never entered, and tells
+// whether there were any
+func removeUnreachableBlocks(function *ir.Func) bool {
+	graph := newCFG(function)
+
+	if len(graph.order) == len(function.Blocks) {
+		return false
+	}
+
+	blocks := function.Blocks[:0]
+
+	for _, block := range function.Blocks {
+		if graph.reachable(block) {
+			blocks = append(blocks, block)
+		}
+	}
+
+	for _, block := range blocks {
+		for _, phi := range phis(block) {
+			incomings := phi.Incs[:0]
+
+			for _, incoming := range phi.Incs {
+				if graph.reachable(incoming.Pred.(*ir.Block)) {
+					incomings = append(incomings, incoming)
+				}
+			}
+
+			phi.Incs = incomings
+		}
+	}
+
+	function.Blocks = blocks
+
+	return true
+}
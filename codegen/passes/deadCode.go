@@ -0,0 +1,53 @@
+package passes
+
+import (
+	"github.com/llir/llvm/ir"
+)
+
+// EliminateDeadCode removes the instructions of function whose result is never used. Stores,
+// calls and terminators are kept for their effects, and so is everything they use.
+func EliminateDeadCode(function *ir.Func) {
+	live := make(map[ir.Instruction]bool)
+
+	var work []interface{}
+
+	mark := func(instruction interface{}) {
+		for _, use := range operands(instruction) {
+			if inst, ok := used(*use).(ir.Instruction); ok && !live[inst] {
+				live[inst] = true
+				work = append(work, inst)
+			}
+		}
+	}
+
+	for _, block := range function.Blocks {
+		for _, instruction := range block.Insts {
+			switch instruction.(type) {
+			case *ir.InstStore, *ir.InstCall:
+				live[instruction] = true
+				work = append(work, instruction)
+			}
+		}
+
+		work = append(work, block.Term)
+	}
+
+	for len(work) > 0 {
+		instruction := work[len(work)-1]
+		work = work[:len(work)-1]
+
+		mark(instruction)
+	}
+
+	for _, block := range function.Blocks {
+		instructions := block.Insts[:0]
+
+		for _, instruction := range block.Insts {
+			if live[instruction] {
+				instructions = append(instructions, instruction)
+			}
+		}
+
+		block.Insts = instructions
+	}
+}
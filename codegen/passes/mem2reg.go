@@ -0,0 +1,259 @@
+package passes
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/value"
+)
+
+// Mem2Reg promotes the variables of function, allocated on the stack and only loaded and
+// stored, to registers: every load is replaced by the value last stored, and phi instructions
+// are inserted where the values stored on different paths meet, as in "Efficiently Computing
+// Static Single Assignment Form" by Cytron et al. A variable described to a debugger is not
+// promoted, so that it can still be inspected.
+func Mem2Reg(function *ir.Func) {
+	removeUnreachableBlocks(function)
+
+	allocas := promotableAllocas(function)
+
+	if len(allocas) == 0 {
+		return
+	}
+
+	graph := newCFG(function)
+	idom := graph.dominators()
+	frontiers := graph.frontiers(idom)
+
+	promoted := make(map[*ir.InstAlloca]bool)
+
+	for _, alloca := range allocas {
+		promoted[alloca] = true
+	}
+
+	inserted := insertPhis(function, allocas, frontiers)
+
+	children := make(map[*ir.Block][]*ir.Block)
+
+	for _, block := range graph.order[1:] {
+		children[idom[block]] = append(children[idom[block]], block)
+	}
+
+	replacements := make(map[value.Value]value.Value)
+
+	// rename walks the dominator tree, knowing the value of every variable on entering block
+	var rename func(block *ir.Block, current map[*ir.InstAlloca]value.Value)
+
+	rename = func(block *ir.Block, current map[*ir.InstAlloca]value.Value) {
+		for alloca, phi := range inserted[block] {
+			current[alloca] = phi
+		}
+
+		instructions := make([]ir.Instruction, 0, len(block.Insts))
+
+		for _, instruction := range block.Insts {
+			switch inst := instruction.(type) {
+			case *ir.InstAlloca:
+				if promoted[inst] {
+					continue
+				}
+			case *ir.InstLoad:
+				if alloca, ok := inst.Src.(*ir.InstAlloca); ok && promoted[alloca] {
+					replacements[inst] = current[alloca]
+					continue
+				}
+			case *ir.InstStore:
+				if alloca, ok := inst.Dst.(*ir.InstAlloca); ok && promoted[alloca] {
+					current[alloca] = resolve(inst.Src, replacements)
+					continue
+				}
+			}
+
+			instructions = append(instructions, instruction)
+		}
+
+		block.Insts = instructions
+
+		for _, succ := range graph.succs[block] {
+			for alloca, phi := range inserted[succ] {
+				phi.Incs = append(phi.Incs, ir.NewIncoming(current[alloca], block))
+			}
+		}
+
+		for _, child := range children[block] {
+			values := make(map[*ir.InstAlloca]value.Value, len(current))
+
+			for alloca, v := range current {
+				values[alloca] = v
+			}
+
+			rename(child, values)
+		}
+	}
+
+	// a variable is undefined until stored, which semantic analysis makes sure happens before
+	// it is loaded
+	undefined := make(map[*ir.InstAlloca]value.Value, len(allocas))
+
+	for _, alloca := range allocas {
+		undefined[alloca] = constant.NewUndef(alloca.ElemType)
+	}
+
+	rename(graph.order[0], undefined)
+
+	replaceUses(function, replacements)
+	removeTrivialPhis(function, inserted)
+}
+
+// promotableAllocas returns the allocas of function that are only loaded from and stored to
+func promotableAllocas(function *ir.Func) []*ir.InstAlloca {
+	var allocas []*ir.InstAlloca
+
+	escaping := make(map[value.Value]bool)
+
+	escape := func(instruction interface{}, skip value.Value) {
+		for _, use := range operands(instruction) {
+			if *use != skip {
+				escaping[used(*use)] = true
+			}
+		}
+	}
+
+	for _, block := range function.Blocks {
+		for _, instruction := range block.Insts {
+			switch inst := instruction.(type) {
+			case *ir.InstAlloca:
+				if inst.NElems == nil {
+					allocas = append(allocas, inst)
+				}
+			case *ir.InstLoad:
+				// loading is the one use of its address
+			case *ir.InstStore:
+				escape(inst, inst.Dst)
+			default:
+				escape(inst, nil)
+			}
+		}
+
+		escape(block.Term, nil)
+	}
+
+	promotable := allocas[:0]
+
+	for _, alloca := range allocas {
+		if !escaping[alloca] {
+			promotable = append(promotable, alloca)
+		}
+	}
+
+	return promotable
+}
+
+// insertPhis inserts an empty phi at the start of every block where values stored to a
+// variable on different paths meet: the iterated dominance frontier of the blocks storing it
+func insertPhis(function *ir.Func, allocas []*ir.InstAlloca, frontiers map[*ir.Block][]*ir.Block) map[*ir.Block]map[*ir.InstAlloca]*ir.InstPhi {
+	inserted := make(map[*ir.Block]map[*ir.InstAlloca]*ir.InstPhi)
+
+	for _, alloca := range allocas {
+		var work []*ir.Block
+
+		for _, block := range function.Blocks {
+			for _, instruction := range block.Insts {
+				if store, ok := instruction.(*ir.InstStore); ok && store.Dst == alloca {
+					work = append(work, block)
+					break
+				}
+			}
+		}
+
+		for len(work) > 0 {
+			block := work[len(work)-1]
+			work = work[:len(work)-1]
+
+			for _, frontier := range frontiers[block] {
+				if _, ok := inserted[frontier][alloca]; ok {
+					continue
+				}
+
+				if inserted[frontier] == nil {
+					inserted[frontier] = make(map[*ir.InstAlloca]*ir.InstPhi)
+				}
+
+				phi := &ir.InstPhi{Typ: alloca.ElemType}
+				inserted[frontier][alloca] = phi
+				frontier.Insts = append([]ir.Instruction{phi}, frontier.Insts...)
+
+				work = append(work, frontier)
+			}
+		}
+	}
+
+	return inserted
+}
+
+// removeTrivialPhis replaces the inserted phis merging a single value besides themselves by
+// that value, until none is left
+func removeTrivialPhis(function *ir.Func, inserted map[*ir.Block]map[*ir.InstAlloca]*ir.InstPhi) {
+	candidates := make(map[*ir.InstPhi]bool)
+
+	for _, blockPhis := range inserted {
+		for _, phi := range blockPhis {
+			candidates[phi] = true
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		replacements := make(map[value.Value]value.Value)
+
+		for _, block := range function.Blocks {
+			for _, phi := range phis(block) {
+				if !candidates[phi] {
+					continue
+				}
+
+				if merged, trivial := trivialValue(phi, replacements); trivial {
+					replacements[phi] = merged
+					delete(candidates, phi)
+					removeInstruction(block, phi)
+					changed = true
+				}
+			}
+		}
+
+		replaceUses(function, replacements)
+	}
+}
+
+// trivialValue returns the one value phi merges besides itself, or undef if there is none
+func trivialValue(phi *ir.InstPhi, replacements map[value.Value]value.Value) (value.Value, bool) {
+	var merged value.Value
+
+	for _, incoming := range phi.Incs {
+		x := resolve(incoming.X, replacements)
+
+		if x == phi || x == merged {
+			continue
+		}
+
+		if merged != nil {
+			return nil, false
+		}
+
+		merged = x
+	}
+
+	if merged == nil {
+		merged = constant.NewUndef(phi.Typ)
+	}
+
+	return merged, true
+}
+
+func removeInstruction(block *ir.Block, removed ir.Instruction) {
+	for i, instruction := range block.Insts {
+		if instruction == removed {
+			block.Insts = append(block.Insts[:i], block.Insts[i+1:]...)
+			return
+		}
+	}
+}
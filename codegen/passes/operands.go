@@ -0,0 +1,145 @@
+package passes
+
+import (
+	"reflect"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/value"
+)
+
+var (
+	valueType     = reflect.TypeOf((*value.Value)(nil)).Elem()
+	valuesType    = reflect.TypeOf([]value.Value(nil))
+	incomingsType = reflect.TypeOf([]*ir.Incoming(nil))
+	casesType     = reflect.TypeOf([]*ir.Case(nil))
+)
+
+// operands returns the fields of an instruction or terminator holding the values it uses,
+// including the blocks it branches to, so that uses can be replaced. Every kind of
+// instruction keeps its operands in differently named fields, which llir exposes without a
+// common accessor.
+func operands(instruction interface{}) []*value.Value {
+	var uses []*value.Value
+
+	use := func(field *value.Value) {
+		if *field != nil {
+			uses = append(uses, field)
+		}
+	}
+
+	fields := reflect.ValueOf(instruction).Elem()
+
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+
+		switch field.Type() {
+		case valueType:
+			use(field.Addr().Interface().(*value.Value))
+		case valuesType:
+			for j := 0; j < field.Len(); j++ {
+				use(field.Index(j).Addr().Interface().(*value.Value))
+			}
+		case incomingsType:
+			for _, incoming := range field.Interface().([]*ir.Incoming) {
+				use(&incoming.X)
+				use(&incoming.Pred)
+			}
+		case casesType:
+			for _, c := range field.Interface().([]*ir.Case) {
+				use(&c.X)
+				use(&c.Target)
+			}
+		}
+	}
+
+	return uses
+}
+
+// used returns the value an operand uses: llvm.dbg.declare takes the address of a variable
+// wrapped in metadata
+func used(operand value.Value) value.Value {
+	if wrapped, ok := operand.(*metadata.Value); ok {
+		if wrappedValue, ok := wrapped.Value.(value.Value); ok {
+			return wrappedValue
+		}
+	}
+
+	return operand
+}
+
+// replaceUses replaces every use of a key of replacements in function by its value, following
+// chains of replacements
+func replaceUses(function *ir.Func, replacements map[value.Value]value.Value) {
+	if len(replacements) == 0 {
+		return
+	}
+
+	replace := func(instruction interface{}) {
+		for _, use := range operands(instruction) {
+			*use = resolve(*use, replacements)
+		}
+	}
+
+	for _, block := range function.Blocks {
+		for _, instruction := range block.Insts {
+			replace(instruction)
+		}
+
+		replace(block.Term)
+		resetSuccessors(block.Term)
+	}
+}
+
+func resolve(v value.Value, replacements map[value.Value]value.Value) value.Value {
+	for {
+		replacement, ok := replacements[v]
+
+		if !ok {
+			return v
+		}
+
+		v = replacement
+	}
+}
+
+// resetSuccessors clears the successors a terminator caches, once its targets changed
+func resetSuccessors(terminator ir.Terminator) {
+	field := reflect.ValueOf(terminator).Elem().FieldByName("Successors")
+
+	if field.IsValid() {
+		field.Set(reflect.Zero(field.Type()))
+	}
+}
+
+// phis returns the phi instructions at the start of block
+func phis(block *ir.Block) []*ir.InstPhi {
+	var result []*ir.InstPhi
+
+	for _, instruction := range block.Insts {
+		phi, ok := instruction.(*ir.InstPhi)
+
+		if !ok {
+			break
+		}
+
+		result = append(result, phi)
+	}
+
+	return result
+}
+
+// removeIncomings drops the incoming values of the phis of block coming from pred
+func removeIncomings(block *ir.Block, pred *ir.Block) {
+	for _, phi := range phis(block) {
+		incomings := phi.Incs[:0]
+
+		for _, incoming := range phi.Incs {
+			if incoming.Pred != pred {
+				incomings = append(incomings, incoming)
+			}
+		}
+
+		phi.Incs = incomings
+	}
+}
@@ -0,0 +1,35 @@
+package passes
+
+import (
+	"github.com/llir/llvm/ir"
+)
+
+// Pass rewrites a function defined in a module, keeping what it computes and prints
+type Pass func(function *ir.Func)
+
+// Pipeline returns the passes run at an optimization level: none at 0, the removal of empty,
+// unreachable and trivially branching blocks and of dead instructions from 1, and the promotion
+// of variables to registers first from 2
+func Pipeline(level int) []Pass {
+	switch {
+	case level <= 0:
+		return nil
+	case level == 1:
+		return []Pass{SimplifyBlocks, EliminateDeadCode}
+	default:
+		return []Pass{Mem2Reg, SimplifyBlocks, EliminateDeadCode}
+	}
+}
+
+// Run the passes in order over every function defined in module
+func Run(module *ir.Module, passes []Pass) {
+	for _, function := range module.Funcs {
+		if len(function.Blocks) == 0 {
+			continue
+		}
+
+		for _, pass := range passes {
+			pass(function)
+		}
+	}
+}
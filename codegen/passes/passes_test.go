@@ -0,0 +1,260 @@
+package passes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+// newFunc returns a function taking a bool %c and returning an int
+func newFunc() (*ir.Func, *ir.Param) {
+	module := ir.NewModule()
+	condition := ir.NewParam("c", types.I1)
+
+	return module.NewFunc("f", types.I32, condition), condition
+}
+
+func one() *constant.Int {
+	return constant.NewInt(types.I32, 1)
+}
+
+func two() *constant.Int {
+	return constant.NewInt(types.I32, 2)
+}
+
+func TestMem2RegInsertsPhi(t *testing.T) {
+	function, condition := newFunc()
+
+	entry := function.NewBlock("entry")
+	then := function.NewBlock("then")
+	otherwise := function.NewBlock("otherwise")
+	end := function.NewBlock("end")
+
+	variable := entry.NewAlloca(types.I32)
+	variable.SetName("x")
+	entry.NewStore(one(), variable)
+	entry.NewCondBr(condition, then, otherwise)
+
+	then.NewStore(two(), variable)
+	then.NewBr(end)
+
+	otherwise.NewBr(end)
+
+	end.NewRet(end.NewLoad(types.I32, variable))
+
+	Mem2Reg(function)
+
+	result := function.LLString()
+
+	for _, instruction := range []string{"alloca", "load", "store"} {
+		if strings.Contains(result, instruction) {
+			t.Errorf("Expecting no %v once promoted, got\n%v", instruction, result)
+		}
+	}
+
+	if !strings.Contains(result, "phi i32 [ 1, %otherwise ], [ 2, %then ]") {
+		t.Errorf("Expecting the values stored on both paths to meet in a phi, got\n%v", result)
+	}
+}
+
+func TestMem2RegRemovesTrivialPhis(t *testing.T) {
+	function, condition := newFunc()
+
+	entry := function.NewBlock("entry")
+	loop := function.NewBlock("loop")
+	end := function.NewBlock("end")
+
+	variable := entry.NewAlloca(types.I32)
+	entry.NewStore(one(), variable)
+	entry.NewBr(loop)
+
+	// the loop stores the value it loads, so the variable is always 1
+	loop.NewStore(loop.NewLoad(types.I32, variable), variable)
+	loop.NewCondBr(condition, loop, end)
+
+	end.NewRet(end.NewLoad(types.I32, variable))
+
+	Mem2Reg(function)
+
+	if result := function.LLString(); strings.Contains(result, "phi") || !strings.Contains(result, "ret i32 1") {
+		t.Errorf("Expecting the variable to be replaced by 1, got\n%v", result)
+	}
+}
+
+func TestMem2RegKeepsEscapingVariables(t *testing.T) {
+	function, _ := newFunc()
+
+	entry := function.NewBlock("entry")
+
+	variable := entry.NewAlloca(types.I32)
+	escaped := entry.NewAlloca(types.NewPointer(types.I32))
+	entry.NewStore(variable, escaped)
+	entry.NewRet(one())
+
+	Mem2Reg(function)
+
+	if count := strings.Count(function.LLString(), "alloca"); count != 1 {
+		t.Errorf("Expecting the variable whose address is stored not to be promoted, got %v alloca(s)", count)
+	}
+}
+
+func TestSimplifyBlocksMergesAndSkips(t *testing.T) {
+	function, condition := newFunc()
+
+	entry := function.NewBlock("entry")
+	empty := function.NewBlock("empty")
+	next := function.NewBlock("next")
+	then := function.NewBlock("then")
+	otherwise := function.NewBlock("otherwise")
+	unreachable := function.NewBlock("unreachable")
+
+	entry.NewBr(empty)
+	empty.NewBr(next)
+	next.NewCondBr(constant.True, then, otherwise)
+	then.NewRet(one())
+	otherwise.NewCondBr(condition, then, then)
+	unreachable.NewBr(then)
+
+	SimplifyBlocks(function)
+
+	if len(function.Blocks) != 1 || function.Blocks[0] != entry {
+		t.Errorf("Expecting a single block, got\n%v", function.LLString())
+	}
+
+	if result := function.LLString(); !strings.Contains(result, "ret i32 1") {
+		t.Errorf("Expecting the entry to return 1, got\n%v", result)
+	}
+}
+
+func TestSimplifyBlocksKeepsPhiPredecessors(t *testing.T) {
+	function, condition := newFunc()
+
+	entry := function.NewBlock("entry")
+	empty := function.NewBlock("empty")
+	end := function.NewBlock("end")
+
+	entry.NewCondBr(condition, empty, end)
+	empty.NewBr(end)
+	end.NewRet(end.NewPhi(ir.NewIncoming(one(), entry), ir.NewIncoming(two(), empty)))
+
+	SimplifyBlocks(function)
+
+	// skipping the empty block would leave the phi unable to tell the paths apart
+	if len(function.Blocks) != 3 {
+		t.Errorf("Expecting the empty block to be kept, got\n%v", function.LLString())
+	}
+}
+
+func TestEliminateDeadCode(t *testing.T) {
+	function, _ := newFunc()
+
+	entry := function.NewBlock("entry")
+
+	printf := ir.NewFunc("printf", types.I32, ir.NewParam("", types.I8Ptr))
+	printf.Sig.Variadic = true
+
+	unused := entry.NewAdd(one(), two())
+	entry.NewMul(unused, two())
+	used := entry.NewSub(two(), one())
+	entry.NewCall(printf, constant.NewNull(types.I8Ptr), entry.NewAdd(one(), one()))
+	entry.NewRet(used)
+
+	EliminateDeadCode(function)
+
+	result := function.LLString()
+
+	if strings.Contains(result, "mul") || strings.Contains(result, "add i32 1, 2") {
+		t.Errorf("Expecting unused instructions to be removed, got\n%v", result)
+	}
+
+	if !strings.Contains(result, "sub") || !strings.Contains(result, "call") || !strings.Contains(result, "add i32 1, 1") {
+		t.Errorf("Expecting calls and the instructions used to be kept, got\n%v", result)
+	}
+}
+
+// verifyMessages verifies the module of function
+func verifyMessages(function *ir.Func) []string {
+	var messages []string
+
+	for _, violation := range Verify(function.Parent) {
+		messages = append(messages, violation.Message)
+	}
+
+	return messages
+}
+
+func TestVerifyMissingTerminator(t *testing.T) {
+	function, _ := newFunc()
+
+	entry := function.NewBlock("entry")
+	entry.NewAdd(one(), two())
+
+	if messages := verifyMessages(function); len(messages) != 1 || messages[0] != "block %entry has no terminator" {
+		t.Errorf("Expecting a missing terminator, got %v", messages)
+	}
+}
+
+func TestVerifyTypes(t *testing.T) {
+	function, condition := newFunc()
+
+	entry := function.NewBlock("entry")
+
+	variable := entry.NewAlloca(types.I32)
+	// llir refuses to create such a store
+	entry.Insts = append(entry.Insts, &ir.InstStore{Src: condition, Dst: variable})
+	entry.NewAdd(one(), condition)
+	entry.NewRet(constant.NewInt(types.I8, 0))
+
+	expected := []string{
+		"store takes a pointer to i1, got i32*",
+		"add takes a value of type i32, got i1",
+		"ret takes a value of type i32, got i8",
+	}
+
+	if messages := verifyMessages(function); strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expecting %v, got %v", expected, messages)
+	}
+}
+
+func TestVerifyPhiPredecessors(t *testing.T) {
+	function, condition := newFunc()
+
+	entry := function.NewBlock("entry")
+	then := function.NewBlock("then")
+	end := function.NewBlock("end")
+
+	entry.NewCondBr(condition, then, end)
+	then.NewBr(end)
+	end.NewRet(end.NewPhi(ir.NewIncoming(one(), then), ir.NewIncoming(two(), end)))
+
+	expected := []string{
+		"phi has an incoming value from %end, which does not branch to %end",
+		"phi has no incoming value from predecessor %entry",
+	}
+
+	if messages := verifyMessages(function); strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expecting %v, got %v", expected, messages)
+	}
+}
+
+func TestVerifyDominance(t *testing.T) {
+	function, condition := newFunc()
+
+	entry := function.NewBlock("entry")
+	then := function.NewBlock("then")
+	end := function.NewBlock("end")
+
+	entry.NewCondBr(condition, then, end)
+
+	sum := then.NewAdd(one(), two())
+	then.NewBr(end)
+
+	end.NewRet(sum)
+
+	if messages := verifyMessages(function); len(messages) != 1 || messages[0] != "ret uses the result of add, which is not computed on every path to it" {
+		t.Errorf("Expecting a use not dominated by its definition, got %v", messages)
+	}
+}
@@ -0,0 +1,180 @@
+package passes
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/value"
+)
+
+// SimplifyBlocks removes the blocks of function that are never entered or only branch to
+// another block, turns conditional branches on a constant or to a single block into
+// unconditional ones, and merges a block into its only predecessor when it is the only
+// successor of that predecessor, until none of these applies
+func SimplifyBlocks(function *ir.Func) {
+	for changed := true; changed; {
+		changed = removeUnreachableBlocks(function)
+		changed = foldBranches(function) || changed
+		changed = skipEmptyBlocks(function) || changed
+		changed = mergeBlocks(function) || changed
+	}
+}
+
+// foldBranches replaces conditional branches whose target is known by unconditional ones
+func foldBranches(function *ir.Func) bool {
+	changed := false
+
+	for _, block := range function.Blocks {
+		branch, ok := block.Term.(*ir.TermCondBr)
+
+		if !ok {
+			continue
+		}
+
+		target, other := branch.TargetTrue.(*ir.Block), branch.TargetFalse.(*ir.Block)
+
+		if condition, ok := branch.Cond.(*constant.Int); ok && condition.X.Sign() == 0 {
+			target, other = other, target
+		} else if !ok && target != other {
+			continue
+		}
+
+		if other != target {
+			removeIncomings(other, block)
+		}
+
+		term := ir.NewBr(target)
+		term.Metadata = branch.Metadata
+		block.Term = term
+		changed = true
+	}
+
+	return changed
+}
+
+// skipEmptyBlocks makes the predecessors of a block holding nothing but a branch jump to its
+// target directly, and removes the block. A target with phis has to tell where it is entered
+// from, so it is only skipped to when none of the predecessors already branches to it.
+func skipEmptyBlocks(function *ir.Func) bool {
+	entry := function.Blocks[0]
+
+	for _, block := range function.Blocks {
+		branch, ok := block.Term.(*ir.TermBr)
+
+		if block == entry || len(block.Insts) > 0 || !ok || branch.Target == block {
+			continue
+		}
+
+		target := branch.Target.(*ir.Block)
+		graph := newCFG(function)
+		preds := graph.preds[block]
+
+		if len(phis(target)) > 0 && sharesPred(graph, preds, target) {
+			continue
+		}
+
+		for _, phi := range phis(target) {
+			var incomings []*ir.Incoming
+
+			for _, incoming := range phi.Incs {
+				if incoming.Pred != block {
+					incomings = append(incomings, incoming)
+					continue
+				}
+
+				for _, pred := range preds {
+					incomings = append(incomings, ir.NewIncoming(incoming.X, pred))
+				}
+			}
+
+			phi.Incs = incomings
+		}
+
+		for _, pred := range preds {
+			retarget(pred, block, target)
+		}
+
+		removeBlock(function, block)
+
+		return true
+	}
+
+	return false
+}
+
+func sharesPred(graph *cfg, preds []*ir.Block, block *ir.Block) bool {
+	for _, pred := range preds {
+		if contains(graph.preds[block], pred) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mergeBlocks appends a block to its only predecessor, which only branches to it
+func mergeBlocks(function *ir.Func) bool {
+	entry := function.Blocks[0]
+	graph := newCFG(function)
+
+	for _, block := range function.Blocks {
+		preds := graph.preds[block]
+
+		if block == entry || len(preds) != 1 || preds[0] == block {
+			continue
+		}
+
+		pred := preds[0]
+
+		if _, ok := pred.Term.(*ir.TermBr); !ok {
+			continue
+		}
+
+		// the phis of the block have a single incoming value, from pred
+		replacements := make(map[value.Value]value.Value)
+		blockPhis := phis(block)
+
+		for _, phi := range blockPhis {
+			replacements[phi] = phi.Incs[0].X
+		}
+
+		pred.Insts = append(pred.Insts, block.Insts[len(blockPhis):]...)
+		pred.Term = block.Term
+
+		for _, succ := range graph.succs[block] {
+			for _, phi := range phis(succ) {
+				for _, incoming := range phi.Incs {
+					if incoming.Pred == block {
+						incoming.Pred = pred
+					}
+				}
+			}
+		}
+
+		removeBlock(function, block)
+		replaceUses(function, replacements)
+
+		return true
+	}
+
+	return false
+}
+
+// retarget makes the terminator of block branch to target instead of previous
+func retarget(block *ir.Block, previous *ir.Block, target *ir.Block) {
+	for _, use := range operands(block.Term) {
+		if *use == previous {
+			*use = target
+		}
+	}
+
+	resetSuccessors(block.Term)
+}
+
+func removeBlock(function *ir.Func, removed *ir.Block) {
+	for i, block := range function.Blocks {
+		if block == removed {
+			function.Blocks = append(function.Blocks[:i], function.Blocks[i+1:]...)
+			return
+		}
+	}
+}
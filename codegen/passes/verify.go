@@ -0,0 +1,328 @@
+package passes
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// Violation of the well-formedness of a module, found at an instruction, a terminator, or a
+// block without terminator
+type Violation struct {
+	At      interface{}
+	Message string
+}
+
+// Verify checks the functions defined in module the way llvm does when reading it: every
+// block ends with a terminator, operands have the types their instruction expects, phis come
+// first and have one incoming value per predecessor, and every value used is defined in a
+// block that dominates the use
+func Verify(module *ir.Module) []*Violation {
+	var violations []*Violation
+
+	for _, function := range module.Funcs {
+		if len(function.Blocks) > 0 {
+			violations = append(violations, verifyFunc(function)...)
+		}
+	}
+
+	return violations
+}
+
+type verifier struct {
+	function   *ir.Func
+	violations []*Violation
+	blocks     map[value.Value]*ir.Block // block defining each instruction
+	positions  map[value.Value]int       // position of each instruction in its block
+	preds      map[*ir.Block][]*ir.Block // including unreachable predecessors
+	graph      *cfg
+	idom       map[*ir.Block]*ir.Block
+}
+
+func verifyFunc(function *ir.Func) []*Violation {
+	v := &verifier{
+		function:  function,
+		blocks:    make(map[value.Value]*ir.Block),
+		positions: make(map[value.Value]int),
+		preds:     make(map[*ir.Block][]*ir.Block),
+	}
+
+	for _, block := range function.Blocks {
+		for i, instruction := range block.Insts {
+			if defined, ok := instruction.(value.Value); ok {
+				v.blocks[defined] = block
+				v.positions[defined] = i
+			}
+		}
+
+		if block.Term == nil {
+			v.report(lastOf(block), "block %v has no terminator", block.Ident())
+		}
+	}
+
+	// the rest needs every block to be terminated
+	if len(v.violations) > 0 {
+		return v.violations
+	}
+
+	for _, block := range function.Blocks {
+		for _, succ := range block.Term.Succs() {
+			if !contains(v.preds[succ], block) {
+				v.preds[succ] = append(v.preds[succ], block)
+			}
+		}
+	}
+
+	v.graph = newCFG(function)
+	v.idom = v.graph.dominators()
+
+	for _, block := range function.Blocks {
+		v.verifyPhis(block)
+
+		for i, instruction := range block.Insts {
+			v.verifyTypes(instruction)
+			v.verifyUses(instruction, block, i)
+		}
+
+		v.verifyTypes(block.Term)
+		v.verifyUses(block.Term, block, len(block.Insts))
+	}
+
+	return v.violations
+}
+
+func (v *verifier) report(at interface{}, format string, args ...interface{}) {
+	v.violations = append(v.violations, &Violation{At: at, Message: fmt.Sprintf(format, args...)})
+}
+
+// lastOf returns the last instruction of block, or block itself if it has none
+func lastOf(block *ir.Block) interface{} {
+	if len(block.Insts) == 0 {
+		return block
+	}
+
+	return block.Insts[len(block.Insts)-1]
+}
+
+// kind names the instruction or terminator as llvm does, e.g. store or br
+func kind(instruction interface{}) string {
+	name := reflect.TypeOf(instruction).Elem().Name()
+
+	for _, prefix := range []string{"Inst", "Term"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+
+	return strings.ToLower(name)
+}
+
+func (v *verifier) verifyPhis(block *ir.Block) {
+	leading := true
+
+	for _, instruction := range block.Insts {
+		phi, ok := instruction.(*ir.InstPhi)
+
+		if !ok {
+			leading = false
+			continue
+		}
+
+		if !leading {
+			v.report(phi, "phi follows other instructions of block %v", block.Ident())
+		}
+
+		incoming := make(map[value.Value]value.Value)
+
+		for _, inc := range phi.Incs {
+			pred, ok := inc.Pred.(*ir.Block)
+
+			if !ok || !contains(v.preds[block], pred) {
+				v.report(phi, "phi has an incoming value from %v, which does not branch to %v", inc.Pred.Ident(), block.Ident())
+				continue
+			}
+
+			if x, ok := incoming[pred]; ok && x != inc.X {
+				v.report(phi, "phi has different incoming values from %v", pred.Ident())
+			}
+
+			incoming[pred] = inc.X
+		}
+
+		for _, pred := range v.preds[block] {
+			if _, ok := incoming[pred]; !ok {
+				v.report(phi, "phi has no incoming value from predecessor %v", pred.Ident())
+			}
+		}
+	}
+}
+
+// verifyTypes checks the operands of instructions codegen emits
+func (v *verifier) verifyTypes(instruction interface{}) {
+	switch inst := instruction.(type) {
+	case *ir.InstLoad:
+		if v.present(inst, "source", inst.Src) {
+			v.expectPointerTo(inst, inst.Src, inst.ElemType)
+		}
+	case *ir.InstStore:
+		if v.present(inst, "value", inst.Src) && v.present(inst, "destination", inst.Dst) {
+			v.expectPointerTo(inst, inst.Dst, inst.Src.Type())
+		}
+	case *ir.InstPhi:
+		for _, inc := range inst.Incs {
+			if v.present(inst, "incoming value", inc.X) {
+				v.expectType(inst, inc.X, inst.Typ)
+			}
+		}
+	case *ir.InstCall:
+		v.verifyCall(inst)
+	case *ir.TermCondBr:
+		if v.present(inst, "condition", inst.Cond) {
+			v.expectType(inst, inst.Cond, types.I1)
+		}
+	case *ir.TermRet:
+		if inst.X == nil {
+			v.expectType(inst, nil, v.function.Sig.RetType)
+		} else {
+			v.expectType(inst, inst.X, v.function.Sig.RetType)
+		}
+	default:
+		// binary, bitwise and comparison instructions take two operands of the same type
+		fields := reflect.ValueOf(instruction).Elem()
+		x, y := fields.FieldByName("X"), fields.FieldByName("Y")
+
+		if x.IsValid() && y.IsValid() && x.Type() == valueType && y.Type() == valueType {
+			lhs, _ := x.Interface().(value.Value)
+			rhs, _ := y.Interface().(value.Value)
+
+			if v.present(instruction, "left operand", lhs) && v.present(instruction, "right operand", rhs) {
+				v.expectType(instruction, rhs, lhs.Type())
+			}
+		}
+	}
+}
+
+func (v *verifier) verifyCall(call *ir.InstCall) {
+	if !v.present(call, "callee", call.Callee) {
+		return
+	}
+
+	var signature *types.FuncType
+
+	if pointer, ok := call.Callee.Type().(*types.PointerType); ok {
+		signature, _ = pointer.ElemType.(*types.FuncType)
+	}
+
+	if signature == nil {
+		v.report(call, "call of %v, which is not a function", call.Callee.Ident())
+		return
+	}
+
+	if len(call.Args) < len(signature.Params) || (len(call.Args) > len(signature.Params) && !signature.Variadic) {
+		v.report(call, "call of %v with %v argument(s), expecting %v", call.Callee.Ident(), len(call.Args), len(signature.Params))
+		return
+	}
+
+	for i, param := range signature.Params {
+		if v.present(call, "argument", call.Args[i]) {
+			v.expectType(call, call.Args[i], param)
+		}
+	}
+}
+
+// present reports an operand that is missing
+func (v *verifier) present(instruction interface{}, operand string, x value.Value) bool {
+	if x == nil || (reflect.ValueOf(x).Kind() == reflect.Ptr && reflect.ValueOf(x).IsNil()) {
+		v.report(instruction, "%v has no %v", kind(instruction), operand)
+		return false
+	}
+
+	return true
+}
+
+func (v *verifier) expectType(instruction interface{}, x value.Value, expected types.Type) {
+	actual := types.Type(types.Void)
+
+	if x != nil {
+		actual = x.Type()
+	}
+
+	if !types.Equal(actual, expected) {
+		v.report(instruction, "%v takes a value of type %v, got %v", kind(instruction), expected, actual)
+	}
+}
+
+func (v *verifier) expectPointerTo(instruction interface{}, pointer value.Value, elem types.Type) {
+	if pointerType, ok := pointer.Type().(*types.PointerType); !ok || !types.Equal(pointerType.ElemType, elem) {
+		v.report(instruction, "%v takes a pointer to %v, got %v", kind(instruction), elem, pointer.Type())
+	}
+}
+
+// verifyUses checks that the instructions used by instruction, at position in block, are
+// defined before it on every path. An incoming value of a phi is used at the end of its
+// predecessor. Uses in blocks never entered are not checked.
+func (v *verifier) verifyUses(instruction interface{}, block *ir.Block, position int) {
+	if !v.graph.reachable(block) {
+		return
+	}
+
+	if phi, ok := instruction.(*ir.InstPhi); ok {
+		for _, inc := range phi.Incs {
+			if pred, ok := inc.Pred.(*ir.Block); ok && inc.X != nil {
+				v.verifyUse(phi, inc.X, pred, len(pred.Insts))
+			}
+		}
+
+		return
+	}
+
+	for _, use := range operands(instruction) {
+		v.verifyUse(instruction, used(*use), block, position)
+	}
+}
+
+func (v *verifier) verifyUse(instruction interface{}, x value.Value, block *ir.Block, position int) {
+	if _, ok := x.(ir.Instruction); !ok {
+		return
+	}
+
+	defBlock, ok := v.blocks[x]
+
+	if !ok {
+		v.report(instruction, "%v uses the result of %v, which is not in function %v", kind(instruction), kind(x), v.function.Ident())
+		return
+	}
+
+	if defBlock == block {
+		if v.positions[x] >= position {
+			v.report(instruction, "%v uses the result of %v before it is computed", kind(instruction), kind(x))
+		}
+
+		return
+	}
+
+	if !v.dominates(defBlock, block) {
+		v.report(instruction, "%v uses the result of %v, which is not computed on every path to it", kind(instruction), kind(x))
+	}
+}
+
+// dominates tells whether every path from the entry to block goes through dominator
+func (v *verifier) dominates(dominator *ir.Block, block *ir.Block) bool {
+	if !v.graph.reachable(dominator) {
+		return false
+	}
+
+	for block != dominator {
+		idom, ok := v.idom[block]
+
+		if !ok {
+			return false
+		}
+
+		block = idom
+	}
+
+	return true
+}
@@ -0,0 +1,124 @@
+package codegen
+
+import (
+	"math"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/typing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// PanicExitCode is the status a program exits with when a runtime check fails, distinct from
+// the statuses of the command line
+const PanicExitCode = 3
+
+const stderrFileDescriptor = 2
+
+// panicFuncName names the function failing a runtime check, which programs cannot declare
+const panicFuncName = "__expressive_panic"
+
+// RuntimeChecks guards the int operations of a program: an overflow, or a division by zero,
+// calls __expressive_panic, which prints a runtime error located at the source of the
+// operation to stderr and exits with PanicExitCode
+type RuntimeChecks struct {
+	visitor    *CodegenVisitor
+	panic      *ir.Func // __expressive_panic, defined on first use
+	intrinsics map[string]*ir.Func
+}
+
+// NewRuntimeChecks of the program visitor generates
+func NewRuntimeChecks(visitor *CodegenVisitor) *RuntimeChecks {
+	return &RuntimeChecks{visitor: visitor, intrinsics: make(map[string]*ir.Func)}
+}
+
+// panicFunc defines __expressive_panic(message), which flushes what was printed, prints
+// message to stderr and exits
+func (checks *RuntimeChecks) panicFunc() *ir.Func {
+	if checks.panic != nil {
+		return checks.panic
+	}
+
+	dprintf := checks.visitor.declare(libcFunction("dprintf"))
+	exit := checks.visitor.declare(libcFunction("exit"))
+
+	message := ir.NewParam("message", types.I8Ptr)
+
+	checks.panic = ir.NewFunc(panicFuncName, types.Void, message)
+	checks.panic.FuncAttrs = append(checks.panic.FuncAttrs, enum.FuncAttrCold, enum.FuncAttrNoInline, enum.FuncAttrNoReturn, enum.FuncAttrNoUnwind)
+
+	entry := checks.panic.NewBlock("")
+	entry.NewCall(checks.visitor.flush, constant.NewNull(types.I8Ptr))
+	entry.NewCall(dprintf, constant.NewInt(types.I32, stderrFileDescriptor), checks.visitor.newStringPointer(entry, "%s\x00"), message)
+	entry.NewCall(exit, constant.NewInt(types.I32, PanicExitCode))
+	entry.NewUnreachable()
+
+	checks.visitor.declare(checks.panic)
+
+	return checks.panic
+}
+
+// intrinsic declares llvm.<operation>.with.overflow.i32, returning the result of operation and
+// whether it overflowed
+func (checks *RuntimeChecks) intrinsic(operation string) *ir.Func {
+	if intrinsic, ok := checks.intrinsics[operation]; ok {
+		return intrinsic
+	}
+
+	intType := typing.INT.IrType().(*types.IntType)
+	resultType := types.NewStruct(intType, types.I1)
+
+	intrinsic := ir.NewFunc("llvm."+operation+".with.overflow.i32", resultType, ir.NewParam("", intType), ir.NewParam("", intType))
+	intrinsic.FuncAttrs = append(intrinsic.FuncAttrs, enum.FuncAttrNoUnwind, enum.FuncAttrReadNone)
+
+	checks.intrinsics[operation] = intrinsic
+	checks.visitor.declare(intrinsic)
+
+	return intrinsic
+}
+
+// overflowing computes x operation y, one of sadd, ssub or smul, failing on overflow
+func (checks *RuntimeChecks) overflowing(fragment *BlocksFragment, operation string, x value.Value, y value.Value, node ast.Node) value.Value {
+	result := fragment.CurrentBlock.NewCall(checks.intrinsic(operation), x, y)
+	overflow := fragment.CurrentBlock.NewExtractValue(result, 1)
+
+	checks.guard(fragment, overflow, node, "integer overflow")
+
+	return fragment.CurrentBlock.NewExtractValue(result, 0)
+}
+
+// divisor fails when x cannot be divided by y: y is zero, or the quotient of the smallest int
+// by -1 overflows
+func (checks *RuntimeChecks) divisor(fragment *BlocksFragment, x value.Value, y value.Value, node ast.Node) {
+	intType := typing.INT.IrType().(*types.IntType)
+
+	zero := fragment.CurrentBlock.NewICmp(enum.IPredEQ, y, constant.NewInt(intType, 0))
+
+	checks.guard(fragment, zero, node, "integer division by zero")
+
+	smallest := fragment.CurrentBlock.NewICmp(enum.IPredEQ, x, constant.NewInt(intType, math.MinInt32))
+	minusOne := fragment.CurrentBlock.NewICmp(enum.IPredEQ, y, constant.NewInt(intType, -1))
+
+	checks.guard(fragment, fragment.CurrentBlock.NewAnd(smallest, minusOne), node, "integer overflow")
+}
+
+// guard branches to a call of __expressive_panic when failed is true, and goes on generating
+// the fragment in a new block otherwise
+func (checks *RuntimeChecks) guard(fragment *BlocksFragment, failed value.Value, node ast.Node, message string) {
+	labeller := checks.visitor.labeller
+
+	fail := ir.NewBlock(labeller.NewSet("check", "fail"))
+	pass := ir.NewBlock(labeller.Label("check", "pass"))
+
+	fragment.CurrentBlock.NewCondBr(failed, fail, pass)
+
+	fragment.AddBlock(fail)
+	fail.NewCall(checks.panicFunc(), checks.visitor.newStringPointer(fail, node.GetLocation()+": runtime error: "+message+"\n\x00"))
+	fail.NewUnreachable()
+
+	fragment.AddBlock(pass)
+}
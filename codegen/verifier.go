@@ -0,0 +1,119 @@
+package codegen
+
+import (
+	"fmt"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/codegen/passes"
+	"github.com/carlcui/expressive/logger"
+
+	"github.com/llir/llvm/ir"
+)
+
+// Verifier checks the generated module before it is printed, so that a bug of codegen or of a
+// pass is reported as an internal compiler error at the source node it comes from, instead of
+// as an output file llvm rejects
+type Verifier struct {
+	origins     map[interface{}]ast.Node // innermost node generating each instruction and terminator
+	terminators map[*ir.Block]terminated
+	program     ast.Node // where violations of instructions without origin are reported
+}
+
+// terminated records a block when its terminator is first seen
+type terminated struct {
+	terminator   ir.Terminator
+	instructions int
+}
+
+// NewVerifier of the module generated for program
+func NewVerifier(program ast.Node) *Verifier {
+	return &Verifier{
+		program:     program,
+		origins:     make(map[interface{}]ast.Node),
+		terminators: make(map[*ir.Block]terminated),
+	}
+}
+
+// track records the node generating the instructions of fragment that have no origin yet.
+// Fragments are tracked when removed, so the instructions of children keep their own nodes,
+// and a block terminated by a node is recorded before the parent node appends to it.
+func (verifier *Verifier) track(fragment Fragment, node ast.Node) {
+	var blocks []*ir.Block
+
+	switch f := fragment.(type) {
+	case *BlocksFragment:
+		blocks = f.Blocks
+	case *FunctionsFragment:
+		for _, function := range f.Functions {
+			blocks = append(blocks, function.Blocks...)
+		}
+	}
+
+	for _, block := range blocks {
+		for _, instruction := range block.Insts {
+			verifier.originate(instruction, node)
+		}
+
+		if block.Term == nil {
+			continue
+		}
+
+		verifier.originate(block.Term, node)
+
+		if _, ok := verifier.terminators[block]; !ok {
+			verifier.terminators[block] = terminated{block.Term, len(block.Insts)}
+		}
+	}
+}
+
+func (verifier *Verifier) originate(instruction interface{}, node ast.Node) {
+	if _, ok := verifier.origins[instruction]; !ok {
+		verifier.origins[instruction] = node
+	}
+}
+
+// Verify module as generated, logging violations as internal compiler errors. It returns false
+// if there were any.
+func (verifier *Verifier) Verify(module *ir.Module, logger logger.Logger) bool {
+	valid := true
+
+	for _, function := range module.Funcs {
+		for _, block := range function.Blocks {
+			record, ok := verifier.terminators[block]
+
+			switch {
+			case !ok:
+			case block.Term != record.terminator:
+				verifier.log(logger, block.Term, fmt.Sprintf("block %v is terminated twice", block.Ident()))
+				valid = false
+			case len(block.Insts) > record.instructions:
+				verifier.log(logger, block.Insts[record.instructions], fmt.Sprintf("instruction follows the terminator of block %v", block.Ident()))
+				valid = false
+			}
+		}
+	}
+
+	return verifier.VerifyPasses(module, logger) && valid
+}
+
+// VerifyPasses verifies module once passes rewrote it, which moves instructions between blocks
+// and replaces terminators
+func (verifier *Verifier) VerifyPasses(module *ir.Module, logger logger.Logger) bool {
+	violations := passes.Verify(module)
+
+	for _, violation := range violations {
+		verifier.log(logger, violation.At, violation.Message)
+	}
+
+	return len(violations) == 0
+}
+
+func (verifier *Verifier) log(logger logger.Logger, at interface{}, message string) {
+	node, ok := verifier.origins[at]
+
+	if !ok {
+		node = verifier.program
+	}
+
+	logger.Log(node.GetLocation(), "internal compiler error: "+message)
+}
@@ -0,0 +1,628 @@
+package wat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/signature"
+	"github.com/carlcui/expressive/symbolTable"
+	"github.com/carlcui/expressive/typing"
+)
+
+const indentation = "  "
+
+// argSize is the size of a print argument in the argument area: ints, bools and string
+// addresses are stored as i32, floats as f64
+const argSize = 8
+
+const pageSize = 1 << 16
+
+// Generator translates an analysed ast into a WebAssembly text module
+type Generator struct {
+	body      strings.Builder // instructions of main
+	indent    int
+	locals    []string // declarations of the locals of main
+	names     map[*symbolTable.Binding]string
+	usedNames map[string]bool
+	labels    int      // number of labelled statements generated, numbering their labels
+	breaks    []string // per enclosing breakable statement, the label a break branches to
+	data      []byte   // static strings, each null terminated
+	strings   map[string]int
+	maxArgs   int // arguments of the print with the most arguments
+	helpers   [HELPER_COUNT]bool
+	importPow bool
+
+	unsupported ast.Node // the first call of a host function or extern declaration, nil if there is none
+}
+
+func (generator *Generator) Init() {
+	generator.names = make(map[*symbolTable.Binding]string)
+	generator.usedNames = make(map[string]bool)
+	generator.strings = make(map[string]int)
+}
+
+func valueType(t typing.Typing) string {
+	switch t {
+	case typing.FLOAT:
+		return "f64"
+	case typing.INT, typing.BYTE, typing.BOOL, typing.CHAR, typing.STRING:
+		return "i32"
+	default:
+		panic(fmt.Sprintf("no wasm type for %v", t))
+	}
+}
+
+// intInstructions and floatInstructions implement binary operators on ints and floats. Bools
+// only compare for equality, as ints.
+var intInstructions = map[signature.Operator]string{
+	signature.ADD:               "i32.add",
+	signature.SUBTRACT:          "i32.sub",
+	signature.MULTIPLY:          "i32.mul",
+	signature.DIVIDE:            "i32.div_s",
+	signature.MODULO:            "i32.rem_s",
+	signature.GREATER:           "i32.gt_s",
+	signature.GREATER_OR_EQUAL:  "i32.ge_s",
+	signature.LESS:              "i32.lt_s",
+	signature.LESS_OR_EQUAL:     "i32.le_s",
+	signature.SHALLOW_EQUAL:     "i32.eq",
+	signature.DEEP_EQUAL:        "i32.eq",
+	signature.SHALLOW_NOT_EQUAL: "i32.ne",
+	signature.DEEP_NOT_EQUAL:    "i32.ne",
+}
+
+var floatInstructions = map[signature.Operator]string{
+	signature.ADD:               "f64.add",
+	signature.SUBTRACT:          "f64.sub",
+	signature.MULTIPLY:          "f64.mul",
+	signature.DIVIDE:            "f64.div",
+	signature.GREATER:           "f64.gt",
+	signature.GREATER_OR_EQUAL:  "f64.ge",
+	signature.LESS:              "f64.lt",
+	signature.LESS_OR_EQUAL:     "f64.le",
+	signature.SHALLOW_EQUAL:     "f64.eq",
+	signature.DEEP_EQUAL:        "f64.eq",
+	signature.SHALLOW_NOT_EQUAL: "f64.ne",
+	signature.DEEP_NOT_EQUAL:    "f64.ne",
+}
+
+// String returns the module. Memory holds the static strings from address 0, then the print
+// argument area, then the heap.
+func (generator *Generator) String() string {
+	var module strings.Builder
+
+	argArea := align(len(generator.data))
+	heap := argArea + generator.maxArgs*argSize
+	pages := (heap + pageSize - 1) / pageSize
+
+	if pages == 0 {
+		pages = 1
+	}
+
+	module.WriteString("(module\n")
+	module.WriteString("  (import \"env\" \"print\" (func $print (param i32 i32)))\n")
+
+	if generator.importPow {
+		module.WriteString("  (import \"env\" \"pow\" (func $pow (param f64 f64) (result f64)))\n")
+	}
+
+	fmt.Fprintf(&module, "  (memory (export \"memory\") %d)\n", pages)
+
+	if generator.maxArgs > 0 {
+		fmt.Fprintf(&module, "  (global $args i32 (i32.const %d))\n", argArea)
+	}
+
+	if generator.helpers[alloc] {
+		fmt.Fprintf(&module, "  (global $heap (mut i32) (i32.const %d))\n", heap)
+	}
+
+	for offset := 0; offset < len(generator.data); {
+		end := offset
+
+		for generator.data[end] != 0 {
+			end++
+		}
+
+		fmt.Fprintf(&module, "  (data (i32.const %d) %v)\n", offset, dataString(generator.data[offset:end+1]))
+
+		offset = end + 1
+	}
+
+	for h, used := range generator.helpers {
+		if used {
+			module.WriteString(helperDefinitions[h])
+		}
+	}
+
+	module.WriteString("  (func $main (export \"main\")\n")
+
+	for _, local := range generator.locals {
+		fmt.Fprintf(&module, "    %v\n", local)
+	}
+
+	module.WriteString(generator.body.String())
+	module.WriteString("  )\n")
+	module.WriteString(")\n")
+
+	return module.String()
+}
+
+func (generator *Generator) generateStmts(stmts []ast.Node) {
+	for _, stmt := range stmts {
+		generator.generateStmt(stmt)
+	}
+}
+
+func (generator *Generator) generateStmt(node ast.Node) {
+	switch stmt := node.(type) {
+	case nil:
+	case *ast.ProgramNode:
+		generator.indent = 2
+		generator.generateStmts(stmt.Chilren)
+	case *ast.BlockNode:
+		generator.generateStmts(stmt.Stmts)
+	case *ast.VariableDeclarationNode:
+		identifier := stmt.Identifier.(*ast.IdentifierNode)
+
+		if stmt.Expr == nil {
+			// Semantic analysis makes sure the variable is assigned before being read. The
+			// default value resets variables declared in loops anyway.
+			generator.defaultValue(identifier)
+		} else {
+			generator.generateExpr(stmt.Expr)
+		}
+
+		generator.line("local.set " + generator.declareLocal(identifier))
+	case *ast.AssignmentNode:
+		identifier := stmt.LHS.(*ast.IdentifierNode)
+		name := generator.name(identifier)
+
+		if stmt.Operator == signature.VOID_OPERATOR {
+			generator.generateExpr(stmt.RHS)
+		} else {
+			generator.line("local.get " + name)
+			generator.generateExpr(stmt.RHS)
+			generator.generateOperator(stmt.Operator, identifier.GetTyping())
+		}
+
+		generator.line("local.set " + name)
+	case *ast.IncDecNode:
+		name := generator.name(stmt.LHS.(*ast.IdentifierNode))
+
+		generator.line("local.get " + name)
+		generator.line("i32.const 1")
+
+		if stmt.IsIncrement {
+			generator.line("i32.add")
+		} else {
+			generator.line("i32.sub")
+		}
+
+		generator.line("local.set " + name)
+	case *ast.PrintNode:
+		generator.generatePrint(stmt)
+	case *ast.IfStmtNode:
+		generator.generateIfStmt(stmt, 0)
+	case *ast.WhileStmtNode:
+		prefix := generator.newLabelPrefix("while")
+
+		generator.breakable(prefix, func() {
+			generator.generateExpr(stmt.ConditionExpr)
+			generator.line("i32.eqz")
+			generator.line("br_if " + prefix + "end")
+			generator.generateStmt(stmt.Block)
+		})
+	case *ast.ForStmtNode:
+		prefix := generator.newLabelPrefix("for")
+
+		generator.generateStmt(stmt.InitializationStmt)
+		generator.breakable(prefix, func() {
+			if stmt.ConditionExpr != nil {
+				generator.generateExpr(stmt.ConditionExpr)
+				generator.line("i32.eqz")
+				generator.line("br_if " + prefix + "end")
+			}
+
+			generator.generateStmt(stmt.Block)
+			generator.generateStmt(stmt.IterationStmt)
+		})
+	case *ast.SwitchStmtNode:
+		generator.generateSwitchStmt(stmt)
+	case *ast.BreakNode:
+		generator.line("br " + generator.breaks[len(generator.breaks)-1])
+	case *ast.CallNode:
+		generator.unsupportedFunction(stmt)
+	case *ast.ExternNode:
+		generator.unsupportedFunction(stmt)
+	default:
+		panic(fmt.Sprintf("%v: unexpected statement %T", node.GetLocation(), node))
+	}
+}
+
+// generatePrint stores the arguments in the argument area, then calls the host with the
+// addresses of the format and of the arguments
+func (generator *Generator) generatePrint(node *ast.PrintNode) {
+	for i, arg := range node.Args {
+		generator.line("global.get $args")
+		generator.generateExpr(arg)
+		generator.line(fmt.Sprintf("%v.store offset=%d", valueType(arg.GetTyping()), i*argSize))
+	}
+
+	if len(node.Args) > generator.maxArgs {
+		generator.maxArgs = len(node.Args)
+	}
+
+	generator.generateExpr(node.StringExpr)
+
+	if len(node.Args) > 0 {
+		generator.line("global.get $args")
+	} else {
+		generator.line("i32.const 0")
+	}
+
+	generator.line("call $print")
+}
+
+// generateIfStmt generates the conditions from the i-th on, nesting each else if in the else
+// of the previous condition
+func (generator *Generator) generateIfStmt(node *ast.IfStmtNode, i int) {
+	generator.generateExpr(node.ConditionExprs[i])
+	generator.line("if")
+	generator.indent++
+	generator.generateStmt(node.ConditionBlocks[i])
+	generator.indent--
+
+	if i+1 < len(node.ConditionExprs) {
+		generator.line("else")
+		generator.indent++
+		generator.generateIfStmt(node, i+1)
+		generator.indent--
+	} else if node.ElseBlock != nil {
+		generator.line("else")
+		generator.indent++
+		generator.generateStmt(node.ElseBlock)
+		generator.indent--
+	}
+
+	generator.line("end")
+}
+
+// breakable generates a loop, whose breaks branch to the end of an enclosing block
+func (generator *Generator) breakable(prefix string, generateBody func()) {
+	generator.line("block " + prefix + "end")
+	generator.indent++
+	generator.line("loop " + prefix + "start")
+	generator.indent++
+
+	generator.breaks = append(generator.breaks, prefix+"end")
+	generateBody()
+	generator.breaks = generator.breaks[:len(generator.breaks)-1]
+
+	generator.line("br " + prefix + "start")
+	generator.indent--
+	generator.line("end")
+	generator.indent--
+	generator.line("end")
+}
+
+// generateSwitchStmt nests one block per case, the innermost one holding the comparisons.
+// Branching out of the block of a case lands on the code of that case, which is followed by
+// the code of the next cases, so a case without break falls through.
+//
+//	block $switch.0.end
+//	  block $switch.0.default
+//	    block $switch.0.case.0
+//	      comparisons, br_if $switch.0.case.0, br $switch.0.default
+//	    end
+//	    case 0
+//	  end
+//	  default
+//	end
+func (generator *Generator) generateSwitchStmt(node *ast.SwitchStmtNode) {
+	prefix := generator.newLabelPrefix("switch")
+	cases := len(node.CaseExprs)
+
+	test := prefix + "test"
+	testTyping := node.TestExpr.GetTyping()
+	generator.locals = append(generator.locals, fmt.Sprintf("(local %v %v)", test, valueType(testTyping)))
+
+	caseLabels := make([]string, cases)
+
+	for i := range caseLabels {
+		caseLabels[i] = fmt.Sprintf("%vcase.%d", prefix, i)
+	}
+
+	generator.line("block " + prefix + "end")
+	generator.indent++
+	generator.line("block " + prefix + "default")
+	generator.indent++
+
+	for i := cases - 1; i >= 0; i-- {
+		generator.line("block " + caseLabels[i])
+		generator.indent++
+	}
+
+	generator.generateExpr(node.TestExpr)
+	generator.line("local.set " + test)
+
+	for i, caseExpr := range node.CaseExprs {
+		generator.line("local.get " + test)
+		generator.generateExpr(caseExpr)
+		generator.generateOperator(signature.SHALLOW_EQUAL, testTyping)
+		generator.line("br_if " + caseLabels[i])
+	}
+
+	generator.line("br " + prefix + "default")
+
+	generator.breaks = append(generator.breaks, prefix+"end")
+
+	for _, caseBlock := range node.CaseBlocks {
+		generator.indent--
+		generator.line("end")
+		generator.generateStmt(caseBlock)
+	}
+
+	generator.indent--
+	generator.line("end")
+	generator.generateStmt(node.DefaultBlock)
+
+	generator.breaks = generator.breaks[:len(generator.breaks)-1]
+
+	generator.indent--
+	generator.line("end")
+}
+
+// newLabelPrefix numbers the labels of a statement. Labels and generated locals contain dots,
+// which expressive identifiers cannot, so they never clash with variables.
+func (generator *Generator) newLabelPrefix(statement string) string {
+	prefix := fmt.Sprintf("$%v.%d.", statement, generator.labels)
+	generator.labels++
+
+	return prefix
+}
+
+// declareLocal adds a local for a variable, named after it. Every variable gets its own local,
+// shadowing variables too.
+func (generator *Generator) declareLocal(identifier *ast.IdentifierNode) string {
+	base := "$" + identifier.Tok.Raw
+	name := base
+
+	for i := 2; generator.usedNames[name]; i++ {
+		name = fmt.Sprintf("%v_%d", base, i)
+	}
+
+	generator.usedNames[name] = true
+	generator.names[identifier.GetBinding()] = name
+	generator.locals = append(generator.locals, fmt.Sprintf("(local %v %v)", name, valueType(identifier.GetTyping())))
+
+	return name
+}
+
+func (generator *Generator) name(identifier *ast.IdentifierNode) string {
+	name, ok := generator.names[identifier.GetBinding()]
+
+	if !ok {
+		panic(fmt.Sprintf("%v: variable %v is not declared", identifier.GetLocation(), identifier.Tok.Raw))
+	}
+
+	return name
+}
+
+func (generator *Generator) defaultValue(identifier *ast.IdentifierNode) {
+	switch identifier.GetTyping() {
+	case typing.INT, typing.BOOL:
+		generator.line("i32.const 0")
+	case typing.FLOAT:
+		generator.line("f64.const 0")
+	case typing.CHAR, typing.STRING:
+		generator.line(fmt.Sprintf("i32.const %d", generator.stringAddress("")))
+	default:
+		panic(fmt.Sprintf("%v: no default value for type %v", identifier.GetLocation(), identifier.GetTyping()))
+	}
+}
+
+// generateExpr generates instructions leaving the value of node on the stack
+func (generator *Generator) generateExpr(node ast.Node) {
+	switch expr := node.(type) {
+	case *ast.IntegerNode:
+		generator.line(fmt.Sprintf("i32.const %d", int32(expr.Val)))
+	case *ast.FloatNode:
+		generator.line("f64.const " + strconv.FormatFloat(float64(expr.Val), 'g', -1, 64))
+	case *ast.BooleanNode:
+		if expr.Val {
+			generator.line("i32.const 1")
+		} else {
+			generator.line("i32.const 0")
+		}
+	case *ast.CharacterNode:
+		generator.line(fmt.Sprintf("i32.const %d", generator.stringAddress(string(expr.Val))))
+	case *ast.StringNode:
+		stringValue := expr.StringValue()
+		address := generator.stringAddress(stringValue[:len(stringValue)-1]) // without terminating character
+		generator.line(fmt.Sprintf("i32.const %d", address))
+	case *ast.IdentifierNode:
+		generator.line("local.get " + generator.name(expr))
+	case *ast.UnaryOperatorNode:
+		switch {
+		case expr.Operator == signature.LOGIC_NOT:
+			generator.generateExpr(expr.Expr)
+			generator.line("i32.eqz")
+		case expr.Operator == signature.NEGATE && expr.GetTyping() == typing.INT:
+			// wasm has no int negation: subtract from zero, which wraps around for the smallest int
+			generator.line("i32.const 0")
+			generator.generateExpr(expr.Expr)
+			generator.line("i32.sub")
+		case expr.Operator == signature.NEGATE && expr.GetTyping() == typing.FLOAT:
+			generator.generateExpr(expr.Expr)
+			generator.line("f64.neg")
+		default:
+			panic(fmt.Sprintf("%v: cannot generate %v", node.GetLocation(), expr.Operator))
+		}
+	case *ast.BinaryOperatorNode:
+		switch expr.Operator {
+		case signature.LOGIC_AND:
+			generator.generateConditional("i32", expr.Lhs, expr.Rhs, nil)
+		case signature.LOGIC_OR:
+			generator.generateConditional("i32", expr.Lhs, nil, expr.Rhs)
+		default:
+			generator.generateExpr(expr.Lhs)
+			generator.generateExpr(expr.Rhs)
+			generator.generateOperator(expr.Operator, expr.Lhs.GetTyping())
+		}
+	case *ast.TernaryOperatorNode:
+		generator.generateConditional(valueType(expr.GetTyping()), expr.Expr1, expr.Expr2, expr.Expr3)
+	case *ast.CallNode:
+		generator.unsupportedFunction(expr)
+	default:
+		panic(fmt.Sprintf("%v: unexpected expression %T", node.GetLocation(), node))
+	}
+}
+
+// generateConditional generates an if with a result. A nil branch is the value of the
+// condition, for short-circuiting operators.
+func (generator *Generator) generateConditional(resultType string, condition ast.Node, then ast.Node, otherwise ast.Node) {
+	generator.generateExpr(condition)
+	generator.line(fmt.Sprintf("if (result %v)", resultType))
+	generator.indent++
+
+	if then == nil {
+		generator.line("i32.const 1")
+	} else {
+		generator.generateExpr(then)
+	}
+
+	generator.indent--
+	generator.line("else")
+	generator.indent++
+
+	if otherwise == nil {
+		generator.line("i32.const 0")
+	} else {
+		generator.generateExpr(otherwise)
+	}
+
+	generator.indent--
+	generator.line("end")
+}
+
+// generateOperator applies operator to operands of the given typing, already on the stack
+func (generator *Generator) generateOperator(operator signature.Operator, operandTyping typing.Typing) {
+	var instruction string
+	var ok bool
+
+	switch operandTyping {
+	case typing.INT:
+		if operator == signature.EXPONENTIATE {
+			instruction, ok = generator.callHelper(powInt), true
+		} else {
+			instruction, ok = intInstructions[operator]
+		}
+	case typing.FLOAT:
+		if operator == signature.EXPONENTIATE {
+			generator.importPow = true
+			instruction, ok = "call $pow", true
+		} else {
+			instruction, ok = floatInstructions[operator]
+		}
+	case typing.BOOL:
+		instruction, ok = intInstructions[operator]
+		ok = ok && isEquality(operator)
+	case typing.CHAR, typing.STRING:
+		if operator == signature.ADD && operandTyping == typing.STRING {
+			instruction, ok = generator.callHelper(concat), true
+		} else if isEquality(operator) {
+			instruction, ok = generator.callHelper(stringEqual), true
+
+			if operator == signature.SHALLOW_NOT_EQUAL || operator == signature.DEEP_NOT_EQUAL {
+				instruction += "\ni32.eqz"
+			}
+		}
+	}
+
+	if !ok {
+		panic(fmt.Sprintf("cannot generate %v on %v", operator, operandTyping))
+	}
+
+	for _, line := range strings.Split(instruction, "\n") {
+		generator.line(line)
+	}
+}
+
+func isEquality(operator signature.Operator) bool {
+	switch operator {
+	case signature.SHALLOW_EQUAL, signature.DEEP_EQUAL, signature.SHALLOW_NOT_EQUAL, signature.DEEP_NOT_EQUAL:
+		return true
+	default:
+		return false
+	}
+}
+
+func (generator *Generator) callHelper(h helper) string {
+	generator.useHelper(h)
+
+	return "call " + helperNames[h]
+}
+
+func (generator *Generator) useHelper(h helper) {
+	generator.helpers[h] = true
+
+	for _, dependency := range helperDependencies[h] {
+		generator.useHelper(dependency)
+	}
+}
+
+// stringAddress returns the address of s in the static data, adding it if needed
+func (generator *Generator) stringAddress(s string) int {
+	if address, ok := generator.strings[s]; ok {
+		return address
+	}
+
+	address := len(generator.data)
+	generator.data = append(generator.data, s...)
+	generator.data = append(generator.data, 0)
+	generator.strings[s] = address
+
+	return address
+}
+
+// unsupportedFunction records a call of a host function or an extern declaration, which
+// WebAssembly modules cannot have
+func (generator *Generator) unsupportedFunction(node ast.Node) {
+	if generator.unsupported == nil {
+		generator.unsupported = node
+	}
+}
+
+func (generator *Generator) line(line string) {
+	generator.body.WriteString(strings.Repeat(indentation, generator.indent))
+	generator.body.WriteString(line)
+	generator.body.WriteString("\n")
+}
+
+func align(offset int) int {
+	return (offset + argSize - 1) / argSize * argSize
+}
+
+// dataString quotes bytes as a wat string, escaping everything but printable ascii
+func dataString(data []byte) string {
+	var quoted strings.Builder
+
+	quoted.WriteByte('"')
+
+	for _, b := range data {
+		switch {
+		case b == '"' || b == '\\':
+			quoted.WriteByte('\\')
+			quoted.WriteByte(b)
+		case b >= ' ' && b < 0x7f:
+			quoted.WriteByte(b)
+		default:
+			fmt.Fprintf(&quoted, "\\%02x", b)
+		}
+	}
+
+	quoted.WriteByte('"')
+
+	return quoted.String()
+}
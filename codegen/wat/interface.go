@@ -0,0 +1,28 @@
+package wat
+
+import (
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/logger"
+)
+
+// Generate a WebAssembly text module for ast. The module exports its memory and a main
+// function, and imports from "env":
+//
+//	print(format i32, args i32): prints like printf the null terminated format at address
+//	format. The arguments are stored from address args, 8 bytes each: ints, bools and string
+//	addresses as little endian i32, floats as f64.
+//
+//	pow(x f64, y f64) f64: only imported by programs raising floats to a power.
+func Generate(node ast.Node, logger logger.Logger) string {
+	var generator Generator
+	generator.Init()
+
+	generator.generateStmt(node)
+
+	if generator.unsupported != nil {
+		logger.Log(generator.unsupported.GetLocation(), "host and extern functions are not supported by the wat target")
+		return ""
+	}
+
+	return generator.String()
+}
@@ -0,0 +1,237 @@
+package wat
+
+// helper is a function emitted in the module when the program needs it
+type helper int
+
+const (
+	powInt helper = iota
+	stringEqual
+	strlen
+	copyBytes
+	alloc
+	concat
+	HELPER_COUNT
+)
+
+// helperDependencies are the helpers each helper calls
+var helperDependencies = map[helper][]helper{
+	concat: {strlen, copyBytes, alloc},
+}
+
+var helperNames = [...]string{
+	powInt:      "$pow_int",
+	stringEqual: "$string_equal",
+	strlen:      "$strlen",
+	copyBytes:   "$copy",
+	alloc:       "$alloc",
+	concat:      "$concat",
+}
+
+// helperDefinitions implement the operators wasm has no instruction for. Strings are addresses
+// of null terminated bytes; concatenation allocates from a heap that is never freed.
+var helperDefinitions = [...]string{
+	powInt: `  (func $pow_int (param $base i32) (param $exponent i32) (result i32)
+    (local $result i32)
+    local.get $exponent
+    i32.const 0
+    i32.lt_s
+    if
+      local.get $base
+      i32.const 1
+      i32.eq
+      if
+        i32.const 1
+        return
+      end
+      local.get $base
+      i32.const -1
+      i32.eq
+      if
+        i32.const 1
+        i32.const -1
+        local.get $exponent
+        i32.const 1
+        i32.and
+        i32.eqz
+        select
+        return
+      end
+      i32.const 0
+      return
+    end
+    i32.const 1
+    local.set $result
+    block $done
+      loop $next
+        local.get $exponent
+        i32.eqz
+        br_if $done
+        local.get $exponent
+        i32.const 1
+        i32.and
+        if
+          local.get $result
+          local.get $base
+          i32.mul
+          local.set $result
+        end
+        local.get $base
+        local.get $base
+        i32.mul
+        local.set $base
+        local.get $exponent
+        i32.const 1
+        i32.shr_u
+        local.set $exponent
+        br $next
+      end
+    end
+    local.get $result
+  )
+`,
+	stringEqual: `  (func $string_equal (param $a i32) (param $b i32) (result i32)
+    (local $byte i32)
+    block $different
+      loop $next
+        local.get $a
+        i32.load8_u
+        local.tee $byte
+        local.get $b
+        i32.load8_u
+        i32.ne
+        br_if $different
+        local.get $byte
+        i32.eqz
+        if
+          i32.const 1
+          return
+        end
+        local.get $a
+        i32.const 1
+        i32.add
+        local.set $a
+        local.get $b
+        i32.const 1
+        i32.add
+        local.set $b
+        br $next
+      end
+    end
+    i32.const 0
+  )
+`,
+	strlen: `  (func $strlen (param $s i32) (result i32)
+    (local $end i32)
+    local.get $s
+    local.set $end
+    block $done
+      loop $next
+        local.get $end
+        i32.load8_u
+        i32.eqz
+        br_if $done
+        local.get $end
+        i32.const 1
+        i32.add
+        local.set $end
+        br $next
+      end
+    end
+    local.get $end
+    local.get $s
+    i32.sub
+  )
+`,
+	copyBytes: `  (func $copy (param $to i32) (param $from i32) (param $length i32)
+    block $done
+      loop $next
+        local.get $length
+        i32.eqz
+        br_if $done
+        local.get $to
+        local.get $from
+        i32.load8_u
+        i32.store8
+        local.get $to
+        i32.const 1
+        i32.add
+        local.set $to
+        local.get $from
+        i32.const 1
+        i32.add
+        local.set $from
+        local.get $length
+        i32.const 1
+        i32.sub
+        local.set $length
+        br $next
+      end
+    end
+  )
+`,
+	alloc: `  (func $alloc (param $size i32) (result i32)
+    (local $address i32)
+    global.get $heap
+    local.set $address
+    global.get $heap
+    local.get $size
+    i32.add
+    global.set $heap
+    block $enough
+      global.get $heap
+      memory.size
+      i32.const 16
+      i32.shl
+      i32.le_u
+      br_if $enough
+      global.get $heap
+      memory.size
+      i32.const 16
+      i32.shl
+      i32.sub
+      i32.const 65535
+      i32.add
+      i32.const 16
+      i32.shr_u
+      memory.grow
+      i32.const -1
+      i32.ne
+      br_if $enough
+      unreachable
+    end
+    local.get $address
+  )
+`,
+	concat: `  (func $concat (param $a i32) (param $b i32) (result i32)
+    (local $lengthA i32)
+    (local $lengthB i32)
+    (local $result i32)
+    local.get $a
+    call $strlen
+    local.set $lengthA
+    local.get $b
+    call $strlen
+    local.set $lengthB
+    local.get $lengthA
+    local.get $lengthB
+    i32.add
+    i32.const 1
+    i32.add
+    call $alloc
+    local.set $result
+    local.get $result
+    local.get $a
+    local.get $lengthA
+    call $copy
+    local.get $result
+    local.get $lengthA
+    i32.add
+    local.get $b
+    local.get $lengthB
+    i32.const 1
+    i32.add
+    call $copy
+    local.get $result
+  )
+`,
+}
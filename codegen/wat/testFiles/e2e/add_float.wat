@@ -0,0 +1,19 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 24))
+  (data (i32.const 0) "1.1 + 2.2 = %.1f\0a\00")
+  (func $main (export "main")
+    (local $a f64)
+    f64.const 1.100000023841858
+    f64.const 2.200000047683716
+    f64.add
+    local.set $a
+    global.get $args
+    local.get $a
+    f64.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+  )
+)
@@ -0,0 +1,39 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 96))
+  (data (i32.const 0) "tab:\09|quotes:\"'|backslash:\\|not a new line:\\n|\0a\00")
+  (data (i32.const 48) "hex:Ab unicode:\c3\a9\f0\9f\98\80\0a\00")
+  (data (i32.const 71) "a\00")
+  (data (i32.const 73) "B\00")
+  (data (i32.const 75) "'\00")
+  (data (i32.const 77) "\"\00")
+  (data (i32.const 79) "\e2\98\ba\00")
+  (data (i32.const 83) "%s%s%s%s%s\0a\00")
+  (func $main (export "main")
+    i32.const 0
+    i32.const 0
+    call $print
+    i32.const 48
+    i32.const 0
+    call $print
+    global.get $args
+    i32.const 71
+    i32.store offset=0
+    global.get $args
+    i32.const 73
+    i32.store offset=8
+    global.get $args
+    i32.const 75
+    i32.store offset=16
+    global.get $args
+    i32.const 77
+    i32.store offset=24
+    global.get $args
+    i32.const 79
+    i32.store offset=32
+    i32.const 83
+    global.get $args
+    call $print
+  )
+)
@@ -0,0 +1,221 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 8))
+  (data (i32.const 0) "%d\0a\00")
+  (data (i32.const 4) "\0a\00")
+  (func $main (export "main")
+    (local $a f64)
+    (local $b f64)
+    f64.const 5.5
+    local.set $a
+    f64.const 6.599999904632568
+    local.set $b
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.gt
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.lt
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.ge
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.le
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    i32.const 4
+    i32.const 0
+    call $print
+    f64.const 6.599999904632568
+    local.set $a
+    f64.const 5.5
+    local.set $b
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.gt
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.lt
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.ge
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.le
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    i32.const 4
+    i32.const 0
+    call $print
+    f64.const 5
+    local.set $a
+    f64.const 5
+    local.set $b
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.gt
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.lt
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.ge
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.le
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    f64.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+  )
+)
@@ -0,0 +1,84 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 8))
+  (data (i32.const 0) "%d\0a\00")
+  (func $main (export "main")
+    (local $i i32)
+    (local $i_2 i32)
+    (local $j i32)
+    i32.const 0
+    local.set $i
+    i32.const 0
+    local.set $i
+    block $for.0.end
+      loop $for.0.start
+        local.get $i
+        i32.const 5
+        i32.lt_s
+        i32.eqz
+        br_if $for.0.end
+        global.get $args
+        local.get $i
+        i32.store offset=0
+        i32.const 0
+        global.get $args
+        call $print
+        local.get $i
+        i32.const 1
+        i32.add
+        local.set $i
+        br $for.0.start
+      end
+    end
+    i32.const 5
+    local.set $i_2
+    block $for.1.end
+      loop $for.1.start
+        local.get $i_2
+        i32.const 0
+        i32.ge_s
+        i32.eqz
+        br_if $for.1.end
+        global.get $args
+        local.get $i_2
+        i32.store offset=0
+        i32.const 0
+        global.get $args
+        call $print
+        local.get $i_2
+        i32.const 1
+        i32.sub
+        local.set $i_2
+        br $for.1.start
+      end
+    end
+    i32.const 0
+    local.set $j
+    block $for.2.end
+      loop $for.2.start
+        local.get $j
+        i32.const 10
+        i32.lt_s
+        i32.eqz
+        br_if $for.2.end
+        local.get $j
+        i32.const 5
+        i32.gt_s
+        if
+          global.get $args
+          local.get $j
+          i32.store offset=0
+          i32.const 0
+          global.get $args
+          call $print
+        end
+        local.get $j
+        i32.const 1
+        i32.add
+        local.set $j
+        br $for.2.start
+      end
+    end
+  )
+)
@@ -0,0 +1,254 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 176))
+  (data (i32.const 0) "test1-true\0a\00")
+  (data (i32.const 12) "test1-false\00")
+  (data (i32.const 24) "test2-false\00")
+  (data (i32.const 36) "test2-true\0a\00")
+  (data (i32.const 48) "test3-true\0a\00")
+  (data (i32.const 60) "test4-false\00")
+  (data (i32.const 72) "test5-false\00")
+  (data (i32.const 84) "test5-true\0a\00")
+  (data (i32.const 96) "test6-false\00")
+  (data (i32.const 108) "test6-true\0a\00")
+  (data (i32.const 120) "test7-false\00")
+  (data (i32.const 132) "test7-true\0a\00")
+  (data (i32.const 144) "test8-false\00")
+  (data (i32.const 156) "test8-true\0a\00")
+  (data (i32.const 168) "%d\0a\00")
+  (func $main (export "main")
+    (local $a i32)
+    (local $a_2 i32)
+    i32.const 5
+    local.set $a
+    local.get $a
+    i32.const 4
+    i32.gt_s
+    if
+      i32.const 0
+      i32.const 0
+      call $print
+    else
+      i32.const 12
+      i32.const 0
+      call $print
+    end
+    local.get $a
+    i32.const 5
+    i32.gt_s
+    if
+      i32.const 24
+      i32.const 0
+      call $print
+    else
+      i32.const 36
+      i32.const 0
+      call $print
+    end
+    local.get $a
+    i32.const 1
+    i32.gt_s
+    if
+      i32.const 48
+      i32.const 0
+      call $print
+    end
+    local.get $a
+    i32.const 1
+    i32.lt_s
+    if
+      i32.const 60
+      i32.const 0
+      call $print
+    end
+    local.get $a
+    i32.const 3
+    i32.lt_s
+    if
+      i32.const 72
+      i32.const 0
+      call $print
+    else
+      local.get $a
+      i32.const 4
+      i32.gt_s
+      if
+        i32.const 84
+        i32.const 0
+        call $print
+      end
+    end
+    local.get $a
+    i32.const 3
+    i32.lt_s
+    if
+      i32.const 96
+      i32.const 0
+      call $print
+    else
+      local.get $a
+      i32.const 4
+      i32.gt_s
+      if
+        i32.const 108
+        i32.const 0
+        call $print
+      else
+        i32.const 96
+        i32.const 0
+        call $print
+      end
+    end
+    local.get $a
+    i32.const 3
+    i32.lt_s
+    if
+      i32.const 120
+      i32.const 0
+      call $print
+    else
+      local.get $a
+      i32.const 2
+      i32.lt_s
+      if
+        i32.const 120
+        i32.const 0
+        call $print
+      else
+        i32.const 132
+        i32.const 0
+        call $print
+      end
+    end
+    local.get $a
+    i32.const 3
+    i32.lt_s
+    if
+      i32.const 144
+      i32.const 0
+      call $print
+    else
+      local.get $a
+      i32.const 2
+      i32.lt_s
+      if
+        i32.const 144
+        i32.const 0
+        call $print
+      else
+        local.get $a
+        i32.const 2
+        i32.lt_s
+        if
+          i32.const 144
+          i32.const 0
+          call $print
+        else
+          local.get $a
+          i32.const 2
+          i32.lt_s
+          if
+            i32.const 144
+            i32.const 0
+            call $print
+          else
+            local.get $a
+            i32.const 2
+            i32.lt_s
+            if
+              i32.const 144
+              i32.const 0
+              call $print
+            else
+              local.get $a
+              i32.const 2
+              i32.lt_s
+              if
+                i32.const 144
+                i32.const 0
+                call $print
+              else
+                local.get $a
+                i32.const 2
+                i32.lt_s
+                if
+                  i32.const 144
+                  i32.const 0
+                  call $print
+                else
+                  local.get $a
+                  i32.const 2
+                  i32.lt_s
+                  if
+                    i32.const 144
+                    i32.const 0
+                    call $print
+                  else
+                    local.get $a
+                    i32.const 2
+                    i32.lt_s
+                    if
+                      i32.const 144
+                      i32.const 0
+                      call $print
+                    else
+                      local.get $a
+                      i32.const 2
+                      i32.lt_s
+                      if
+                        i32.const 144
+                        i32.const 0
+                        call $print
+                      else
+                        i32.const 156
+                        i32.const 0
+                        call $print
+                      end
+                    end
+                  end
+                end
+              end
+            end
+          end
+        end
+      end
+    end
+    global.get $args
+    local.get $a
+    i32.store offset=0
+    i32.const 168
+    global.get $args
+    call $print
+    local.get $a
+    i32.const 2
+    i32.gt_s
+    if
+      global.get $args
+      local.get $a
+      i32.store offset=0
+      i32.const 168
+      global.get $args
+      call $print
+    end
+    local.get $a
+    i32.const 2
+    i32.gt_s
+    if
+      i32.const 7
+      local.set $a_2
+      global.get $args
+      local.get $a_2
+      i32.store offset=0
+      i32.const 168
+      global.get $args
+      call $print
+    end
+    global.get $args
+    local.get $a
+    i32.store offset=0
+    i32.const 168
+    global.get $args
+    call $print
+  )
+)
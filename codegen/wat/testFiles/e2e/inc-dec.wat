@@ -0,0 +1,37 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 8))
+  (data (i32.const 0) "%d\0a\00")
+  (func $main (export "main")
+    (local $a i32)
+    i32.const 5
+    local.set $a
+    global.get $args
+    local.get $a
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    local.get $a
+    i32.const 1
+    i32.add
+    local.set $a
+    global.get $args
+    local.get $a
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    local.get $a
+    i32.const 1
+    i32.sub
+    local.set $a
+    global.get $args
+    local.get $a
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+  )
+)
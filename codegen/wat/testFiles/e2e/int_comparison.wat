@@ -0,0 +1,370 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 8))
+  (data (i32.const 0) "%d\0a\00")
+  (data (i32.const 4) "\0a\00")
+  (func $main (export "main")
+    (local $a i32)
+    (local $b i32)
+    i32.const 0
+    local.set $a
+    i32.const 0
+    local.set $b
+    i32.const 5
+    local.set $a
+    i32.const 6
+    local.set $b
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.gt_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.lt_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ge_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.le_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    i32.const 4
+    i32.const 0
+    call $print
+    i32.const 6
+    local.set $a
+    i32.const 5
+    local.set $b
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.gt_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.lt_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ge_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.le_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    i32.const 4
+    i32.const 0
+    call $print
+    i32.const 5
+    local.set $a
+    i32.const 5
+    local.set $b
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.gt_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.lt_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ge_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.le_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    i32.const 4
+    i32.const 0
+    call $print
+    i32.const -5
+    local.set $a
+    i32.const 6
+    local.set $b
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.gt_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.lt_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ge_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.le_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    i32.const 4
+    i32.const 0
+    call $print
+    i32.const 5
+    local.set $a
+    i32.const -6
+    local.set $b
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.gt_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.lt_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ge_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.le_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.eq
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $a
+    local.get $b
+    i32.ne
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    i32.const 4
+    i32.const 0
+    call $print
+  )
+)
@@ -0,0 +1,186 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 8))
+  (data (i32.const 0) "%d\0a\00")
+  (data (i32.const 4) "\0a\00")
+  (func $main (export "main")
+    global.get $args
+    i32.const 1
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 0
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    i32.const 4
+    i32.const 0
+    call $print
+    global.get $args
+    i32.const 1
+    if (result i32)
+      i32.const 1
+    else
+      i32.const 0
+    end
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 1
+    if (result i32)
+      i32.const 0
+    else
+      i32.const 0
+    end
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 0
+    if (result i32)
+      i32.const 0
+    else
+      i32.const 0
+    end
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 0
+    if (result i32)
+      i32.const 1
+    else
+      i32.const 0
+    end
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    i32.const 4
+    i32.const 0
+    call $print
+    global.get $args
+    i32.const 1
+    if (result i32)
+      i32.const 1
+    else
+      i32.const 1
+    end
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 1
+    if (result i32)
+      i32.const 1
+    else
+      i32.const 0
+    end
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 0
+    if (result i32)
+      i32.const 1
+    else
+      i32.const 0
+    end
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 0
+    if (result i32)
+      i32.const 1
+    else
+      i32.const 1
+    end
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    i32.const 4
+    i32.const 0
+    call $print
+    global.get $args
+    i32.const 1
+    i32.eqz
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 0
+    i32.eqz
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    i32.const 4
+    i32.const 0
+    call $print
+    global.get $args
+    i32.const 1
+    if (result i32)
+      i32.const 0
+    else
+      i32.const 0
+    end
+    i32.eqz
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 1
+    if (result i32)
+      i32.const 0
+      i32.eqz
+    else
+      i32.const 0
+    end
+    if (result i32)
+      i32.const 1
+    else
+      i32.const 0
+    end
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 1
+    if (result i32)
+      i32.const 0
+      i32.eqz
+    else
+      i32.const 0
+    end
+    if (result i32)
+      i32.const 1
+    else
+      i32.const 0
+      if (result i32)
+        i32.const 1
+      else
+        i32.const 1
+      end
+    end
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+  )
+)
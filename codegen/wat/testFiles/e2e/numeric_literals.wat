@@ -0,0 +1,70 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 40))
+  (data (i32.const 0) "%d %d %d %d\0a\00")
+  (data (i32.const 13) "%d %d\0a\00")
+  (data (i32.const 20) "%f %f %f %f\0a\00")
+  (data (i32.const 33) "%d\0a\00")
+  (func $main (export "main")
+    (local $flags i32)
+    (local $mask i32)
+    (local $mode i32)
+    (local $million i32)
+    i32.const 10
+    local.set $flags
+    i32.const 31
+    local.set $mask
+    i32.const 493
+    local.set $mode
+    i32.const 1000000
+    local.set $million
+    global.get $args
+    local.get $flags
+    i32.store offset=0
+    global.get $args
+    local.get $mask
+    i32.store offset=8
+    global.get $args
+    local.get $mode
+    i32.store offset=16
+    global.get $args
+    local.get $million
+    i32.store offset=24
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 65535
+    i32.store offset=0
+    global.get $args
+    i32.const -2147483648
+    i32.store offset=8
+    i32.const 13
+    global.get $args
+    call $print
+    global.get $args
+    f64.const 0.5
+    f64.store offset=0
+    global.get $args
+    f64.const 5
+    f64.store offset=8
+    global.get $args
+    f64.const 2500
+    f64.store offset=16
+    global.get $args
+    f64.const 1000.25
+    f64.store offset=24
+    i32.const 20
+    global.get $args
+    call $print
+    global.get $args
+    f64.const 0.0010000000474974513
+    f64.const 0.009999999776482582
+    f64.lt
+    i32.store offset=0
+    i32.const 33
+    global.get $args
+    call $print
+  )
+)
@@ -0,0 +1,191 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 16))
+  (data (i32.const 0) "%d\0a\00")
+  (data (i32.const 4) "\0a\00")
+  (data (i32.const 6) "%.1f\0a\00")
+  (func $main (export "main")
+    global.get $args
+    i32.const 5
+    i32.const 6
+    i32.add
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 5
+    i32.const 6
+    i32.add
+    i32.const 7
+    i32.add
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 5
+    i32.const 6
+    i32.add
+    i32.const 4
+    i32.sub
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 5
+    i32.const 5
+    i32.sub
+    i32.const 5
+    i32.add
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 5
+    i32.const 6
+    i32.sub
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 5
+    i32.const 6
+    i32.sub
+    i32.const 5
+    i32.sub
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 5
+    i32.const 4
+    i32.const 3
+    i32.sub
+    i32.add
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 5
+    i32.const 6
+    i32.mul
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 5
+    i32.const 6
+    i32.div_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 6
+    i32.const 6
+    i32.div_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 6
+    i32.const 5
+    i32.rem_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 12
+    i32.const 4
+    i32.div_s
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 3
+    i32.const 5
+    i32.const 6
+    i32.add
+    i32.mul
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 5
+    i32.const 6
+    i32.add
+    i32.const 4
+    i32.const 3
+    i32.add
+    i32.mul
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 5
+    i32.const 4
+    i32.const 2
+    i32.mul
+    i32.add
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+    i32.const 4
+    i32.const 0
+    call $print
+    global.get $args
+    f64.const 1.100000023841858
+    f64.const 2.200000047683716
+    f64.add
+    f64.store offset=0
+    i32.const 6
+    global.get $args
+    call $print
+    global.get $args
+    f64.const 1.100000023841858
+    f64.const 2.200000047683716
+    f64.sub
+    f64.store offset=0
+    i32.const 6
+    global.get $args
+    call $print
+    global.get $args
+    f64.const 2.200000047683716
+    f64.const 1.100000023841858
+    f64.sub
+    f64.store offset=0
+    i32.const 6
+    global.get $args
+    call $print
+    global.get $args
+    f64.const 1
+    f64.const 3
+    f64.mul
+    f64.store offset=0
+    i32.const 6
+    global.get $args
+    call $print
+    global.get $args
+    f64.const 3
+    f64.const 1
+    f64.div
+    f64.store offset=0
+    i32.const 6
+    global.get $args
+    call $print
+  )
+)
@@ -0,0 +1,58 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 8))
+  (data (i32.const 0) "%d\0a\00")
+  (func $main (export "main")
+    (local $a i32)
+    (local $b i32)
+    (local $switch.0.test i32)
+    i32.const 5
+    local.set $a
+    i32.const 0
+    local.set $b
+    block $switch.0.end
+      block $switch.0.default
+        block $switch.0.case.3
+          block $switch.0.case.2
+            block $switch.0.case.1
+              block $switch.0.case.0
+                local.get $a
+                local.set $switch.0.test
+                local.get $switch.0.test
+                i32.const 5
+                i32.eq
+                br_if $switch.0.case.0
+                local.get $switch.0.test
+                i32.const 6
+                i32.eq
+                br_if $switch.0.case.1
+                local.get $switch.0.test
+                i32.const 7
+                i32.eq
+                br_if $switch.0.case.2
+                local.get $switch.0.test
+                i32.const 8
+                i32.eq
+                br_if $switch.0.case.3
+                br $switch.0.default
+              end
+            end
+          end
+          i32.const 7
+          local.set $b
+          br $switch.0.end
+        end
+        i32.const 8
+        local.set $b
+        br $switch.0.end
+      end
+    end
+    global.get $args
+    local.get $b
+    i32.store offset=0
+    i32.const 0
+    global.get $args
+    call $print
+  )
+)
@@ -0,0 +1,112 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 32))
+  (data (i32.const 0) "%d %d %d\0a\00")
+  (data (i32.const 10) "%d %d\0a\00")
+  (data (i32.const 17) "%f %f %f\0a\00")
+  (func $main (export "main")
+    (local $a i32)
+    (local $x f64)
+    (local $smallest i32)
+    i32.const 5
+    local.set $a
+    f64.const 2.5
+    local.set $x
+    i32.const -2147483648
+    local.set $smallest
+    global.get $args
+    local.get $a
+    i32.const 1
+    i32.sub
+    i32.store offset=0
+    global.get $args
+    local.get $a
+    i32.const 1
+    i32.sub
+    i32.store offset=8
+    global.get $args
+    local.get $a
+    i32.const -1
+    i32.sub
+    i32.store offset=16
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 0
+    local.get $a
+    i32.sub
+    i32.store offset=0
+    global.get $args
+    i32.const 0
+    i32.const 0
+    local.get $a
+    i32.sub
+    i32.sub
+    i32.store offset=8
+    global.get $args
+    i32.const 0
+    local.get $a
+    i32.const 1
+    i32.add
+    i32.sub
+    i32.const 2
+    i32.mul
+    i32.store offset=16
+    i32.const 0
+    global.get $args
+    call $print
+    global.get $args
+    local.get $smallest
+    i32.store offset=0
+    global.get $args
+    i32.const 0
+    local.get $a
+    i32.sub
+    i32.const 0
+    local.get $a
+    i32.sub
+    i32.mul
+    i32.store offset=8
+    i32.const 10
+    global.get $args
+    call $print
+    global.get $args
+    local.get $x
+    f64.neg
+    f64.store offset=0
+    global.get $args
+    local.get $x
+    f64.const 1.5
+    f64.sub
+    f64.store offset=8
+    global.get $args
+    local.get $x
+    f64.const 2
+    f64.mul
+    f64.neg
+    f64.store offset=16
+    i32.const 17
+    global.get $args
+    call $print
+    global.get $args
+    i32.const 0
+    local.get $a
+    i32.sub
+    i32.const 0
+    i32.lt_s
+    i32.store offset=0
+    global.get $args
+    i32.const 0
+    local.get $a
+    i32.sub
+    i32.const 0
+    i32.gt_s
+    i32.eqz
+    i32.store offset=8
+    i32.const 10
+    global.get $args
+    call $print
+  )
+)
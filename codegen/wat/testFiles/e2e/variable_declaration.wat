@@ -0,0 +1,24 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (func $main (export "main")
+    (local $a i32)
+    (local $b i32)
+    (local $c i32)
+    (local $d f64)
+    (local $e f64)
+    (local $f f64)
+    i32.const 0
+    local.set $a
+    i32.const 5
+    local.set $b
+    i32.const 5
+    local.set $c
+    f64.const 0
+    local.set $d
+    f64.const 5.5
+    local.set $e
+    f64.const 5.599999904632568
+    local.set $f
+  )
+)
@@ -0,0 +1,35 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 8))
+  (data (i32.const 0) "%d\00")
+  (data (i32.const 3) "\0a\00")
+  (func $main (export "main")
+    (local $a i32)
+    i32.const 5
+    local.set $a
+    block $while.0.end
+      loop $while.0.start
+        local.get $a
+        i32.const 10
+        i32.lt_s
+        i32.eqz
+        br_if $while.0.end
+        global.get $args
+        local.get $a
+        i32.store offset=0
+        i32.const 0
+        global.get $args
+        call $print
+        local.get $a
+        i32.const 1
+        i32.add
+        local.set $a
+        br $while.0.start
+      end
+    end
+    i32.const 3
+    i32.const 0
+    call $print
+  )
+)
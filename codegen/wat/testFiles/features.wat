@@ -0,0 +1,373 @@
+(module
+  (import "env" "print" (func $print (param i32 i32)))
+  (import "env" "pow" (func $pow (param f64 f64) (result f64)))
+  (memory (export "memory") 1)
+  (global $args i32 (i32.const 88))
+  (global $heap (mut i32) (i32.const 112))
+  (data (i32.const 0) "who\00")
+  (data (i32.const 4) "??\00")
+  (data (i32.const 7) "?\00")
+  (data (i32.const 9) "c\00")
+  (data (i32.const 11) "%d %f %s\0a\00")
+  (data (i32.const 21) "even\00")
+  (data (i32.const 26) "odd\00")
+  (data (i32.const 30) "%d is even\0a\00")
+  (data (i32.const 42) "%d after even\0a\00")
+  (data (i32.const 57) "d\00")
+  (data (i32.const 59) "%s shadows\0a\00")
+  (data (i32.const 71) "%d %d\0a\00")
+  (data (i32.const 78) "%d\0a\00")
+  (func $pow_int (param $base i32) (param $exponent i32) (result i32)
+    (local $result i32)
+    local.get $exponent
+    i32.const 0
+    i32.lt_s
+    if
+      local.get $base
+      i32.const 1
+      i32.eq
+      if
+        i32.const 1
+        return
+      end
+      local.get $base
+      i32.const -1
+      i32.eq
+      if
+        i32.const 1
+        i32.const -1
+        local.get $exponent
+        i32.const 1
+        i32.and
+        i32.eqz
+        select
+        return
+      end
+      i32.const 0
+      return
+    end
+    i32.const 1
+    local.set $result
+    block $done
+      loop $next
+        local.get $exponent
+        i32.eqz
+        br_if $done
+        local.get $exponent
+        i32.const 1
+        i32.and
+        if
+          local.get $result
+          local.get $base
+          i32.mul
+          local.set $result
+        end
+        local.get $base
+        local.get $base
+        i32.mul
+        local.set $base
+        local.get $exponent
+        i32.const 1
+        i32.shr_u
+        local.set $exponent
+        br $next
+      end
+    end
+    local.get $result
+  )
+  (func $string_equal (param $a i32) (param $b i32) (result i32)
+    (local $byte i32)
+    block $different
+      loop $next
+        local.get $a
+        i32.load8_u
+        local.tee $byte
+        local.get $b
+        i32.load8_u
+        i32.ne
+        br_if $different
+        local.get $byte
+        i32.eqz
+        if
+          i32.const 1
+          return
+        end
+        local.get $a
+        i32.const 1
+        i32.add
+        local.set $a
+        local.get $b
+        i32.const 1
+        i32.add
+        local.set $b
+        br $next
+      end
+    end
+    i32.const 0
+  )
+  (func $strlen (param $s i32) (result i32)
+    (local $end i32)
+    local.get $s
+    local.set $end
+    block $done
+      loop $next
+        local.get $end
+        i32.load8_u
+        i32.eqz
+        br_if $done
+        local.get $end
+        i32.const 1
+        i32.add
+        local.set $end
+        br $next
+      end
+    end
+    local.get $end
+    local.get $s
+    i32.sub
+  )
+  (func $copy (param $to i32) (param $from i32) (param $length i32)
+    block $done
+      loop $next
+        local.get $length
+        i32.eqz
+        br_if $done
+        local.get $to
+        local.get $from
+        i32.load8_u
+        i32.store8
+        local.get $to
+        i32.const 1
+        i32.add
+        local.set $to
+        local.get $from
+        i32.const 1
+        i32.add
+        local.set $from
+        local.get $length
+        i32.const 1
+        i32.sub
+        local.set $length
+        br $next
+      end
+    end
+  )
+  (func $alloc (param $size i32) (result i32)
+    (local $address i32)
+    global.get $heap
+    local.set $address
+    global.get $heap
+    local.get $size
+    i32.add
+    global.set $heap
+    block $enough
+      global.get $heap
+      memory.size
+      i32.const 16
+      i32.shl
+      i32.le_u
+      br_if $enough
+      global.get $heap
+      memory.size
+      i32.const 16
+      i32.shl
+      i32.sub
+      i32.const 65535
+      i32.add
+      i32.const 16
+      i32.shr_u
+      memory.grow
+      i32.const -1
+      i32.ne
+      br_if $enough
+      unreachable
+    end
+    local.get $address
+  )
+  (func $concat (param $a i32) (param $b i32) (result i32)
+    (local $lengthA i32)
+    (local $lengthB i32)
+    (local $result i32)
+    local.get $a
+    call $strlen
+    local.set $lengthA
+    local.get $b
+    call $strlen
+    local.set $lengthB
+    local.get $lengthA
+    local.get $lengthB
+    i32.add
+    i32.const 1
+    i32.add
+    call $alloc
+    local.set $result
+    local.get $result
+    local.get $a
+    local.get $lengthA
+    call $copy
+    local.get $result
+    local.get $lengthA
+    i32.add
+    local.get $b
+    local.get $lengthB
+    i32.const 1
+    i32.add
+    call $copy
+    local.get $result
+  )
+  (func $main (export "main")
+    (local $register i32)
+    (local $auto f64)
+    (local $expressive_name i32)
+    (local $c i32)
+    (local $i i32)
+    (local $s i32)
+    (local $switch.1.test i32)
+    (local $c_2 i32)
+    (local $x i32)
+    (local $x_2 i32)
+    i32.const 2
+    i32.const 10
+    call $pow_int
+    local.set $register
+    f64.const 2
+    f64.const 3
+    call $pow
+    local.set $auto
+    i32.const 0
+    i32.const 4
+    call $concat
+    i32.const 7
+    call $concat
+    local.set $expressive_name
+    i32.const 9
+    local.set $c
+    global.get $args
+    local.get $register
+    i32.store offset=0
+    global.get $args
+    local.get $auto
+    f64.store offset=8
+    global.get $args
+    local.get $expressive_name
+    i32.store offset=16
+    i32.const 11
+    global.get $args
+    call $print
+    i32.const 0
+    local.set $i
+    block $for.0.end
+      loop $for.0.start
+        local.get $i
+        i32.const 5
+        i32.lt_s
+        i32.eqz
+        br_if $for.0.end
+        local.get $i
+        i32.const 2
+        i32.rem_s
+        i32.const 0
+        i32.eq
+        if (result i32)
+          i32.const 21
+        else
+          i32.const 26
+        end
+        local.set $s
+        block $switch.1.end
+          block $switch.1.default
+            block $switch.1.case.0
+              local.get $s
+              local.set $switch.1.test
+              local.get $switch.1.test
+              i32.const 21
+              call $string_equal
+              br_if $switch.1.case.0
+              br $switch.1.default
+            end
+            local.get $i
+            i32.const 4
+            i32.eq
+            if
+              br $switch.1.end
+            end
+            global.get $args
+            local.get $i
+            i32.store offset=0
+            i32.const 30
+            global.get $args
+            call $print
+          end
+          global.get $args
+          local.get $i
+          i32.store offset=0
+          i32.const 42
+          global.get $args
+          call $print
+        end
+        local.get $i
+        i32.const 3
+        i32.eq
+        if
+          br $for.0.end
+        end
+        local.get $i
+        i32.const 1
+        i32.add
+        local.set $i
+        br $for.0.start
+      end
+    end
+    block $while.2.end
+      loop $while.2.start
+        local.get $c
+        i32.const 9
+        call $string_equal
+        i32.eqz
+        br_if $while.2.end
+        i32.const 57
+        local.set $c_2
+        global.get $args
+        local.get $c_2
+        i32.store offset=0
+        i32.const 59
+        global.get $args
+        call $print
+        br $while.2.end
+        br $while.2.start
+      end
+    end
+    i32.const 2147483647
+    local.set $x
+    local.get $x
+    i32.const 1
+    i32.add
+    local.set $x
+    global.get $args
+    local.get $x
+    i32.store offset=0
+    global.get $args
+    i32.const 0
+    i32.const 7
+    i32.sub
+    i32.const 2
+    i32.div_s
+    i32.store offset=8
+    i32.const 71
+    global.get $args
+    call $print
+    i32.const 1
+    if
+      local.get $x
+      i32.const 1
+      i32.add
+      local.set $x_2
+      global.get $args
+      local.get $x_2
+      i32.store offset=0
+      i32.const 78
+      global.get $args
+      call $print
+    end
+  )
+)
@@ -0,0 +1,61 @@
+package wat
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/internal/testutil"
+	"github.com/carlcui/expressive/logger"
+)
+
+// testGolden compares the module generated for every program of dirName with the .wat file of the
+// same name in expectedDirName
+func testGolden(dirName string, expectedDirName string, t *testing.T) {
+	testutil.Golden(dirName, expectedDirName, func(root ast.Node, fileName string) map[string]string {
+		var buffer logger.Buffer
+
+		actual := Generate(root, &buffer)
+
+		if buffer.ErrorsCount() > 0 {
+			t.Errorf("File %v: error(s): %v", fileName, buffer.Messages())
+		}
+
+		return map[string]string{".wat": actual}
+	}, t)
+}
+
+func TestE2ePrograms(t *testing.T) {
+	testGolden("../../e2e", "./testFiles/e2e", t)
+}
+
+func TestPrograms(t *testing.T) {
+	testGolden("./testFiles", "./testFiles", t)
+}
+
+func TestDataString(t *testing.T) {
+	cases := map[string]string{
+		"plain\x00":     `"plain\00"`,
+		"a\"b\\c\n\x00": `"a\"b\\c\0a\00"`,
+		"\xc3\xa9\x7f":  `"\c3\a9\7f"`,
+	}
+
+	for s, expected := range cases {
+		if actual := dataString([]byte(s)); actual != expected {
+			t.Errorf("Expecting %q to be quoted as %v, got %v", s, expected, actual)
+		}
+	}
+}
+
+func TestUnsupportedFunctions(t *testing.T) {
+	src := "let a = 1;\nextern func puts(s: string) -> int;\nputs(\"x\");\nprint \"%d\", puts(\"y\");"
+
+	var buffer logger.Buffer
+
+	actual := Generate(testutil.AnalyzeSource(src, nil, t), &buffer)
+
+	// calls follow the declaration of their function, which is reported
+	if expected := "at 11: host and extern functions are not supported by the wat target"; actual != "" || fmt.Sprint(buffer.Messages()) != fmt.Sprint([]string{expected}) {
+		t.Errorf("Expecting %q, got %v", expected, buffer.Messages())
+	}
+}
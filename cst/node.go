@@ -0,0 +1,82 @@
+package cst
+
+import (
+	"strings"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/token"
+)
+
+// Node is a node of the concrete syntax tree of a program. Unlike the ast, it keeps every token
+// of the source in order, with the whitespace and comments preceding them as trivia, so the
+// source can be reprinted exactly. A leaf holds a token, and a branch the tokens and branches
+// that an ast node is parsed from.
+type Node struct {
+	Token    *token.Token // the token of a leaf, nil for a branch
+	Syntax   ast.Node     // the ast node parsed from the children of a branch, nil for a leaf
+	Children []*Node
+}
+
+// NewLeaf creates the leaf of tok
+func NewLeaf(tok *token.Token) *Node {
+	return &Node{Token: tok}
+}
+
+// NewBranch creates the branch of the ast node syntax, parsed from children
+func NewBranch(syntax ast.Node, children []*Node) *Node {
+	return &Node{Syntax: syntax, Children: children}
+}
+
+// IsLeaf tells whether node holds a token
+func (node *Node) IsLeaf() bool {
+	return node.Token != nil
+}
+
+// Tokens returns the tokens of node in source order
+func (node *Node) Tokens() []*token.Token {
+	tokens := make([]*token.Token, 0)
+
+	node.Walk(func(leaf *Node) {
+		tokens = append(tokens, leaf.Token)
+	})
+
+	return tokens
+}
+
+// Walk calls visit on the leaves of node in source order
+func (node *Node) Walk(visit func(leaf *Node)) {
+	if node.IsLeaf() {
+		visit(node)
+		return
+	}
+
+	for _, child := range node.Children {
+		child.Walk(visit)
+	}
+}
+
+// Find returns the branch of the ast node syntax within node, or nil if there is none
+func (node *Node) Find(syntax ast.Node) *Node {
+	if node.Syntax == syntax && !node.IsLeaf() {
+		return node
+	}
+
+	for _, child := range node.Children {
+		if found := child.Find(syntax); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// String returns the source text of node, trivia included
+func (node *Node) String() string {
+	var text strings.Builder
+
+	node.Walk(func(leaf *Node) {
+		text.WriteString(leaf.Token.FullText())
+	})
+
+	return text.String()
+}
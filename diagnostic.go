@@ -0,0 +1,75 @@
+package expressive
+
+import (
+	"github.com/carlcui/expressive/locator"
+)
+
+// Severity tells whether a diagnostic fails the compilation
+type Severity int
+
+const (
+	Error   Severity = iota // the program cannot be compiled
+	Warning                 // the program is correct, but suspicious, e.g. a lint
+)
+
+func (severity Severity) String() string {
+	if severity == Warning {
+		return "warning"
+	}
+
+	return "error"
+}
+
+// Diagnostic is an error or a warning found while compiling a program
+type Diagnostic struct {
+	Severity Severity
+	Location string // e.g. "file main.exp: row 2, column 4", empty if the diagnostic has none
+	Row      int    // the row of Location, starting at 0, or -1 if it has none
+	Column   int    // the column of Location in runes, starting at 0, or -1 if it has none
+	Message  string
+}
+
+// String writes the diagnostic as the cli reports it
+func (diagnostic Diagnostic) String() string {
+	text := diagnostic.Message
+
+	if diagnostic.Severity == Warning {
+		text = "warning: " + text
+	}
+
+	if diagnostic.Location == "" {
+		return text
+	}
+
+	return diagnostic.Location + ": " + text
+}
+
+// diagnosticLogger is a logger.WarningLogger keeping what is logged as diagnostics
+type diagnosticLogger struct {
+	diagnostics []Diagnostic
+	errorCount  int
+}
+
+func (logger *diagnosticLogger) Log(location string, message string) {
+	logger.errorCount++
+	logger.add(Error, location, message)
+}
+
+func (logger *diagnosticLogger) Warn(location string, message string) {
+	logger.add(Warning, location, message)
+}
+
+func (logger *diagnosticLogger) ErrorsCount() int {
+	return logger.errorCount
+}
+
+func (logger *diagnosticLogger) add(severity Severity, location string, message string) {
+	diagnostic := Diagnostic{Severity: severity, Location: location, Row: -1, Column: -1, Message: message}
+
+	if loc, ok := locator.ParseFileLocation(location); ok {
+		diagnostic.Row = loc.Row
+		diagnostic.Column = loc.Col
+	}
+
+	logger.diagnostics = append(logger.diagnostics, diagnostic)
+}
@@ -0,0 +1,197 @@
+// Package expressive compiles expressive programs in-process: Check scans, parses and analyses a
+// program, Compile also generates its llvm IR, and Run interprets it. None of them panics; the
+// errors and warnings found are returned as diagnostics.
+//
+// Programs call the functions of the host given in Options.Functions by name. Run calls them
+// back, and the llvm IR of Compile declares them as externals for the linker to resolve.
+package expressive
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/codegen"
+	"github.com/carlcui/expressive/codegen/passes"
+	"github.com/carlcui/expressive/host"
+	"github.com/carlcui/expressive/input"
+	"github.com/carlcui/expressive/internal/frontend"
+	"github.com/carlcui/expressive/interp"
+	"github.com/carlcui/expressive/lint"
+	"github.com/carlcui/expressive/optimizer"
+
+	"github.com/llir/llvm/ir"
+)
+
+// defaultName names a source given as text when Options.Name is empty
+const defaultName = "<input>"
+
+// Options of a compilation. The zero value warns about every lint, and generates llvm IR without
+// optimizations, debug information or runtime checks.
+type Options struct {
+	Name              string      // the name of source text in the locations of diagnostics
+	Lints             lint.Levels // the lints to report as warnings, as errors or not at all
+	OptimizationLevel int         // 0, 1 or 2, as -O0, -O1 and -O2
+	DebugInfo         bool        // describe the program with DWARF metadata
+	Checks            bool        // guard int operations against overflow and division by zero
+
+	Functions []*host.Function // the functions of the host the program can call
+}
+
+// Result of a compilation
+type Result struct {
+	AST         ast.Node // the analysed program, nil if its source cannot be read
+	Diagnostics []Diagnostic
+
+	Module *ir.Module // the llvm IR module generated by Compile, nil if the program has errors
+	IR     string     // the llvm IR of Module
+}
+
+// HasErrors tells whether any diagnostic is an error
+func (result *Result) HasErrors() bool {
+	for _, diagnostic := range result.Diagnostics {
+		if diagnostic.Severity == Error {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check scans, parses and analyses the program src, reporting lints
+func Check(src string, options Options) *Result {
+	return compile(textInput(src, options), options, nil)
+}
+
+// CheckFile checks the program in the file at path like Check
+func CheckFile(path string, options Options) *Result {
+	return compile(fileInput(path), options, nil)
+}
+
+// Compile checks the program src, then generates its llvm IR unless it has errors
+func Compile(src string, options Options) *Result {
+	return compile(textInput(src, options), options, generate)
+}
+
+// CompileFile compiles the program in the file at path like Compile
+func CompileFile(path string, options Options) *Result {
+	return compile(fileInput(path), options, generate)
+}
+
+// Run checks the program src, then interprets it unless it has errors, writing what it prints to
+// out. The runtime error stopping the program, if any, is returned as a diagnostic. The options
+// of code generation are ignored.
+func Run(src string, options Options, out io.Writer) *Result {
+	return compile(textInput(src, options), options, interpret(out))
+}
+
+// RunFile runs the program in the file at path like Run
+func RunFile(path string, options Options, out io.Writer) *Result {
+	return compile(fileInput(path), options, interpret(out))
+}
+
+// newInput opens the source of a compilation, reporting to logger while it is scanned
+type newInput func(logger *diagnosticLogger) (input.Input, error)
+
+func textInput(src string, options Options) newInput {
+	name := options.Name
+
+	if name == "" {
+		name = defaultName
+	}
+
+	return func(logger *diagnosticLogger) (input.Input, error) {
+		var reader input.Reader
+		reader.Init(strings.NewReader(src), name, logger)
+
+		return &reader, nil
+	}
+}
+
+func fileInput(path string) newInput {
+	return func(logger *diagnosticLogger) (input.Input, error) {
+		var file input.File
+
+		if err := file.Init(filepath.Dir(path), filepath.Base(path), logger); err != nil {
+			return nil, err
+		}
+
+		return &file, nil
+	}
+}
+
+// stage completes the compilation of a program checked without errors
+type stage func(result *Result, options Options, logger *diagnosticLogger)
+
+func compile(open newInput, options Options, next stage) (result *Result) {
+	result = &Result{}
+
+	var logger diagnosticLogger
+
+	// an internal error of the compiler is reported like any other error
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Log("", fmt.Sprintf("internal compiler error: %v", err))
+
+			result.Module = nil
+			result.IR = ""
+		}
+
+		result.Diagnostics = logger.diagnostics
+	}()
+
+	sourceInput, err := open(&logger)
+
+	if err != nil {
+		logger.Log("", err.Error())
+		return result
+	}
+
+	result.AST = frontend.Analyze(sourceInput, options.Functions, &logger)
+
+	if logger.ErrorsCount() > 0 {
+		return result
+	}
+
+	lint.Check(result.AST, &options.Lints, &logger)
+
+	if next == nil || logger.ErrorsCount() > 0 {
+		return result
+	}
+
+	next(result, options, &logger)
+
+	return result
+}
+
+// generate generates the llvm IR of the program
+func generate(result *Result, options Options, logger *diagnosticLogger) {
+	if options.OptimizationLevel >= 1 {
+		optimizer.Optimize(result.AST)
+	}
+
+	codegenOptions := codegen.Options{
+		DebugInfo: options.DebugInfo,
+		Checks:    options.Checks,
+		Passes:    passes.Pipeline(options.OptimizationLevel),
+	}
+
+	result.Module = codegen.GenerateModule(result.AST, codegenOptions, logger)
+
+	if result.Module != nil {
+		result.IR = result.Module.String()
+	}
+}
+
+// interpret runs the program, calling back the functions of the host
+func interpret(out io.Writer) stage {
+	return func(result *Result, options Options, logger *diagnosticLogger) {
+		var interpreter interp.Interpreter
+		interpreter.Init(out)
+		interpreter.Register(options.Functions...)
+
+		interpreter.Exec(result.AST, logger)
+	}
+}
@@ -0,0 +1,152 @@
+package expressive
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/host"
+	"github.com/carlcui/expressive/lint"
+	"github.com/carlcui/expressive/typing"
+)
+
+func TestCompile(t *testing.T) {
+	result := Compile("let a = 1 + 2;\nprint \"%d\\n\", a;\n", Options{})
+
+	if result.HasErrors() || len(result.Diagnostics) != 0 {
+		t.Fatalf("Unexpected diagnostics %v", result.Diagnostics)
+	}
+
+	if _, ok := result.AST.(*ast.ProgramNode); !ok {
+		t.Errorf("Expecting the analysed program, got %T", result.AST)
+	}
+
+	if result.Module == nil || result.IR != result.Module.String() || !strings.Contains(result.IR, "define i32 @main()") {
+		t.Errorf("Expecting the llvm IR of the program, got\n%v", result.IR)
+	}
+}
+
+func TestCompileWithOptions(t *testing.T) {
+	src := "let a = 1;\nlet b = a + 2;\nprint \"%d\\n\", b / a;\n"
+
+	if ir := Compile(src, Options{}).IR; !strings.Contains(ir, "alloca") {
+		t.Errorf("Expecting variables in memory without optimizations, got\n%v", ir)
+	}
+
+	if ir := Compile(src, Options{OptimizationLevel: 2}).IR; strings.Contains(ir, "alloca") {
+		t.Errorf("Expecting variables promoted to registers at -O2, got\n%v", ir)
+	}
+
+	if ir := Compile(src, Options{Checks: true}).IR; !strings.Contains(ir, "sadd.with.overflow") {
+		t.Errorf("Expecting runtime checks, got\n%v", ir)
+	}
+
+	if ir := Compile(src, Options{Name: "main.exp", DebugInfo: true}).IR; !strings.Contains(ir, `!DIFile(filename: "main.exp"`) {
+		t.Errorf("Expecting debug information, got\n%v", ir)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		src      string
+		options  Options
+		expected []string
+	}{
+		{"let a = ;", Options{}, []string{"file <input>: row 0, column 8: expected expression"}},
+		{"let a = 1;\nlet b: int = \"x\";", Options{Name: "main.exp"}, []string{"file main.exp: row 1, column 0: variable declared as INT, but expression evaluated to STRING"}},
+		{"let a = 1;", Options{}, []string{"file <input>: row 0, column 4: warning: variable \"a\" is declared but never read (unused-variable)"}},
+	}
+
+	for _, test := range tests {
+		result := Compile(test.src, test.options)
+
+		diagnostics := make([]string, 0)
+
+		for _, diagnostic := range result.Diagnostics {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+
+		if strings.Join(diagnostics, "\n") != strings.Join(test.expected, "\n") {
+			t.Errorf("Expecting %q to report\n%v\ngot\n%v", test.src, strings.Join(test.expected, "\n"), strings.Join(diagnostics, "\n"))
+		}
+
+		if result.HasErrors() && (result.Module != nil || result.IR != "") {
+			t.Errorf("Expecting no llvm IR for %q", test.src)
+		}
+	}
+
+	result := Check("\nlet b: int = \"x\";", Options{})
+
+	if !result.HasErrors() || result.Diagnostics[0].Row != 1 || result.Diagnostics[0].Column != 0 {
+		t.Errorf("Expecting an error at row 1, column 0, got %v", result.Diagnostics)
+	}
+}
+
+func TestLints(t *testing.T) {
+	var denied Options
+	denied.Lints.Set("unused-variable", lint.DENY)
+
+	if result := Compile("let a = 1;", denied); !result.HasErrors() || result.Module != nil {
+		t.Errorf("Expecting a denied lint to fail compilation, got %v", result.Diagnostics)
+	}
+
+	var allowed Options
+	allowed.Lints.Set("all", lint.ALLOW)
+
+	if result := Check("let a = 1;", allowed); len(result.Diagnostics) != 0 {
+		t.Errorf("Expecting allowed lints not to be reported, got %v", result.Diagnostics)
+	}
+}
+
+func TestCheckDoesNotGenerate(t *testing.T) {
+	result := Check("print \"hi\\n\";", Options{})
+
+	if result.HasErrors() || result.AST == nil || result.Module != nil || result.IR != "" {
+		t.Errorf("Expecting an analysed program without llvm IR, got %+v", result)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	result := CompileFile("e2e/switch_1.exp", Options{})
+
+	if result.HasErrors() || result.IR == "" {
+		t.Errorf("Expecting the e2e program to compile, got %v", result.Diagnostics)
+	}
+
+	result = CheckFile("e2e/missing.exp", Options{})
+
+	if !result.HasErrors() || result.AST != nil || result.Diagnostics[0].Row != -1 {
+		t.Errorf("Expecting a missing file to be reported, got %v", result.Diagnostics)
+	}
+}
+
+func TestHostFunctions(t *testing.T) {
+	var logged []string
+
+	options := Options{Functions: []*host.Function{
+		{Name: "double", Params: []typing.Typing{typing.INT}, Result: typing.INT, Call: func(args []host.Value) (host.Value, error) {
+			return args[0].(int32) * 2, nil
+		}},
+		{Name: "log", Params: []typing.Typing{typing.STRING}, Result: typing.VOID, Call: func(args []host.Value) (host.Value, error) {
+			logged = append(logged, args[0].(string))
+			return nil, nil
+		}},
+	}}
+
+	src := "log(\"start\");\nprint \"%d\\n\", double(double(5));\n"
+
+	var out bytes.Buffer
+
+	if result := Run(src, options, &out); result.HasErrors() || out.String() != "20\n" || len(logged) != 1 || logged[0] != "start" {
+		t.Errorf("Expecting the host functions to be called back, got %q, %v and %v", out.String(), logged, result.Diagnostics)
+	}
+
+	if ir := Compile(src, options).IR; !strings.Contains(ir, "declare i32 @double(i32 %0)") || !strings.Contains(ir, "declare void @log(i8* %0)") {
+		t.Errorf("Expecting the host functions to be declared, got\n%v", ir)
+	}
+
+	if result := Check(src, Options{}); !result.HasErrors() || result.Diagnostics[0].Message != "function \"log\" is not declared" {
+		t.Errorf("Expecting calls of undeclared functions to be reported, got %v", result.Diagnostics)
+	}
+}
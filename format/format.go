@@ -0,0 +1,213 @@
+// Package format prints programs in the canonical layout of expressive source:
+//
+//   - a statement per line, blocks indented by four spaces, and opening braces on the line of
+//     their statement
+//   - `case` and `default` aligned with their `switch`, and their statements indented
+//   - a space around binary operators, after commas and keywords, and none inside parentheses,
+//     after unary operators, or before the arguments of a call and the parameters of an extern
+//   - blank lines between statements kept, at most one in a row
+//   - comments kept, either at the end of a line or on lines of their own
+package format
+
+import (
+	"strings"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/cst"
+	"github.com/carlcui/expressive/token"
+)
+
+// Source formats the program of root, the cst of a program parsed without syntax errors from a
+// lossless scanner, so that its comments are kept
+func Source(root *cst.Node) string {
+	var formatter formatter
+
+	formatter.program(root)
+
+	return formatter.printer.String()
+}
+
+type formatter struct {
+	printer printer
+
+	prev  *token.Token // the last token written
+	tight bool         // no space before the next token
+}
+
+func (formatter *formatter) program(node *cst.Node) {
+	first := true
+
+	for _, child := range node.Children {
+		if child.IsLeaf() { // EOF, with the comments ending the program
+			formatter.printer.breakLine(!first)
+			formatter.printer.comments(child.Token)
+			continue
+		}
+
+		formatter.printer.breakLine(!first)
+		formatter.node(child)
+
+		first = false
+	}
+}
+
+func (formatter *formatter) node(node *cst.Node) {
+	if node.IsLeaf() {
+		formatter.token(node.Token)
+		return
+	}
+
+	switch syntax := node.Syntax.(type) {
+	case *ast.BlockNode:
+		formatter.block(node)
+	case *ast.SwitchStmtNode:
+		formatter.switchStmt(node)
+	case *ast.UnaryOperatorNode, *ast.IntegerNode, *ast.FloatNode:
+		// the operator of a unary operation or of a negative literal is followed by its operand
+		for i, child := range node.Children {
+			formatter.node(child)
+			formatter.tight = i == 0 && len(node.Children) > 1
+		}
+	case *ast.CallNode:
+		// the callee is followed by the parenthesis of its arguments
+		for i, child := range node.Children {
+			formatter.node(child)
+			formatter.tight = i == 0
+		}
+	case *ast.ExternNode:
+		// the name of the function is followed by the parenthesis of its parameters
+		for _, child := range node.Children {
+			formatter.node(child)
+			formatter.tight = child.Syntax == syntax.Identifier
+		}
+	case *ast.TernaryOperatorNode:
+		for _, child := range node.Children {
+			if child.IsLeaf() && child.Token.TokenType == token.COLON {
+				formatter.spacedToken(child.Token)
+			} else {
+				formatter.node(child)
+			}
+		}
+	default:
+		for _, child := range node.Children {
+			formatter.node(child)
+		}
+	}
+}
+
+// block formats the statements of a block, with its braces unless it is the block of a case
+func (formatter *formatter) block(node *cst.Node) {
+	children := node.Children
+	braced := len(children) > 0 && children[0].IsLeaf() && children[0].Token.TokenType == token.LEFT_CURLY_BRACE
+
+	if braced {
+		formatter.token(children[0].Token)
+		children = children[1 : len(children)-1]
+	}
+
+	formatter.printer.indent++
+
+	for i, stmt := range children {
+		formatter.printer.breakLine(i > 0)
+		formatter.node(stmt)
+	}
+
+	if !braced {
+		formatter.printer.indent--
+		return
+	}
+
+	closing := node.Children[len(node.Children)-1].Token
+
+	if len(children) == 0 && !hasComments(closing) {
+		formatter.printer.indent--
+		formatter.tight = true
+		formatter.token(closing)
+
+		return
+	}
+
+	formatter.printer.breakLine(len(children) > 0)
+	formatter.printer.comments(closing)
+	formatter.printer.indent--
+	formatter.printer.breakLine(false)
+	formatter.write(closing)
+}
+
+// switchStmt formats a switch, with its labels on lines of their own
+func (formatter *formatter) switchStmt(node *cst.Node) {
+	firstLabel := true
+
+	for _, child := range node.Children {
+		if !child.IsLeaf() {
+			formatter.node(child)
+			continue
+		}
+
+		switch child.Token.TokenType {
+		case token.CASE, token.DEFAULT:
+			formatter.printer.breakLine(!firstLabel)
+			firstLabel = false
+		case token.RIGHT_CURLY_BRACE:
+			formatter.printer.breakLine(false)
+		}
+
+		formatter.token(child.Token)
+	}
+}
+
+// token writes tok after its comments, with a space before it unless it is tight
+func (formatter *formatter) token(tok *token.Token) {
+	formatter.printer.comments(tok)
+	formatter.write(tok)
+}
+
+// spacedToken writes tok with a space before it, e.g. the colon of a ternary operation
+func (formatter *formatter) spacedToken(tok *token.Token) {
+	formatter.printer.comments(tok)
+	formatter.printer.space = true
+	formatter.printer.write(tok.Raw, false)
+	formatter.prev = tok
+	formatter.tight = false
+}
+
+func (formatter *formatter) write(tok *token.Token) {
+	formatter.printer.space = formatter.spaceBefore(tok)
+	formatter.printer.write(tok.Raw, blankLineBefore(tok))
+
+	formatter.prev = tok
+	formatter.tight = false
+}
+
+// spaceBefore tells whether tok is separated from the token before it on its line
+func (formatter *formatter) spaceBefore(tok *token.Token) bool {
+	if formatter.prev == nil {
+		return false
+	}
+
+	// - -a is not --a, a decrement
+	if formatter.prev.TokenType == token.SUB && strings.HasPrefix(tok.Raw, "-") {
+		return true
+	}
+
+	if formatter.tight || formatter.prev.TokenType == token.LEFT_PAREN {
+		return false
+	}
+
+	switch tok.TokenType {
+	case token.SEMI, token.COMMA, token.COLON, token.RIGHT_PAREN, token.INCREMENT, token.DECREMENT:
+		return false
+	}
+
+	return true
+}
+
+func hasComments(tok *token.Token) bool {
+	for _, trivia := range tok.Trivia {
+		if trivia.TokenType == token.COMMENT {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,97 @@
+package format
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carlcui/expressive/cst"
+	"github.com/carlcui/expressive/input"
+	"github.com/carlcui/expressive/internal/frontend"
+	"github.com/carlcui/expressive/logger"
+)
+
+func parse(src string, t *testing.T) *cst.Node {
+	var stringInput input.StringInput
+	stringInput.Init(src)
+
+	var buffer logger.Buffer
+
+	root := frontend.ParseCST(&stringInput, &buffer)
+
+	if buffer.ErrorsCount() > 0 {
+		t.Fatalf("Unexpected syntax errors in\n%v", src)
+	}
+
+	return root
+}
+
+// text returns the tokens of root without their trivia
+func text(root *cst.Node) string {
+	var text strings.Builder
+
+	for _, tok := range root.Tokens() {
+		text.WriteString(tok.Raw + " ")
+	}
+
+	return text.String()
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		src, expected string
+	}{
+		{"let a:int=1+2*-3;print \"%d\\n\",a;", "let a: int = 1 + 2 * -3;\nprint \"%d\\n\", a;\n"},
+		{"if(a<2){a++;}else   if (a) {} else{ a = ( 4+3 ); }", "if (a < 2) {\n    a++;\n} else if (a) {} else {\n    a = (4 + 3);\n}\n"},
+		{"switch (a) {\n  case 1: print \"one\"; break;\n    default:\n}", "switch (a) {\ncase 1:\n    print \"one\";\n    break;\ndefault:\n}\n"},
+		{"let a = 1;\n\n\n\nlet b = 2;\nlet c = 3;", "let a = 1;\n\nlet b = 2;\nlet c = 3;\n"},
+		{"let a = - -b - -1 + !c;\nlet d = a?b:c;", "let a = - -b - -1 + !c;\nlet d = a ? b : c;\n"},
+		{"for(let i=0;i<3;i++){break;}", "for (let i = 0; i < 3; i++) {\n    break;\n}\n"},
+		{"log ( square( 2 ),\"x\" ) ;let a=f();", "log(square(2), \"x\");\nlet a = f();\n"},
+		{"extern  func printf ( f:string ,... )->int;extern func srand(seed :int);", "extern func printf(f: string, ...) -> int;\nextern func srand(seed: int);\n"},
+		{"// header\n\nlet a = 1;   // trailing\n/* own */ let b = 2;", "// header\n\nlet a = 1; // trailing\n/* own */ let b = 2;\n"},
+		{"while (true) {\n\n  // only a comment\n}", "while (true) {\n    // only a comment\n}\n"},
+		{"let c = 1 + // why\n2;", "let c = 1 + // why\n    2;\n"},
+		{"\uFEFFlet a = 1; // one\r\n/* two\r\n */\r\nprint \"%d\", a;\r\n", "let a = 1; // one\n/* two\n */\nprint \"%d\", a;\n"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if formatted := Source(parse(test.src, t)); formatted != test.expected {
+			t.Errorf("Expecting\n%q\nto be formatted as\n%q\ngot\n%q", test.src, test.expected, formatted)
+		}
+	}
+}
+
+func TestFormatIsIdempotent(t *testing.T) {
+	files, err := filepath.Glob("../e2e/*.exp")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) == 0 {
+		t.Fatal("Expecting e2e sources to format")
+	}
+
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		root := parse(string(src), t)
+		formatted := Source(root)
+		reformatted := parse(formatted, t)
+
+		if again := Source(reformatted); again != formatted {
+			t.Errorf("Expecting %v to format the same once formatted, got\n%v\nthen\n%v", file, formatted, again)
+		}
+
+		if text(reformatted) != text(root) {
+			t.Errorf("Expecting %v to keep its tokens once formatted, got\n%v", file, formatted)
+		}
+	}
+}
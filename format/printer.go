@@ -0,0 +1,131 @@
+package format
+
+import (
+	"strings"
+
+	"github.com/carlcui/expressive/token"
+)
+
+const indentation = "    "
+
+// printer writes tokens with the layout requested by the formatter, and the comments of their
+// trivia: a comment following a token on its line stays on that line, and any other comment
+// gets a line of its own
+type printer struct {
+	out    strings.Builder
+	indent int
+
+	space       bool // a space is requested before the next token
+	newline     bool // a line break is requested before the next token
+	blank       bool // the line break may be a blank line, if the source has one there
+	forcedBreak bool // the current line ends with a comment, so the next token starts a line
+	started     bool // a comment started the line requested before the next token
+}
+
+// breakLine requests the next token to start a line. The line is preceded by a blank line if
+// allowBlank is true and the source has one before the token.
+func (p *printer) breakLine(allowBlank bool) {
+	p.newline = true
+	p.blank = allowBlank
+}
+
+// comments writes the comments among the trivia of tok, at the current indentation
+func (p *printer) comments(tok *token.Token) {
+	newlines := 0
+	afterComment := false
+
+	for _, trivia := range tok.Trivia {
+		if trivia.TokenType == token.WHITESPACE {
+			newlines = strings.Count(trivia.Raw, "\n")
+
+			// a token following a comment on the next line stays there
+			if afterComment && newlines > 0 {
+				p.forcedBreak = true
+			}
+
+			continue
+		}
+
+		// formatted comments end their lines with "\n", whatever the source uses
+		comment := strings.TrimSuffix(strings.ReplaceAll(trivia.Raw, "\r\n", "\n"), "\r")
+
+		if newlines == 0 && p.out.Len() > 0 && !p.forcedBreak {
+			p.out.WriteString(" " + comment)
+		} else {
+			p.startLine(p.blank && newlines > 1, !p.newline)
+			p.out.WriteString(comment)
+
+			// past a comment on its own line, a statement is no longer the first of its block
+			p.blank = p.newline
+			p.started = true
+		}
+
+		p.forcedBreak = strings.HasPrefix(comment, "//")
+		afterComment = true
+		newlines = 0
+	}
+}
+
+// write writes text, a token, where the layout requests it. blank tells whether the source has
+// a blank line before the token.
+func (p *printer) write(text string, blank bool) {
+	switch {
+	case p.out.Len() == 0:
+	case p.forcedBreak:
+		p.startLine(p.blank && blank, !p.newline)
+	case p.newline && !p.started:
+		p.startLine(p.blank && blank, false)
+	case p.space || p.started:
+		p.out.WriteString(" ")
+	}
+
+	p.out.WriteString(text)
+
+	p.space = false
+	p.newline = false
+	p.blank = false
+	p.forcedBreak = false
+	p.started = false
+}
+
+// startLine ends the current line, if anything was written, and indents a new one, once more if
+// it continues the statement of the line
+func (p *printer) startLine(blank bool, continuation bool) {
+	if p.out.Len() == 0 {
+		return
+	}
+
+	p.out.WriteString("\n")
+
+	if blank {
+		p.out.WriteString("\n")
+	}
+
+	indent := p.indent
+
+	if continuation {
+		indent++
+	}
+
+	p.out.WriteString(strings.Repeat(indentation, indent))
+}
+
+// String returns what was written, ending with a line break unless nothing was
+func (p *printer) String() string {
+	if p.out.Len() == 0 {
+		return ""
+	}
+
+	return p.out.String() + "\n"
+}
+
+// blankLineBefore tells whether the source has a blank line right before tok, after its comments
+func blankLineBefore(tok *token.Token) bool {
+	if len(tok.Trivia) == 0 {
+		return false
+	}
+
+	last := tok.Trivia[len(tok.Trivia)-1]
+
+	return last.TokenType == token.WHITESPACE && strings.Count(last.Raw, "\n") > 1
+}
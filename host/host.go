@@ -0,0 +1,122 @@
+// Package host declares the functions of a Go program embedding the compiler, which scripts
+// call by name. The semantic analyser checks the calls against their signatures, the llvm
+// backend declares them as externals for the linker to resolve, and the interpreter and the
+// bytecode vm call them back in-process.
+package host
+
+import (
+	"fmt"
+
+	"github.com/carlcui/expressive/input"
+	"github.com/carlcui/expressive/scanner"
+	"github.com/carlcui/expressive/signature"
+	"github.com/carlcui/expressive/token"
+	"github.com/carlcui/expressive/typing"
+)
+
+// Value is the value of an argument or a result, like interp.Value: an int32 for INT, a
+// float64 for FLOAT, a bool for BOOL, and a string for CHAR and STRING.
+type Value = interface{}
+
+// Function is a function of the host
+type Function struct {
+	Name   string
+	Params []typing.Typing
+	Result typing.Typing // typing.VOID for a function returning nothing
+
+	// Call runs the function on arguments of the types of Params. Its result is ignored if the
+	// function returns nothing. An error stops the script with a runtime error.
+	Call func(args []Value) (Value, error)
+}
+
+// Type is the typing of the function name in scripts
+func (function *Function) Type() *typing.FunctionType {
+	return typing.NewFunctionType(function.Result, function.Params...)
+}
+
+// Signature is the signature calls to the function are checked against
+func (function *Function) Signature() *signature.Signature {
+	return signature.CreateFunctionSignature(function.Type())
+}
+
+// Validate tells why the function cannot be called from scripts, if it cannot
+func (function *Function) Validate() error {
+	if !isIdentifier(function.Name) {
+		return fmt.Errorf("host function %q: name is not an identifier", function.Name)
+	}
+
+	for i, param := range function.Params {
+		if !isValueTyping(param) {
+			return fmt.Errorf("host function %q: parameter %d has type %v", function.Name, i+1, param)
+		}
+	}
+
+	if function.Result == nil || !isValueTyping(function.Result) && function.Result != typing.VOID {
+		return fmt.Errorf("host function %q: result has type %v", function.Name, function.Result)
+	}
+
+	if function.Call == nil {
+		return fmt.Errorf("host function %q: Call is nil", function.Name)
+	}
+
+	return nil
+}
+
+// Invoke calls the function, checking that it returns a value of its result type. It returns
+// nil for a function returning nothing.
+func (function *Function) Invoke(args []Value) (Value, error) {
+	result, err := function.Call(args)
+
+	if err != nil {
+		return nil, fmt.Errorf("host function %v: %v", function.Name, err)
+	}
+
+	if function.Result == typing.VOID {
+		return nil, nil
+	}
+
+	if !HasTyping(result, function.Result) {
+		return nil, fmt.Errorf("host function %v returned %v of Go type %T, expecting %v", function.Name, result, result, function.Result)
+	}
+
+	return result, nil
+}
+
+// HasTyping tells whether value is a value of valueTyping
+func HasTyping(value Value, valueTyping typing.Typing) bool {
+	switch value.(type) {
+	case int32:
+		return valueTyping == typing.INT
+	case float64:
+		return valueTyping == typing.FLOAT
+	case bool:
+		return valueTyping == typing.BOOL
+	case string:
+		return valueTyping == typing.CHAR || valueTyping == typing.STRING
+	default:
+		return false
+	}
+}
+
+// isValueTyping tells whether values of t can be passed to a function
+func isValueTyping(t typing.Typing) bool {
+	switch t {
+	case typing.INT, typing.FLOAT, typing.BOOL, typing.CHAR, typing.STRING:
+		return true
+	default:
+		return false
+	}
+}
+
+// isIdentifier tells whether name scans as a single identifier, which is not a keyword
+func isIdentifier(name string) bool {
+	var stringInput input.StringInput
+	stringInput.Init(name)
+
+	var s scanner.ExpressiveScanner
+	s.Init(&stringInput)
+
+	tok := s.Next()
+
+	return tok.TokenType == token.IDENTIFIER && tok.Raw == name && s.Next().TokenType == token.EOF
+}
@@ -2,66 +2,64 @@ package input
 
 import (
 	"io/ioutil"
-	"log"
 	"path"
-	"strconv"
-	"unicode/utf8"
 
 	"github.com/carlcui/expressive/locator"
+	"github.com/carlcui/expressive/logger"
 )
 
 // File encapsulates all operations related to file IO
 type File struct {
-	curRow    int
-	curColumn int
-
-	curRead int
+	pos position
 
 	src      []byte
 	filename string
 	dirname  string
+	lossless bool // see Lossless
+
+	logger logger.Logger
 }
 
-// Init initializes File by reading and storing the contents in the file to src
-func (file *File) Init(dirname, filename string) {
+// Init initializes File by reading and storing the contents in the file to src. Bytes that are
+// not valid UTF-8 are reported to logger while reading.
+func (file *File) Init(dirname, filename string, logger logger.Logger) error {
 	filePath := path.Join(dirname, filename)
 
 	src, err := ioutil.ReadFile(filePath)
 
 	if err != nil {
-		// TODO: proper error reporting
-		panic(err)
+		return err
 	}
 
-	file.curColumn = 0
-	file.curColumn = 0
-
-	file.curRead = 0
-
 	file.src = src
 	file.filename = filename
 	file.dirname = dirname
+	file.lossless = false
+	file.logger = logger
+
+	file.pos = position{offset: skipByteOrderMark(src)}
+
+	return nil
 }
 
 // NextRune returns the next rune. The user needs to check IsEOF before calling this function.
+// An invalid byte is reported and read as utf8.RuneError.
 func (file *File) NextRune() rune {
 	if file.IsEOF() {
-		panic("EOF in " + file.dirname + "/" + file.filename)
+		panic("EOF in " + path.Join(file.dirname, file.filename))
 	}
 
-	r, size := utf8.DecodeRune(file.src[file.curRead:])
+	src := file.src[file.pos.offset:]
+	r, size := decodeRune(src, file.lossless)
 
-	if r == utf8.RuneError {
-		file.reportError("Unable to parse UTF-8 rune")
+	if isInvalidEncoding(r, size) {
+		file.reportError(invalidEncodingMessage(src[0]))
 	}
 
-	file.curRead += size
-
-	if r == '\n' { // new line
-		file.curRow++
-		file.curColumn = 0
+	if file.lossless && (isLineEnding(src) || r == ByteOrderMark && file.pos.offset == 0) {
+		file.pos.skip(size)
 	} else {
-		file.curColumn++
+		file.pos.advance(r, size)
 	}
 
 	return r
@@ -70,34 +68,39 @@ func (file *File) NextRune() rune {
 // Peek returns the next rune without modifying any field (does not considered as a read)
 func (file *File) Peek() rune {
 	if file.IsEOF() {
-		panic("EOF in " + file.dirname + "/" + file.filename)
+		panic("EOF in " + path.Join(file.dirname, file.filename))
 	}
 
-	r, _ := utf8.DecodeRune(file.src[file.curRead:])
-
-	if r == utf8.RuneError {
-		file.reportError("Unable to parse UTF-8 rune")
-	}
+	r, _ := decodeRune(file.src[file.pos.offset:], file.lossless)
 
 	return r
 }
 
+// ReadLossless makes file read the carriage returns of "\r\n" line endings and its byte order
+// mark, see Lossless
+func (file *File) ReadLossless() {
+	file.lossless = true
+	file.pos = position{}
+}
+
 // IsEOF returns true if nothing can be read further
 func (file *File) IsEOF() bool {
-	return file.curRead >= len(file.src)
+	return file.pos.offset >= len(file.src)
 }
 
 func (file *File) CurLoc() locator.Locator {
 	var loc locator.FileLocation
-	loc.Col = file.curColumn
-	loc.Row = file.curRow
+	loc.Col = file.pos.column
+	loc.Row = file.pos.row
 	loc.FileName = file.filename
 	loc.DirName = file.dirname
 
 	return &loc
 }
 
-// ReportError reports error message at current reading location
+// reportError reports error message at current reading location
 func (file *File) reportError(message string) {
-	log.Fatal(message + " at row " + strconv.Itoa(file.curRow) + ", column " + strconv.Itoa(file.curColumn))
+	if file.logger != nil {
+		file.logger.Log(file.CurLoc().Locate(), message)
+	}
 }
@@ -0,0 +1,194 @@
+package input
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/carlcui/expressive/logger"
+)
+
+type location struct {
+	row    int
+	column int
+}
+
+// readAll reads every rune of input, recording the reading location before each rune
+func readAll(input Input, locate func() location) ([]rune, []location) {
+	runes := make([]rune, 0)
+	locations := make([]location, 0)
+
+	for !input.IsEOF() {
+		locations = append(locations, locate())
+		runes = append(runes, input.NextRune())
+	}
+
+	return runes, locations
+}
+
+func openFile(fileName string, t *testing.T) (*File, *logger.Buffer) {
+	var buffer logger.Buffer
+	var file File
+
+	if err := file.Init("./testFiles", fileName, &buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	return &file, &buffer
+}
+
+func fileLocation(file *File) func() location {
+	return func() location {
+		return location{file.pos.row, file.pos.column}
+	}
+}
+
+func TestMissingFileReturnsError(t *testing.T) {
+	var file File
+
+	if err := file.Init("./testFiles", "missing.exp", &logger.Buffer{}); err == nil {
+		t.Error("Expecting error opening a missing file")
+	}
+}
+
+func TestCRLFIsReadAsOneLineBreak(t *testing.T) {
+	file, buffer := openFile("crlf.exp", t)
+
+	runes, locations := readAll(file, fileLocation(file))
+
+	if strings.ContainsRune(string(runes), '\r') {
+		t.Errorf("Expecting no carriage return, got %q", string(runes))
+	}
+
+	// `print` starts the second line
+	printIndex := strings.Index(string(runes), "print")
+
+	if locations[printIndex] != (location{1, 0}) {
+		t.Errorf("Expecting print at row 1, column 0, got %v", locations[printIndex])
+	}
+
+	if buffer.ErrorsCount() > 0 {
+		t.Errorf("Expecting no error, got %v", buffer.Messages())
+	}
+}
+
+func TestByteOrderMarkIsSkipped(t *testing.T) {
+	file, _ := openFile("bom.exp", t)
+
+	runes, locations := readAll(file, fileLocation(file))
+
+	if !strings.HasPrefix(string(runes), "let") {
+		t.Errorf("Expecting byte order mark to be skipped, got %q", string(runes))
+	}
+
+	if locations[0] != (location{0, 0}) {
+		t.Errorf("Expecting first rune at row 0, column 0, got %v", locations[0])
+	}
+}
+
+func TestInvalidByteIsReportedAtItsLocation(t *testing.T) {
+	file, buffer := openFile("invalid.exp", t)
+
+	runes, _ := readAll(file, fileLocation(file))
+
+	if !strings.ContainsRune(string(runes), utf8.RuneError) {
+		t.Errorf("Expecting invalid byte to be read as utf8.RuneError, got %q", string(runes))
+	}
+
+	messages := buffer.Messages()
+
+	if len(messages) != 1 || !strings.Contains(messages[0], "row 1, column 4") || !strings.Contains(messages[0], "0xff") {
+		t.Errorf("Expecting one error for byte 0xff at row 1, column 4, got %v", messages)
+	}
+}
+
+func TestReaderMatchesFile(t *testing.T) {
+	fileNames := []string{"crlf.exp", "bom.exp", "invalid.exp"}
+
+	for _, fileName := range fileNames {
+		file, fileBuffer := openFile(fileName, t)
+		fileRunes, fileLocations := readAll(file, fileLocation(file))
+
+		var reader Reader
+		var readerBuffer logger.Buffer
+
+		src := string(file.src)
+		reader.Init(strings.NewReader(src), fileName, &readerBuffer)
+
+		readerRunes, readerLocations := readAll(&reader, func() location {
+			return location{reader.pos.row, reader.pos.column}
+		})
+
+		if string(fileRunes) != string(readerRunes) {
+			t.Errorf("%v: reader read %q, file read %q", fileName, string(readerRunes), string(fileRunes))
+		}
+
+		for i := range fileLocations {
+			if i < len(readerLocations) && fileLocations[i] != readerLocations[i] {
+				t.Errorf("%v: rune %v located at %v by reader, but %v by file", fileName, i, readerLocations[i], fileLocations[i])
+			}
+		}
+
+		if readerBuffer.ErrorsCount() != fileBuffer.ErrorsCount() {
+			t.Errorf("%v: reader reported %v, file reported %v", fileName, readerBuffer.Messages(), fileBuffer.Messages())
+		}
+	}
+}
+
+func TestLosslessKeepsCarriageReturnsAndByteOrderMark(t *testing.T) {
+	for _, fileName := range []string{"crlf.exp", "bom.exp"} {
+		file, _ := openFile(fileName, t)
+		src := string(file.src)
+		_, expected := readAll(file, fileLocation(file))
+
+		lossless, _ := openFile(fileName, t)
+		lossless.ReadLossless()
+
+		var reader Reader
+		reader.Init(strings.NewReader(src), fileName, &logger.Buffer{})
+		reader.ReadLossless()
+
+		inputs := map[string]func() ([]rune, []location){
+			"file": func() ([]rune, []location) { return readAll(lossless, fileLocation(lossless)) },
+			"reader": func() ([]rune, []location) {
+				return readAll(&reader, func() location { return location{reader.pos.row, reader.pos.column} })
+			},
+		}
+
+		for name, read := range inputs {
+			runes, locations := read()
+
+			if string(runes) != src {
+				t.Errorf("%v: expecting lossless %v to read %q, got %q", fileName, name, src, string(runes))
+				continue
+			}
+
+			// the runes kept are not counted as columns, so the other runes are located as usual
+			kept := make([]location, 0)
+
+			for i, r := range runes {
+				if !(r == ByteOrderMark && i == 0 || r == '\r' && i+1 < len(runes) && runes[i+1] == '\n') {
+					kept = append(kept, locations[i])
+				}
+			}
+
+			for i := range expected {
+				if i >= len(kept) || kept[i] != expected[i] {
+					t.Errorf("%v: expecting lossless %v to locate runes at %v, got %v", fileName, name, expected, kept)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestStringInputDoesNotPanicOnInvalidByte(t *testing.T) {
+	var input StringInput
+	input.Init("a\xffb")
+
+	runes, _ := readAll(&input, func() location { return location{} })
+
+	if string(runes) != "a�b" {
+		t.Errorf("Expecting invalid byte to be read as utf8.RuneError, got %q", string(runes))
+	}
+}
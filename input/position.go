@@ -0,0 +1,81 @@
+package input
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// ByteOrderMark may lead a UTF-8 encoded source. It is skipped, or read by lossless inputs, and
+// not counted as a column.
+const ByteOrderMark = '\uFEFF'
+
+// Lossless is implemented by the inputs which can read the carriage return of a "\r\n" line
+// ending and a leading byte order mark as runes of their own, instead of dropping them, so that
+// the source can be reprinted exactly. Neither rune is counted as a column.
+type Lossless interface {
+	Input
+
+	// ReadLossless makes the input lossless. It must be called before reading.
+	ReadLossless()
+}
+
+// position tracks the reading position in a source. Rows and columns start at 0, and columns
+// are counted in runes.
+type position struct {
+	row    int
+	column int
+	offset int // in bytes
+}
+
+// skip moves past size bytes which are not counted as a column
+func (pos *position) skip(size int) {
+	pos.offset += size
+}
+
+func (pos *position) advance(r rune, size int) {
+	pos.offset += size
+
+	if r == '\n' { // new line
+		pos.row++
+		pos.column = 0
+	} else {
+		pos.column++
+	}
+}
+
+// decodeRune decodes the first rune in src, returning the rune and its size in bytes.
+// A "\r\n" line ending decodes to a single '\n', so that CRLF sources are scanned and
+// located exactly like LF sources, unless lossless: its carriage return then decodes on its
+// own. A byte that is not valid UTF-8 decodes to utf8.RuneError with size 1.
+func decodeRune(src []byte, lossless bool) (rune, int) {
+	if !lossless && isLineEnding(src) {
+		return '\n', 2
+	}
+
+	return utf8.DecodeRune(src)
+}
+
+// isLineEnding tells whether src starts with a "\r\n" line ending
+func isLineEnding(src []byte) bool {
+	return len(src) >= 2 && src[0] == '\r' && src[1] == '\n'
+}
+
+// isInvalidEncoding distinguishes an invalid byte from an encoded U+FFFD
+func isInvalidEncoding(r rune, size int) bool {
+	return r == utf8.RuneError && size == 1
+}
+
+func invalidEncodingMessage(invalidByte byte) string {
+	return fmt.Sprintf("invalid UTF-8 encoding: unexpected byte 0x%02x", invalidByte)
+}
+
+// skipByteOrderMark returns the number of bytes taken by a leading byte order mark in src
+func skipByteOrderMark(src []byte) int {
+	r, size := utf8.DecodeRune(src)
+
+	if r == ByteOrderMark {
+		return size
+	}
+
+	return 0
+}
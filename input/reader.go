@@ -0,0 +1,143 @@
+package input
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf8"
+
+	"github.com/carlcui/expressive/locator"
+	"github.com/carlcui/expressive/logger"
+)
+
+// Reader reads source from an io.Reader as it is scanned, without loading it into memory first.
+// It allows the compiler to consume pipes, network streams or editor buffers.
+type Reader struct {
+	pos position
+
+	reader *bufio.Reader
+	name   string // name of the source used in locations
+	eof    bool   // reading has ended, either at the end of input or on a read error
+
+	lossless      bool // see Lossless
+	byteOrderMark bool // the byte order mark skipped by Init is yet to be read by a lossless reader
+
+	logger logger.Logger
+}
+
+// Init initializes Reader to read from reader. Read errors and bytes that are not valid
+// UTF-8 are reported to logger. name identifies the source in locations.
+func (input *Reader) Init(reader io.Reader, name string, logger logger.Logger) {
+	input.pos = position{}
+	input.reader = bufio.NewReader(reader)
+	input.name = name
+	input.eof = false
+	input.lossless = false
+	input.byteOrderMark = false
+	input.logger = logger
+
+	if r, size := input.peekRune(); r == ByteOrderMark {
+		input.reader.Discard(size)
+		input.pos.skip(size)
+		input.byteOrderMark = true
+	}
+}
+
+// ReadLossless makes input read the carriage returns of "\r\n" line endings and its byte order
+// mark, see Lossless
+func (input *Reader) ReadLossless() {
+	input.lossless = true
+}
+
+// peekRune decodes the next rune without consuming it
+func (input *Reader) peekRune() (rune, int) {
+	if input.eof {
+		return utf8.RuneError, 0
+	}
+
+	buffered, err := input.reader.Peek(utf8.UTFMax)
+
+	if len(buffered) == 0 {
+		if err != io.EOF {
+			input.reportError("unable to read source: " + err.Error())
+		}
+
+		input.eof = true
+
+		return utf8.RuneError, 0
+	}
+
+	return decodeRune(buffered, input.lossless)
+}
+
+// NextRune returns the next rune. The user needs to check IsEOF before calling this function.
+// An invalid byte is reported and read as utf8.RuneError.
+func (input *Reader) NextRune() rune {
+	if input.IsEOF() {
+		panic("EOF in " + input.name)
+	}
+
+	if input.lossless && input.byteOrderMark {
+		input.byteOrderMark = false
+
+		return ByteOrderMark
+	}
+
+	r, size := input.peekRune()
+
+	if isInvalidEncoding(r, size) {
+		invalidByte, _ := input.reader.Peek(1)
+		input.reportError(invalidEncodingMessage(invalidByte[0]))
+	}
+
+	if buffered, _ := input.reader.Peek(2); input.lossless && isLineEnding(buffered) {
+		input.pos.skip(size)
+	} else {
+		input.pos.advance(r, size)
+	}
+
+	input.reader.Discard(size)
+
+	return r
+}
+
+// Peek returns the next rune without consuming it
+func (input *Reader) Peek() rune {
+	if input.IsEOF() {
+		panic("EOF in " + input.name)
+	}
+
+	if input.lossless && input.byteOrderMark {
+		return ByteOrderMark
+	}
+
+	r, _ := input.peekRune()
+
+	return r
+}
+
+// IsEOF returns true if nothing can be read further
+func (input *Reader) IsEOF() bool {
+	if input.lossless && input.byteOrderMark {
+		return false
+	}
+
+	input.peekRune()
+
+	return input.eof
+}
+
+func (input *Reader) CurLoc() locator.Locator {
+	var loc locator.FileLocation
+	loc.Col = input.pos.column
+	loc.Row = input.pos.row
+	loc.FileName = input.name
+
+	return &loc
+}
+
+// reportError reports error message at current reading location
+func (input *Reader) reportError(message string) {
+	if input.logger != nil {
+		input.logger.Log(input.CurLoc().Locate(), message)
+	}
+}
@@ -2,32 +2,39 @@ package input
 
 import (
 	"strconv"
-	"unicode/utf8"
 
 	"github.com/carlcui/expressive/locator"
 )
 
+// StringInput reads source held in a string, located by byte index. Bytes that are not valid
+// UTF-8 are not reported: sources given by users are read through Reader or File instead.
 type StringInput struct {
 	curPos int
 
-	src []byte
+	src      []byte
+	lossless bool // see Lossless
 }
 
 func (input *StringInput) Init(src string) {
-	input.curPos = 0
 	input.src = []byte(src)
+	input.curPos = skipByteOrderMark(input.src)
+	input.lossless = false
+}
+
+// ReadLossless makes input read the carriage returns of "\r\n" line endings and its byte order
+// mark, see Lossless
+func (input *StringInput) ReadLossless() {
+	input.lossless = true
+	input.curPos = 0
 }
 
+// NextRune returns the next rune. An invalid byte is read as utf8.RuneError.
 func (input *StringInput) NextRune() rune {
 	if input.IsEOF() {
 		panic("eof at " + strconv.Itoa(input.curPos))
 	}
 
-	r, size := utf8.DecodeRune(input.src[input.curPos:])
-
-	if r == utf8.RuneError {
-		panic("error decoding rune at " + strconv.Itoa(input.curPos))
-	}
+	r, size := decodeRune(input.src[input.curPos:], input.lossless)
 
 	input.curPos += size
 
@@ -39,11 +46,7 @@ func (input *StringInput) Peek() rune {
 		panic("eof at " + strconv.Itoa(input.curPos))
 	}
 
-	r, _ := utf8.DecodeRune(input.src[input.curPos:])
-
-	if r == utf8.RuneError {
-		panic("error decoding rune at " + strconv.Itoa(input.curPos))
-	}
+	r, _ := decodeRune(input.src[input.curPos:], input.lossless)
 
 	return r
 }
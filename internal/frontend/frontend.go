@@ -0,0 +1,51 @@
+// Package frontend runs the scanner, the parser and the semantic analyser over a source, so that
+// the compiler API, the commands and the tests go through the same pipeline.
+package frontend
+
+import (
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/cst"
+	"github.com/carlcui/expressive/host"
+	"github.com/carlcui/expressive/input"
+	"github.com/carlcui/expressive/logger"
+	"github.com/carlcui/expressive/parser"
+	"github.com/carlcui/expressive/scanner"
+	"github.com/carlcui/expressive/semanticAnalyser"
+)
+
+// Parse scans and parses the program of source, reporting syntax errors to logger
+func Parse(source input.Input, logger logger.Logger) ast.Node {
+	var s scanner.ExpressiveScanner
+	s.Init(source)
+
+	var p parser.Parser
+	p.Init(&s, logger)
+
+	return p.Parse()
+}
+
+// ParseCST parses the program of source into its concrete syntax tree, keeping comments and
+// whitespace
+func ParseCST(source input.Input, logger logger.Logger) *cst.Node {
+	var s scanner.ExpressiveScanner
+	s.InitLossless(source)
+
+	var p parser.Parser
+	p.Init(&s, logger)
+
+	return p.ParseCST()
+}
+
+// Analyze parses the program of source, then analyses it unless it has syntax errors. The
+// program may call the functions of the host.
+func Analyze(source input.Input, functions []*host.Function, logger logger.Logger) ast.Node {
+	root := Parse(source, logger)
+
+	if logger.ErrorsCount() > 0 {
+		return root
+	}
+
+	semanticAnalyser.AnalyzeWithHost(root, functions, logger)
+
+	return root
+}
@@ -0,0 +1,124 @@
+// Package testutil holds the fixtures shared by the tests of the compiler: analysing test
+// programs, and comparing what they generate or print with expected files.
+package testutil
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/host"
+	"github.com/carlcui/expressive/input"
+	"github.com/carlcui/expressive/internal/frontend"
+	"github.com/carlcui/expressive/logger"
+)
+
+// e.g. go test ./codegen/c -update rewrites the expected files after a deliberate change
+var update = flag.Bool("update", false, "write the generated outputs to the expected files")
+
+// AnalyzeFile parses and analyses the program fileName of dirName, failing the test if the
+// program has errors
+func AnalyzeFile(dirName string, fileName string, t *testing.T) ast.Node {
+	var buffer logger.Buffer
+	var fileInput input.File
+
+	if err := fileInput.Init(dirName, fileName, &buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	root := frontend.Analyze(&fileInput, nil, &buffer)
+
+	if buffer.ErrorsCount() > 0 {
+		t.Fatalf("File %v: error(s) encountered: %v", fileName, buffer.Messages())
+	}
+
+	return root
+}
+
+// AnalyzeSource parses and analyses the program src calling functions of the host, failing the
+// test if the program has errors. Locations in src are byte indices.
+func AnalyzeSource(src string, functions []*host.Function, t *testing.T) ast.Node {
+	var buffer logger.Buffer
+	var stringInput input.StringInput
+	stringInput.Init(src)
+
+	root := frontend.Analyze(&stringInput, functions, &buffer)
+
+	if buffer.ErrorsCount() > 0 {
+		t.Fatalf("Error(s) encountered: %v", buffer.Messages())
+	}
+
+	return root
+}
+
+// Golden compares the outputs generated for every program of dirName with the files of the same
+// name in expectedDirName. generate returns the outputs by the extension replacing .exp in the
+// name of their file. With -update, the files are written instead.
+func Golden(dirName string, expectedDirName string, generate func(root ast.Node, fileName string) map[string]string, t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(dirName, "*.exp"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		fileName := filepath.Base(file)
+
+		for ext, actual := range generate(AnalyzeFile(dirName, fileName, t), fileName) {
+			expectedFile := filepath.Join(expectedDirName, strings.TrimSuffix(fileName, ".exp")+ext)
+
+			if *update {
+				if err := ioutil.WriteFile(expectedFile, []byte(actual), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				continue
+			}
+
+			expected, err := ioutil.ReadFile(expectedFile)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if actual != string(expected) {
+				t.Errorf("File %v: expecting\n%v\nbut got\n%v", expectedFile, string(expected), actual)
+			}
+		}
+	}
+}
+
+// Outputs runs every program of dirName having a .txt file holding its expected output, and
+// compares what it prints with the file. run returns the output of the program and its runtime
+// errors.
+func Outputs(dirName string, run func(root ast.Node) (string, *logger.Buffer), t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(dirName, "*.txt"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expectedFile := range files {
+		fileName := strings.TrimSuffix(filepath.Base(expectedFile), ".txt") + ".exp"
+
+		expected, err := ioutil.ReadFile(expectedFile)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual, buffer := run(AnalyzeFile(dirName, fileName, t))
+
+		if buffer.ErrorsCount() > 0 {
+			t.Errorf("File %v: runtime error(s): %v", fileName, buffer.Messages())
+		}
+
+		// expected outputs are compared the way test_e2e.sh does, ignoring trailing new lines
+		if strings.TrimRight(actual, "\n") != strings.TrimRight(string(expected), "\n") {
+			t.Errorf("File %v: expecting output\n%v\nbut got\n%v", fileName, string(expected), actual)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package interp
+
+import (
+	"io"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/logger"
+)
+
+// Run executes an analysed program, writing what it prints to out. A runtime error, e.g. an
+// integer division by zero, stops the program and is reported to logger.
+func Run(node ast.Node, out io.Writer, logger logger.Logger) {
+	var interpreter Interpreter
+	interpreter.Init(out)
+
+	interpreter.Exec(node, logger)
+}
+
+// Exec executes an analysed program like Run, keeping the values of its variables for what is
+// executed next, e.g. the following entries of a repl. It returns false if a runtime error
+// stopped the program.
+func (interpreter *Interpreter) Exec(node ast.Node, logger logger.Logger) bool {
+	return interpreter.guard(logger, func() {
+		interpreter.exec(node)
+	})
+}
+
+// Eval evaluates an analysed expression with the values of the variables executed so far. It
+// returns false if a runtime error stopped the evaluation.
+func (interpreter *Interpreter) Eval(node ast.Node, logger logger.Logger) (Value, bool) {
+	var value Value
+
+	ok := interpreter.guard(logger, func() {
+		value = interpreter.eval(node)
+	})
+
+	return value, ok
+}
+
+// guard runs run, and reports the runtime error stopping it to logger
+func (interpreter *Interpreter) guard(logger logger.Logger, run func()) (ok bool) {
+	// flush what has been printed before reporting a runtime error
+	defer func() {
+		interpreter.out.Flush()
+
+		if err := recover(); err != nil {
+			runtimeErr, isRuntimeErr := err.(runtimeError)
+
+			if !isRuntimeErr {
+				panic(err)
+			}
+
+			logger.Log(runtimeErr.location, "runtime error: "+runtimeErr.message)
+			ok = false
+		}
+	}()
+
+	run()
+
+	return true
+}
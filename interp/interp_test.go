@@ -0,0 +1,110 @@
+package interp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/host"
+	"github.com/carlcui/expressive/internal/testutil"
+	"github.com/carlcui/expressive/logger"
+	"github.com/carlcui/expressive/typing"
+)
+
+func run(root ast.Node) (string, *logger.Buffer) {
+	var out bytes.Buffer
+	var buffer logger.Buffer
+
+	Run(root, &out, &buffer)
+
+	return out.String(), &buffer
+}
+
+func TestE2ePrograms(t *testing.T) {
+	testutil.Outputs("../e2e", run, t)
+}
+
+func TestPrograms(t *testing.T) {
+	testutil.Outputs("./testFiles", run, t)
+}
+
+func TestRuntimeError(t *testing.T) {
+	out, buffer := run(testutil.AnalyzeFile("./testFiles", "division_by_zero.exp", t))
+
+	if out != "before\n" {
+		t.Errorf("Expecting output before the runtime error to be printed, got %q", out)
+	}
+
+	messages := buffer.Messages()
+
+	if len(messages) != 1 || !strings.Contains(messages[0], "row 3, column 16: runtime error: integer division by zero") {
+		t.Errorf("Expecting division by zero at row 3, column 16, got %v", messages)
+	}
+}
+
+func TestHostFunctions(t *testing.T) {
+	var out bytes.Buffer
+	var calls []string
+
+	functions := []*host.Function{
+		{Name: "square", Params: []typing.Typing{typing.INT}, Result: typing.INT, Call: func(args []host.Value) (host.Value, error) {
+			calls = append(calls, fmt.Sprintf("square %v after %q", args[0], out.String()))
+			return args[0].(int32) * args[0].(int32), nil
+		}},
+		{Name: "fail", Params: []typing.Typing{typing.STRING}, Result: typing.VOID, Call: func(args []host.Value) (host.Value, error) {
+			return nil, errors.New(args[0].(string))
+		}},
+	}
+
+	root := testutil.AnalyzeSource("print \"before\\n\";\nprint \"%d\\n\", square(square(2)) + 1;\nfail(\"no\");\nprint \"after\\n\";\n", functions, t)
+
+	var buffer logger.Buffer
+	var interpreter Interpreter
+	interpreter.Init(&out)
+	interpreter.Register(functions...)
+
+	if interpreter.Exec(root, &buffer) {
+		t.Error("Expecting the failing host function to stop the program")
+	}
+
+	if out.String() != "before\n17\n" {
+		t.Errorf("Expecting the output before the failing call, got %q", out.String())
+	}
+
+	if expected := []string{"square 2 after \"before\\n\"", "square 4 after \"before\\n\""}; fmt.Sprint(calls) != fmt.Sprint(expected) {
+		t.Errorf("Expecting calls %v, got %v", expected, calls)
+	}
+
+	messages := buffer.Messages()
+
+	if len(messages) != 1 || !strings.HasSuffix(messages[0], "runtime error: host function fail: no") {
+		t.Errorf("Expecting the error of the host function, got %v", messages)
+	}
+
+	buffer = logger.Buffer{}
+	Run(root, &out, &buffer)
+
+	if messages := buffer.Messages(); len(messages) != 1 || !strings.Contains(messages[0], "runtime error: host function square is not registered") {
+		t.Errorf("Expecting the unregistered function to be reported, got %v", messages)
+	}
+}
+
+func TestExternFunctions(t *testing.T) {
+	root := testutil.AnalyzeSource("extern func puts(s: string) -> int;\nprint \"before\\n\";\nputs(\"a\");\nprint \"after\\n\";\n", nil, t)
+
+	var out bytes.Buffer
+	var buffer logger.Buffer
+
+	Run(root, &out, &buffer)
+
+	if out.String() != "before\n" {
+		t.Errorf("Expecting the output before the call, got %q", out.String())
+	}
+
+	if messages := buffer.Messages(); len(messages) != 1 || !strings.HasSuffix(messages[0], "runtime error: extern function puts cannot be called by the interpreter") {
+		t.Errorf("Expecting the call of the extern function to be reported, got %v", messages)
+	}
+}
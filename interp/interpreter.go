@@ -0,0 +1,248 @@
+package interp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/builtin"
+	"github.com/carlcui/expressive/host"
+	"github.com/carlcui/expressive/signature"
+	"github.com/carlcui/expressive/symbolTable"
+)
+
+// Value is the value of an expression at run time: an int32 for int, a float64 for float, a
+// bool for bool, and a string for char and string.
+type Value = interface{}
+
+// runtimeError aborts the program. It is raised as a panic and recovered by Run.
+type runtimeError struct {
+	location string
+	message  string
+}
+
+// Interpreter executes an analysed ast directly, with the same semantics as the generated llvm
+// IR: ints wrap around at 32 bits, float literals have single precision, and print behaves like
+// printf.
+type Interpreter struct {
+	out       *bufio.Writer
+	variables map[*symbolTable.Binding]Value
+	functions map[string]*host.Function
+	externs   map[string]bool // the functions declared extern, which only compiled code can call
+}
+
+func (interpreter *Interpreter) Init(out io.Writer) {
+	interpreter.out = bufio.NewWriter(out)
+	interpreter.variables = make(map[*symbolTable.Binding]Value)
+	interpreter.functions = make(map[string]*host.Function)
+	interpreter.externs = make(map[string]bool)
+}
+
+// Register makes the calls of the functions of the host call them back
+func (interpreter *Interpreter) Register(functions ...*host.Function) {
+	for _, function := range functions {
+		interpreter.functions[function.Name] = function
+	}
+}
+
+// completion tells how a statement finished
+type completion int
+
+const (
+	normal completion = iota
+	breaking
+)
+
+func (interpreter *Interpreter) execStmts(stmts []ast.Node) completion {
+	for _, stmt := range stmts {
+		if interpreter.exec(stmt) == breaking {
+			return breaking
+		}
+	}
+
+	return normal
+}
+
+func (interpreter *Interpreter) exec(node ast.Node) completion {
+	switch stmt := node.(type) {
+	case nil:
+		return normal
+	case *ast.ProgramNode:
+		return interpreter.execStmts(stmt.Chilren)
+	case *ast.BlockNode:
+		return interpreter.execStmts(stmt.Stmts)
+	case *ast.VariableDeclarationNode:
+		identifier := stmt.Identifier.(*ast.IdentifierNode)
+
+		if stmt.Expr == nil {
+			interpreter.variables[identifier.GetBinding()] = defaultValue(identifier)
+		} else {
+			interpreter.variables[identifier.GetBinding()] = interpreter.eval(stmt.Expr)
+		}
+	case *ast.AssignmentNode:
+		identifier := stmt.LHS.(*ast.IdentifierNode)
+		value := interpreter.eval(stmt.RHS)
+
+		if stmt.Operator != signature.VOID_OPERATOR {
+			value = interpreter.operate(stmt, stmt.Operator, interpreter.eval(identifier), value)
+		}
+
+		interpreter.variables[identifier.GetBinding()] = value
+	case *ast.IncDecNode:
+		identifier := stmt.LHS.(*ast.IdentifierNode)
+		value := interpreter.eval(identifier).(int32)
+
+		if stmt.IsIncrement {
+			value++
+		} else {
+			value--
+		}
+
+		interpreter.variables[identifier.GetBinding()] = value
+	case *ast.PrintNode:
+		args := make([]Value, len(stmt.Args))
+
+		for i, arg := range stmt.Args {
+			args[i] = interpreter.eval(arg)
+		}
+
+		format := interpreter.eval(stmt.StringExpr).(string)
+
+		if err := builtin.Printf(interpreter.out, format, args); err != nil {
+			interpreter.raise(stmt, err.Error())
+		}
+	case *ast.IfStmtNode:
+		for i, conditionExpr := range stmt.ConditionExprs {
+			if interpreter.eval(conditionExpr).(bool) {
+				return interpreter.exec(stmt.ConditionBlocks[i])
+			}
+		}
+
+		return interpreter.exec(stmt.ElseBlock)
+	case *ast.WhileStmtNode:
+		for interpreter.eval(stmt.ConditionExpr).(bool) {
+			if interpreter.exec(stmt.Block) == breaking {
+				break
+			}
+		}
+	case *ast.ForStmtNode:
+		interpreter.exec(stmt.InitializationStmt)
+
+		for stmt.ConditionExpr == nil || interpreter.eval(stmt.ConditionExpr).(bool) {
+			if interpreter.exec(stmt.Block) == breaking {
+				break
+			}
+
+			interpreter.exec(stmt.IterationStmt)
+		}
+	case *ast.SwitchStmtNode:
+		interpreter.execSwitchStmt(stmt)
+	case *ast.BreakNode:
+		return breaking
+	case *ast.CallNode:
+		interpreter.call(stmt)
+	case *ast.ExternNode:
+		interpreter.externs[stmt.Name()] = true
+	default:
+		panic(fmt.Sprintf("%v: unexpected statement %T", node.GetLocation(), node))
+	}
+
+	return normal
+}
+
+// execSwitchStmt runs the block of the first matching case, then falls through the following
+// blocks and the default block until a break
+func (interpreter *Interpreter) execSwitchStmt(node *ast.SwitchStmtNode) {
+	testValue := interpreter.eval(node.TestExpr)
+
+	matched := false
+
+	for i, caseExpr := range node.CaseExprs {
+		if !matched && interpreter.eval(caseExpr) != testValue {
+			continue
+		}
+
+		matched = true
+
+		if interpreter.exec(node.CaseBlocks[i]) == breaking {
+			return
+		}
+	}
+
+	interpreter.exec(node.DefaultBlock)
+}
+
+func (interpreter *Interpreter) eval(node ast.Node) Value {
+	switch expr := node.(type) {
+	case *ast.IntegerNode:
+		return int32(expr.Val)
+	case *ast.FloatNode:
+		return float64(expr.Val)
+	case *ast.BooleanNode:
+		return expr.Val
+	case *ast.CharacterNode:
+		return string(expr.Val)
+	case *ast.StringNode:
+		stringValue := expr.StringValue()
+		return stringValue[:len(stringValue)-1] // without terminating character
+	case *ast.IdentifierNode:
+		return interpreter.variables[expr.GetBinding()]
+	case *ast.UnaryOperatorNode:
+		return interpreter.operate(expr, expr.Operator, interpreter.eval(expr.Expr))
+	case *ast.BinaryOperatorNode:
+		// short-circuit evaluation
+		switch expr.Operator {
+		case signature.LOGIC_AND:
+			return interpreter.eval(expr.Lhs).(bool) && interpreter.eval(expr.Rhs).(bool)
+		case signature.LOGIC_OR:
+			return interpreter.eval(expr.Lhs).(bool) || interpreter.eval(expr.Rhs).(bool)
+		}
+
+		return interpreter.operate(expr, expr.Operator, interpreter.eval(expr.Lhs), interpreter.eval(expr.Rhs))
+	case *ast.TernaryOperatorNode:
+		if interpreter.eval(expr.Expr1).(bool) {
+			return interpreter.eval(expr.Expr2)
+		}
+
+		return interpreter.eval(expr.Expr3)
+	case *ast.CallNode:
+		return interpreter.call(expr)
+	default:
+		panic(fmt.Sprintf("%v: unexpected expression %T", node.GetLocation(), node))
+	}
+}
+
+// call calls back the host function called by node, with what has been printed so far written
+// out
+func (interpreter *Interpreter) call(node *ast.CallNode) Value {
+	args := make([]Value, len(node.Args))
+
+	for i, arg := range node.Args {
+		args[i] = interpreter.eval(arg)
+	}
+
+	if interpreter.externs[node.Name()] {
+		interpreter.raise(node, "extern function "+node.Name()+" cannot be called by the interpreter")
+	}
+
+	function, ok := interpreter.functions[node.Name()]
+
+	if !ok {
+		interpreter.raise(node, "host function "+node.Name()+" is not registered")
+	}
+
+	interpreter.out.Flush()
+
+	result, err := function.Invoke(args)
+
+	if err != nil {
+		interpreter.raise(node, err.Error())
+	}
+
+	return result
+}
+
+func (interpreter *Interpreter) raise(node ast.Node, message string) {
+	panic(runtimeError{node.GetLocation(), message})
+}
@@ -0,0 +1,132 @@
+package interp
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/builtin"
+	"github.com/carlcui/expressive/signature"
+	"github.com/carlcui/expressive/typing"
+)
+
+// defaultValue is the value of a variable declared without initializer
+func defaultValue(identifier *ast.IdentifierNode) Value {
+	switch identifier.GetTyping() {
+	case typing.INT:
+		return int32(0)
+	case typing.FLOAT:
+		return float64(0)
+	case typing.BOOL:
+		return false
+	case typing.CHAR, typing.STRING:
+		return ""
+	default:
+		panic(fmt.Sprintf("%v: no default value for type %v", identifier.GetLocation(), identifier.GetTyping()))
+	}
+}
+
+// operate applies an operator to operands already evaluated. Semantic analysis guarantees the
+// operands have types the operator supports.
+func (interpreter *Interpreter) operate(node ast.Node, operator signature.Operator, operands ...Value) Value {
+	switch operator {
+	case signature.LOGIC_NOT:
+		return !operands[0].(bool)
+	case signature.NEGATE:
+		switch operand := operands[0].(type) {
+		case int32:
+			return -operand // wraps around for the lowest int, as at runtime
+		case float64:
+			return -operand
+		}
+	}
+
+	switch lhs := operands[0].(type) {
+	case int32:
+		return interpreter.operateInt(node, operator, lhs, operands[1].(int32))
+	case float64:
+		return operateFloat(operator, lhs, operands[1].(float64))
+	case bool:
+		return operateEquality(operator, lhs == operands[1].(bool))
+	case string:
+		if operator == signature.ADD {
+			return lhs + operands[1].(string)
+		}
+
+		// strings have value semantics, so shallow and deep equality are the same
+		return operateEquality(operator, lhs == operands[1].(string))
+	}
+
+	panic(fmt.Sprintf("%v: cannot apply %v on %v", node.GetLocation(), operator, operands))
+}
+
+func (interpreter *Interpreter) operateInt(node ast.Node, operator signature.Operator, lhs int32, rhs int32) Value {
+	switch operator {
+	case signature.ADD:
+		return lhs + rhs
+	case signature.SUBTRACT:
+		return lhs - rhs
+	case signature.MULTIPLY:
+		return lhs * rhs
+	case signature.DIVIDE:
+		if rhs == 0 {
+			interpreter.raise(node, "integer division by zero")
+		}
+
+		return lhs / rhs
+	case signature.MODULO:
+		if rhs == 0 {
+			interpreter.raise(node, "integer division by zero")
+		}
+
+		return lhs % rhs
+	case signature.EXPONENTIATE:
+		return builtin.PowInt(lhs, rhs)
+	case signature.GREATER:
+		return lhs > rhs
+	case signature.GREATER_OR_EQUAL:
+		return lhs >= rhs
+	case signature.LESS:
+		return lhs < rhs
+	case signature.LESS_OR_EQUAL:
+		return lhs <= rhs
+	}
+
+	return operateEquality(operator, lhs == rhs)
+}
+
+func operateFloat(operator signature.Operator, lhs float64, rhs float64) Value {
+	switch operator {
+	case signature.ADD:
+		return lhs + rhs
+	case signature.SUBTRACT:
+		return lhs - rhs
+	case signature.MULTIPLY:
+		return lhs * rhs
+	case signature.DIVIDE:
+		return lhs / rhs
+	case signature.EXPONENTIATE:
+		return math.Pow(lhs, rhs)
+	case signature.GREATER:
+		return lhs > rhs
+	case signature.GREATER_OR_EQUAL:
+		return lhs >= rhs
+	case signature.LESS:
+		return lhs < rhs
+	case signature.LESS_OR_EQUAL:
+		return lhs <= rhs
+	}
+
+	return operateEquality(operator, lhs == rhs)
+}
+
+func operateEquality(operator signature.Operator, equal bool) Value {
+	switch operator {
+	case signature.SHALLOW_EQUAL, signature.DEEP_EQUAL:
+		return equal
+	case signature.SHALLOW_NOT_EQUAL, signature.DEEP_NOT_EQUAL:
+		return !equal
+	}
+
+	panic(fmt.Sprintf("cannot apply %v on equality", operator))
+}
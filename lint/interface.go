@@ -0,0 +1,16 @@
+package lint
+
+import (
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/logger"
+)
+
+// Check reports lints found in an analysed program. Warnings are only reported if logger is a
+// logger.WarningLogger, denied lints are logged as errors.
+func Check(node ast.Node, levels *Levels, logger logger.Logger) {
+	var visitor LintVisitor
+	visitor.levels = levels
+	visitor.logger = logger
+
+	node.Accept(&visitor)
+}
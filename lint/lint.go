@@ -0,0 +1,92 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lint is a kind of warning reported over a correct program
+type Lint int
+
+const (
+	UNUSED_VARIABLE Lint = iota
+	UNUSED_CONST
+	SHADOWING
+	DEAD_CODE
+	EMPTY_CASE
+	CONSTANT_CONDITION
+	LINT_COUNT
+)
+
+var lintNames = [...]string{
+	UNUSED_VARIABLE:    "unused-variable",
+	UNUSED_CONST:       "unused-const",
+	SHADOWING:          "shadowing",
+	DEAD_CODE:          "dead-code",
+	EMPTY_CASE:         "empty-case",
+	CONSTANT_CONDITION: "constant-condition",
+}
+
+// allLints is the name standing for every lint in --allow and --deny
+const allLints = "all"
+
+func (lint Lint) String() string {
+	if lint >= UNUSED_VARIABLE && lint < LINT_COUNT {
+		return lintNames[lint]
+	}
+
+	return "unknown lint"
+}
+
+// ParseLint finds the lint with the given name
+func ParseLint(name string) (Lint, bool) {
+	for lint, lintName := range lintNames {
+		if lintName == name {
+			return Lint(lint), true
+		}
+	}
+
+	return LINT_COUNT, false
+}
+
+// Names returns the names of every lint
+func Names() []string {
+	return lintNames[:]
+}
+
+// Level tells what to do when a lint is found
+type Level int
+
+const (
+	WARN  Level = iota // report a warning, which does not fail the compilation
+	ALLOW              // do not report
+	DENY               // report an error
+)
+
+// Levels holds the level of every lint. The zero value warns about every lint.
+type Levels [LINT_COUNT]Level
+
+// Set sets the level of lints given as a comma separated list of names, or "all"
+func (levels *Levels) Set(names string, level Level) error {
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+
+		if name == allLints {
+			for lint := range levels {
+				levels[lint] = level
+			}
+
+			continue
+		}
+
+		lint, ok := ParseLint(name)
+
+		if !ok {
+			return fmt.Errorf("unknown lint %q, expecting one of %v or %v", name, strings.Join(Names(), ", "), allLints)
+		}
+
+		levels[lint] = level
+	}
+
+	return nil
+}
@@ -0,0 +1,299 @@
+package lint
+
+import (
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/logger"
+	"github.com/carlcui/expressive/signature"
+	"github.com/carlcui/expressive/token"
+	"github.com/carlcui/expressive/typing"
+)
+
+// LintVisitor walks an analysed ast and reports lints
+type LintVisitor struct {
+	levels *Levels
+	logger logger.Logger
+}
+
+func (visitor *LintVisitor) VisitEnterProgramNode(node *ast.ProgramNode) {
+
+}
+
+func (visitor *LintVisitor) VisitLeaveProgramNode(node *ast.ProgramNode) {
+
+}
+
+// VisitEnterBlockNode reports statements following a break in the same block
+func (visitor *LintVisitor) VisitEnterBlockNode(node *ast.BlockNode) {
+	for i, stmt := range node.Stmts {
+		if _, ok := stmt.(*ast.BreakNode); ok && i+1 < len(node.Stmts) {
+			visitor.report(DEAD_CODE, node.Stmts[i+1].GetLocation(), "unreachable statement after break")
+			return
+		}
+	}
+}
+
+func (visitor *LintVisitor) VisitLeaveBlockNode(node *ast.BlockNode) {
+
+}
+
+// stmts
+
+func (visitor *LintVisitor) VisitEnterVariableDeclarationNode(node *ast.VariableDeclarationNode) {
+
+}
+
+// VisitLeaveVariableDeclarationNode reports unused and shadowing declarations. Semantic analysis
+// has already seen every read of the variable.
+func (visitor *LintVisitor) VisitLeaveVariableDeclarationNode(node *ast.VariableDeclarationNode) {
+	identifier, ok := node.Identifier.(*ast.IdentifierNode)
+
+	if !ok || identifier.GetBinding() == nil || !node.GetTyping().Equals(typing.VOID) {
+		return
+	}
+
+	name := identifier.Tok.Raw
+	binding := identifier.GetBinding()
+
+	if !binding.IsRead() {
+		if node.Tok.TokenType == token.CONST {
+			visitor.report(UNUSED_CONST, identifier.GetLocation(), "const \""+name+"\" is declared but never read")
+		} else {
+			visitor.report(UNUSED_VARIABLE, identifier.GetLocation(), "variable \""+name+"\" is declared but never read")
+		}
+	}
+
+	for scope := identifier.GetLocalScope().BaseScope; scope != nil; scope = scope.BaseScope {
+		if shadowed := scope.FindBinding(name); shadowed != nil {
+			visitor.report(SHADOWING, identifier.GetLocation(),
+				"variable \""+name+"\" shadows the variable declared at "+shadowed.GetLocator().Locate())
+			return
+		}
+	}
+}
+
+func (visitor *LintVisitor) VisitEnterAssignmentNode(node *ast.AssignmentNode) {
+
+}
+
+func (visitor *LintVisitor) VisitLeaveAssignmentNode(node *ast.AssignmentNode) {
+
+}
+
+func (visitor *LintVisitor) VisitEnterIncDecNode(node *ast.IncDecNode) {
+
+}
+
+func (visitor *LintVisitor) VisitLeaveIncDecNode(node *ast.IncDecNode) {
+
+}
+
+func (visitor *LintVisitor) VisitEnterIfStmtNode(node *ast.IfStmtNode) {
+	for _, conditionExpr := range node.ConditionExprs {
+		visitor.checkCondition(conditionExpr)
+	}
+}
+
+func (visitor *LintVisitor) VisitLeaveIfStmtNode(node *ast.IfStmtNode) {
+
+}
+
+// VisitEnterWhileStmtNode reports constant conditions, except for `while (true)` which is how
+// an infinite loop is written
+func (visitor *LintVisitor) VisitEnterWhileStmtNode(node *ast.WhileStmtNode) {
+	if literal, ok := node.ConditionExpr.(*ast.BooleanNode); ok && literal.Val {
+		return
+	}
+
+	visitor.checkCondition(node.ConditionExpr)
+}
+
+func (visitor *LintVisitor) VisitLeaveWhileStmtNode(node *ast.WhileStmtNode) {
+
+}
+
+func (visitor *LintVisitor) VisitEnterForStmtNode(node *ast.ForStmtNode) {
+	if node.ConditionExpr == nil {
+		return
+	}
+
+	if literal, ok := node.ConditionExpr.(*ast.BooleanNode); ok && literal.Val {
+		return
+	}
+
+	visitor.checkCondition(node.ConditionExpr)
+}
+
+func (visitor *LintVisitor) VisitEnterForStmtNodeBeforeBlockNode(node *ast.ForStmtNode) {
+
+}
+
+func (visitor *LintVisitor) VisitLeaveForStmtNode(node *ast.ForStmtNode) {
+
+}
+
+// VisitEnterSwitchStmtNode reports empty case blocks, which fall through to the next case
+func (visitor *LintVisitor) VisitEnterSwitchStmtNode(node *ast.SwitchStmtNode) {
+	for i, caseExpr := range node.CaseExprs {
+		if node.IsEmptyCaseBlockAt(i) {
+			visitor.report(EMPTY_CASE, caseExpr.GetLocation(), "empty case falls through to the next case")
+		}
+	}
+}
+
+func (visitor *LintVisitor) VisitLeaveSwitchStmtNode(node *ast.SwitchStmtNode) {
+
+}
+
+func (visitor *LintVisitor) VisitBreakNode(node *ast.BreakNode) {
+
+}
+
+func (visitor *LintVisitor) VisitEnterPrintNode(node *ast.PrintNode) {
+
+}
+
+func (visitor *LintVisitor) VisitLeavePrintNode(node *ast.PrintNode) {
+
+}
+
+func (visitor *LintVisitor) VisitEnterExternNode(node *ast.ExternNode) {
+
+}
+
+func (visitor *LintVisitor) VisitLeaveExternNode(node *ast.ExternNode) {
+
+}
+
+// exprs
+
+func (visitor *LintVisitor) VisitEnterCallNode(node *ast.CallNode) {
+
+}
+
+func (visitor *LintVisitor) VisitLeaveCallNode(node *ast.CallNode) {
+
+}
+
+func (visitor *LintVisitor) VisitEnterTernaryOperatorNode(node *ast.TernaryOperatorNode) {
+	visitor.checkCondition(node.Expr1)
+}
+
+func (visitor *LintVisitor) VisitLeaveTernaryOperatorNode(node *ast.TernaryOperatorNode) {
+
+}
+
+func (visitor *LintVisitor) VisitEnterBinaryOepratorNode(node *ast.BinaryOperatorNode) {
+
+}
+
+func (visitor *LintVisitor) VisitLeaveBinaryOperatorNode(node *ast.BinaryOperatorNode) {
+
+}
+
+func (visitor *LintVisitor) VisitEnterUnaryOperatorNode(node *ast.UnaryOperatorNode) {
+
+}
+
+func (visitor *LintVisitor) VisitLeaveUnaryOperatorNode(node *ast.UnaryOperatorNode) {
+
+}
+
+// literal nodes
+
+func (visitor *LintVisitor) VisitIntegerNode(node *ast.IntegerNode) {
+
+}
+
+func (visitor *LintVisitor) VisitFloatNode(node *ast.FloatNode) {
+
+}
+
+func (visitor *LintVisitor) VisitBooleanNode(node *ast.BooleanNode) {
+
+}
+
+func (visitor *LintVisitor) VisitCharacterNode(node *ast.CharacterNode) {
+
+}
+
+func (visitor *LintVisitor) VisitStringNode(node *ast.StringNode) {
+
+}
+
+func (visitor *LintVisitor) VisitIdentifierNode(node *ast.IdentifierNode) {
+
+}
+
+func (visitor *LintVisitor) VisitTypeLiteralNode(node *ast.TypeLiteralNode) {
+
+}
+
+func (visitor *LintVisitor) VisitErrorNode(node *ast.ErrorNode) {
+
+}
+
+func (visitor *LintVisitor) checkCondition(conditionExpr ast.Node) {
+	value, ok := constantCondition(conditionExpr)
+
+	if !ok {
+		return
+	}
+
+	if value {
+		visitor.report(CONSTANT_CONDITION, conditionExpr.GetLocation(), "condition is always true")
+	} else {
+		visitor.report(CONSTANT_CONDITION, conditionExpr.GetLocation(), "condition is always false")
+	}
+}
+
+// constantCondition evaluates a boolean expression made of literals, returning false as its
+// second value if the expression is not constant
+func constantCondition(node ast.Node) (bool, bool) {
+	switch expr := node.(type) {
+	case *ast.BooleanNode:
+		return expr.Val, true
+	case *ast.UnaryOperatorNode:
+		if expr.Operator != signature.LOGIC_NOT {
+			return false, false
+		}
+
+		value, ok := constantCondition(expr.Expr)
+
+		return !value, ok
+	case *ast.BinaryOperatorNode:
+		lhs, lhsOk := constantCondition(expr.Lhs)
+		rhs, rhsOk := constantCondition(expr.Rhs)
+
+		switch expr.Operator {
+		case signature.LOGIC_AND:
+			if (lhsOk && !lhs) || (rhsOk && !rhs) {
+				return false, true
+			}
+
+			return true, lhsOk && rhsOk
+		case signature.LOGIC_OR:
+			if (lhsOk && lhs) || (rhsOk && rhs) {
+				return true, true
+			}
+
+			return false, lhsOk && rhsOk
+		}
+	}
+
+	return false, false
+}
+
+func (visitor *LintVisitor) report(lint Lint, location string, message string) {
+	message = message + " (" + lint.String() + ")"
+
+	switch visitor.levels[lint] {
+	case ALLOW:
+		return
+	case DENY:
+		visitor.logger.Log(location, message)
+	default:
+		if warningLogger, ok := visitor.logger.(logger.WarningLogger); ok {
+			warningLogger.Warn(location, message)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carlcui/expressive/internal/testutil"
+	"github.com/carlcui/expressive/logger"
+)
+
+const testDir = "./testFiles"
+
+// countLint counts the messages reporting lint
+func countLint(messages []string, lint Lint) int {
+	count := 0
+
+	for _, message := range messages {
+		if strings.HasSuffix(message, "("+lint.String()+")") {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestLints(t *testing.T) {
+	expectedCounts := map[Lint]int{
+		UNUSED_VARIABLE:    3,
+		UNUSED_CONST:       1,
+		SHADOWING:          2,
+		DEAD_CODE:          1,
+		EMPTY_CASE:         2,
+		CONSTANT_CONDITION: 4,
+	}
+
+	for lint, expectedCount := range expectedCounts {
+		fileName := lint.String() + ".exp"
+
+		var buffer logger.Buffer
+		var levels Levels
+
+		Check(testutil.AnalyzeFile(testDir, fileName, t), &levels, &buffer)
+
+		if count := countLint(buffer.Messages(), lint); count != expectedCount {
+			t.Errorf("File %v: expecting %v %v warning(s), got %v: %v", fileName, expectedCount, lint, count, buffer.Messages())
+		}
+
+		if buffer.ErrorsCount() > 0 {
+			t.Errorf("File %v: expecting warnings not to count as errors", fileName)
+		}
+	}
+}
+
+func TestCleanProgramHasNoLint(t *testing.T) {
+	var buffer logger.Buffer
+	var levels Levels
+
+	Check(testutil.AnalyzeFile(testDir, "clean.exp", t), &levels, &buffer)
+
+	if len(buffer.Messages()) > 0 {
+		t.Errorf("Expecting no lint, got %v", buffer.Messages())
+	}
+}
+
+func TestAllowAndDeny(t *testing.T) {
+	var levels Levels
+
+	if err := levels.Set("all", ALLOW); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := levels.Set("unused-variable", DENY); err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer logger.Buffer
+
+	Check(testutil.AnalyzeFile(testDir, "unused-variable.exp", t), &levels, &buffer)
+
+	if buffer.ErrorsCount() != 3 {
+		t.Errorf("Expecting denied lints to be errors, got %v", buffer.Messages())
+	}
+
+	buffer = logger.Buffer{}
+
+	Check(testutil.AnalyzeFile(testDir, "constant-condition.exp", t), &levels, &buffer)
+
+	if len(buffer.Messages()) > 0 {
+		t.Errorf("Expecting allowed lints not to be reported, got %v", buffer.Messages())
+	}
+}
+
+func TestUnknownLint(t *testing.T) {
+	var levels Levels
+
+	if err := levels.Set("unused-variable,unknown", DENY); err == nil {
+		t.Error("Expecting error setting the level of an unknown lint")
+	}
+}
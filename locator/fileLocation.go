@@ -2,6 +2,7 @@ package locator
 
 import (
 	"path"
+	"regexp"
 	"strconv"
 )
 
@@ -16,3 +17,22 @@ func (loc *FileLocation) Locate() string {
 	return "file " + path.Join(loc.DirName, loc.FileName) +
 		": row " + strconv.Itoa(loc.Row) + ", column " + strconv.Itoa(loc.Col)
 }
+
+var fileLocationPattern = regexp.MustCompile(`^file (.*): row (\d+), column (\d+)$`)
+
+// ParseFileLocation reads back a location written by Locate, e.g. one logged with a message. The
+// path of the file is returned as FileName.
+func ParseFileLocation(location string) (*FileLocation, bool) {
+	match := fileLocationPattern.FindStringSubmatch(location)
+
+	if match == nil {
+		return nil, false
+	}
+
+	var loc FileLocation
+	loc.FileName = match[1]
+	loc.Row, _ = strconv.Atoi(match[2])
+	loc.Col, _ = strconv.Atoi(match[3])
+
+	return &loc, true
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+)
+
+// Buffer keeps logged messages in memory instead of printing them right away, so that
+// diagnostics of compilations running concurrently do not interleave.
+type Buffer struct {
+	messages   []string
+	errorCount int
+}
+
+func (buffer *Buffer) Log(location string, message string) {
+	buffer.errorCount++
+	buffer.messages = append(buffer.messages, location+": "+message)
+}
+
+func (buffer *Buffer) Warn(location string, message string) {
+	buffer.messages = append(buffer.messages, location+": warning: "+message)
+}
+
+func (buffer *Buffer) ErrorsCount() int {
+	return buffer.errorCount
+}
+
+// Messages returns logged errors and warnings in the order they were logged
+func (buffer *Buffer) Messages() []string {
+	return buffer.messages
+}
+
+// Flush writes all logged messages to writer, one per line
+func (buffer *Buffer) Flush(writer io.Writer) {
+	for _, message := range buffer.messages {
+		fmt.Fprintln(writer, message)
+	}
+}
@@ -4,3 +4,9 @@ type Logger interface {
 	Log(location string, message string)
 	ErrorsCount() int
 }
+
+// WarningLogger is a Logger that can also report warnings. Warnings are not counted as errors.
+type WarningLogger interface {
+	Logger
+	Warn(location string, message string)
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"os"
 )
 
 type StdError struct {
@@ -10,7 +11,11 @@ type StdError struct {
 
 func (stdError *StdError) Log(location string, message string) {
 	stdError.errorCount++
-	fmt.Println(location + ": " + message)
+	fmt.Fprintln(os.Stderr, location+": "+message)
+}
+
+func (stdError *StdError) Warn(location string, message string) {
+	fmt.Fprintln(os.Stderr, location+": warning: "+message)
 }
 
 func (stdError *StdError) ErrorsCount() int {
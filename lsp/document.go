@@ -0,0 +1,329 @@
+package lsp
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/cst"
+	"github.com/carlcui/expressive/input"
+	"github.com/carlcui/expressive/internal/frontend"
+	"github.com/carlcui/expressive/locator"
+	"github.com/carlcui/expressive/semanticAnalyser"
+	"github.com/carlcui/expressive/symbolTable"
+	"github.com/carlcui/expressive/token"
+)
+
+// document is a source open in the editor, analysed each time it changes
+type document struct {
+	uri         string
+	diagnostics []Diagnostic
+
+	analysis *analysis // nil if the source has syntax errors
+
+	// the last analysis of the document without syntax errors, which completion falls back to
+	// while the source is being edited
+	lastAnalysis *analysis
+}
+
+// analysis is the cst of a source analysed without syntax errors
+type analysis struct {
+	lines []string
+	root  *cst.Node
+}
+
+// update replaces the source of the document, and analyses it
+func (doc *document) update(text string) {
+	lines := strings.Split(text, "\n")
+
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	var reader input.Reader
+	var diagnostics diagnosticLogger
+
+	reader.Init(strings.NewReader(text), doc.uri, &diagnostics)
+
+	current := &analysis{lines, frontend.ParseCST(&reader, &diagnostics)}
+
+	doc.analysis = nil
+
+	if diagnostics.ErrorsCount() == 0 {
+		semanticAnalyser.Analyze(current.root.Syntax, &diagnostics)
+
+		doc.analysis = current
+		doc.lastAnalysis = current
+	}
+
+	doc.diagnostics = make([]Diagnostic, 0, len(diagnostics.logged))
+
+	for _, logged := range diagnostics.logged {
+		doc.diagnostics = append(doc.diagnostics, Diagnostic{
+			Range:    current.tokenRange(logged.row, logged.col),
+			Severity: logged.severity,
+			Source:   "expressive",
+			Message:  logged.message,
+		})
+	}
+}
+
+// tokenRange returns the range of the token at row and col in root, or an empty range there if
+// no token starts there
+func (analysis *analysis) tokenRange(row int, col int) Range {
+	var found *token.Token
+
+	analysis.root.Walk(func(leaf *cst.Node) {
+		if loc, ok := leaf.Token.Locator.(*locator.FileLocation); ok && loc.Row == row && loc.Col == col && found == nil {
+			found = leaf.Token
+		}
+	})
+
+	if found == nil || found.TokenType == token.EOF {
+		start := analysis.position(row, col)
+		return Range{start, start}
+	}
+
+	return analysis.rangeOf(found)
+}
+
+// position converts a location of the scanner, whose columns count runes, to a position
+func (analysis *analysis) position(row int, col int) Position {
+	return Position{Line: row, Character: utf16Column(lineAt(analysis.lines, row), col)}
+}
+
+// rangeOf returns the range of the text of tok, without its trivia
+func (analysis *analysis) rangeOf(tok *token.Token) Range {
+	loc, ok := tok.Locator.(*locator.FileLocation)
+
+	if !ok {
+		return Range{}
+	}
+
+	return Range{
+		Start: analysis.position(loc.Row, loc.Col),
+		End:   analysis.position(loc.Row, loc.Col+utf8.RuneCountInString(tok.Raw)),
+	}
+}
+
+// branchRange returns the range from the first token of branch to the end of its last one
+func (analysis *analysis) branchRange(branch *cst.Node) Range {
+	tokens := branch.Tokens()
+
+	if len(tokens) == 0 {
+		return Range{}
+	}
+
+	return Range{analysis.rangeOf(tokens[0]).Start, analysis.rangeOf(tokens[len(tokens)-1]).End}
+}
+
+// path returns the branches of root from root down to the one holding the token at pos,
+// followed by the leaf of that token. A position at the end of a token, e.g. right after an
+// identifier, is on that token. It returns nil if no token is at pos.
+func (analysis *analysis) path(root *cst.Node, pos Position) []*cst.Node {
+	if root.IsLeaf() {
+		tokenRange := analysis.rangeOf(root.Token)
+
+		if root.Token.TokenType == token.EOF || !contains(tokenRange, pos) {
+			return nil
+		}
+
+		return []*cst.Node{root}
+	}
+
+	for _, child := range root.Children {
+		if found := analysis.path(child, pos); found != nil {
+			return append([]*cst.Node{root}, found...)
+		}
+	}
+
+	return nil
+}
+
+// identifierAt returns the identifier at pos bound by semantic analysis, and the leaf of its
+// token
+func (analysis *analysis) identifierAt(pos Position) (*ast.IdentifierNode, *cst.Node) {
+	path := analysis.path(analysis.root, pos)
+
+	if len(path) < 2 {
+		return nil, nil
+	}
+
+	identifier, ok := path[len(path)-2].Syntax.(*ast.IdentifierNode)
+
+	if !ok || identifier.GetBinding() == nil {
+		return nil, nil
+	}
+
+	return identifier, path[len(path)-1]
+}
+
+// identifiers returns the identifiers of the source in source order
+func (analysis *analysis) identifiers() []*ast.IdentifierNode {
+	identifiers := make([]*ast.IdentifierNode, 0)
+
+	var visit func(node *cst.Node)
+
+	visit = func(node *cst.Node) {
+		if identifier, ok := node.Syntax.(*ast.IdentifierNode); ok {
+			identifiers = append(identifiers, identifier)
+			return
+		}
+
+		for _, child := range node.Children {
+			visit(child)
+		}
+	}
+
+	visit(analysis.root)
+
+	return identifiers
+}
+
+// scopeAt returns the innermost scope of the branches of node spanning pos, or nil if none does
+func (analysis *analysis) scopeAt(node *cst.Node, pos Position) *symbolTable.Scope {
+	if node.IsLeaf() || !contains(analysis.branchRange(node), pos) && node != analysis.root {
+		return nil
+	}
+
+	for _, child := range node.Children {
+		if scope := analysis.scopeAt(child, pos); scope != nil {
+			return scope
+		}
+	}
+
+	return node.Syntax.GetScope()
+}
+
+// references returns the identifiers bound like identifier, its declaration included
+func (analysis *analysis) references(identifier *ast.IdentifierNode) []*ast.IdentifierNode {
+	references := make([]*ast.IdentifierNode, 0)
+
+	for _, other := range analysis.identifiers() {
+		if other.GetBinding() == identifier.GetBinding() {
+			references = append(references, other)
+		}
+	}
+
+	return references
+}
+
+// renameConflicts tells whether renaming identifier to name would change what a reference refers
+// to, or declare name twice:
+//   - name is bound in a scope from the scope of a reference of identifier up to the scope of its
+//     declaration, so that the reference would refer to that binding
+//   - a reference to another binding named name is in the scope of the declaration, below the
+//     scope binding it, so that the reference would refer to the renamed binding
+//   - name is bound above the scope of the declaration by a binding that cannot be shadowed
+func (analysis *analysis) renameConflicts(identifier *ast.IdentifierNode, name string) bool {
+	binding := identifier.GetBinding()
+	var declarationScope *symbolTable.Scope
+
+	for _, reference := range analysis.references(identifier) {
+		if reference.IsBeingDeclared() {
+			declarationScope = analysis.scopeAt(analysis.root, analysis.rangeOf(reference.Tok).Start)
+		}
+	}
+
+	if declarationScope == nil {
+		return false
+	}
+
+	for _, other := range analysis.identifiers() {
+		otherBinding := other.GetBinding()
+
+		if otherBinding == nil || otherBinding != binding && other.Tok.Raw != name {
+			continue
+		}
+
+		for scope := analysis.scopeAt(analysis.root, analysis.rangeOf(other.Tok).Start); scope != nil; scope = scope.BaseScope {
+			found := scope.FindBinding(name)
+
+			if otherBinding == binding && found != nil && found != binding {
+				return true
+			}
+
+			if otherBinding != binding && found == otherBinding {
+				break
+			}
+
+			if scope == declarationScope {
+				if otherBinding != binding {
+					return true
+				}
+
+				break
+			}
+		}
+	}
+
+	return declarationScope.BaseScope != nil && !declarationScope.BaseScope.VariableCanBeShadowed(name)
+}
+
+func contains(r Range, pos Position) bool {
+	return !before(pos, r.Start) && !before(r.End, pos)
+}
+
+// before tells whether a comes before b
+func before(a Position, b Position) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Character < b.Character
+}
+
+func lineAt(lines []string, row int) string {
+	if row < 0 || row >= len(lines) {
+		return ""
+	}
+
+	return lines[row]
+}
+
+// utf16Column converts a column counted in runes to UTF-16 code units
+func utf16Column(line string, col int) int {
+	runes := []rune(line)
+
+	if col > len(runes) {
+		col = len(runes)
+	}
+
+	return len(utf16.Encode(runes[:col]))
+}
+
+// diagnosticLogger is a logger.WarningLogger keeping what the frontend logs, with the row and
+// the column of its location
+type diagnosticLogger struct {
+	logged     []loggedDiagnostic
+	errorCount int
+}
+
+type loggedDiagnostic struct {
+	row, col int
+	severity int
+	message  string
+}
+
+func (diagnostics *diagnosticLogger) Log(location string, message string) {
+	diagnostics.errorCount++
+	diagnostics.add(location, message, severityError)
+}
+
+func (diagnostics *diagnosticLogger) Warn(location string, message string) {
+	diagnostics.add(location, message, severityWarning)
+}
+
+func (diagnostics *diagnosticLogger) ErrorsCount() int {
+	return diagnostics.errorCount
+}
+
+// add records message at the row and column of location, or at the start of the document if
+// location has none
+func (diagnostics *diagnosticLogger) add(location string, message string, severity int) {
+	logged := loggedDiagnostic{severity: severity, message: message}
+
+	if loc, ok := locator.ParseFileLocation(location); ok {
+		logged.row = loc.Row
+		logged.col = loc.Col
+	}
+
+	diagnostics.logged = append(diagnostics.logged, logged)
+}
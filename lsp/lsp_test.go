@@ -0,0 +1,461 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+const uri = "file:///program.exp"
+
+const program = `let a: int = 1;
+const b = "hi";
+
+if (a > 0) {
+    let c = a + 2;
+    print "%d\n", c;
+}
+
+a += 1;
+`
+
+// session sends requests and notifications to a server, and returns what it writes back
+type session struct {
+	input  bytes.Buffer
+	nextID int
+}
+
+func (session *session) send(value interface{}) {
+	if err := writeMessage(&session.input, value); err != nil {
+		panic(err)
+	}
+}
+
+func (session *session) request(method string, params interface{}) int {
+	session.nextID++
+	session.send(map[string]interface{}{"jsonrpc": "2.0", "id": session.nextID, "method": method, "params": params})
+
+	return session.nextID
+}
+
+func (session *session) notify(method string, params interface{}) {
+	session.send(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (session *session) open(text string) {
+	session.request("initialize", map[string]interface{}{})
+	session.notify("initialized", map[string]interface{}{})
+	session.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "expressive", "version": 1, "text": text},
+	})
+}
+
+type reply struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *responseError  `json:"error"`
+}
+
+// run serves the session until its end, and returns the messages of the server
+func (session *session) run(t *testing.T) []reply {
+	session.request("shutdown", nil)
+	session.notify("exit", nil)
+
+	var output bytes.Buffer
+
+	if err := NewServer(&session.input, &output).Serve(); err != nil {
+		t.Fatal(err)
+	}
+
+	replies := make([]reply, 0)
+	reader := bufio.NewReader(&output)
+
+	for {
+		content, err := readMessage(reader)
+
+		if err == io.EOF {
+			return replies
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var r reply
+
+		if err := json.Unmarshal(content, &r); err != nil {
+			t.Fatal(err)
+		}
+
+		replies = append(replies, r)
+	}
+}
+
+func find(replies []reply, id int, t *testing.T) reply {
+	for _, r := range replies {
+		if r.ID != nil && *r.ID == id {
+			return r
+		}
+	}
+
+	t.Fatalf("Expecting a reply to request %v", id)
+
+	return reply{}
+}
+
+func position(line int, character int) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"position":     map[string]interface{}{"line": line, "character": character},
+	}
+}
+
+// result decodes the result of request id into value
+func result(replies []reply, id int, value interface{}, t *testing.T) {
+	r := find(replies, id, t)
+
+	if r.Error != nil {
+		t.Fatalf("Unexpected error %v", r.Error.Message)
+	}
+
+	if err := json.Unmarshal(r.Result, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func diagnostics(replies []reply, t *testing.T) [][]Diagnostic {
+	published := make([][]Diagnostic, 0)
+
+	for _, r := range replies {
+		if r.Method == "textDocument/publishDiagnostics" {
+			var params publishDiagnosticsParams
+
+			if err := json.Unmarshal(r.Params, &params); err != nil {
+				t.Fatal(err)
+			}
+
+			published = append(published, params.Diagnostics)
+		}
+	}
+
+	return published
+}
+
+func TestDiagnosticsArePublishedOnChange(t *testing.T) {
+	var session session
+	session.open("let a = 1;\nlet b: int = \"x\";\n")
+	session.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri, "version": 2},
+		"contentChanges": []interface{}{map[string]interface{}{"text": "let a = 1;\nprint a\n"}},
+	})
+	session.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri, "version": 3},
+		"contentChanges": []interface{}{map[string]interface{}{"text": program}},
+	})
+
+	published := diagnostics(session.run(t), t)
+
+	if len(published) != 3 {
+		t.Fatalf("Expecting diagnostics published on open and on each change, got %v", published)
+	}
+
+	semantic := published[0]
+
+	if len(semantic) != 1 || semantic[0].Severity != severityError || semantic[0].Range.Start != (Position{1, 0}) {
+		t.Errorf("Expecting the type error of the declaration of b, got %v", semantic)
+	}
+
+	syntax := published[1]
+
+	if len(syntax) == 0 || syntax[0].Range.Start.Line != 2 {
+		t.Errorf("Expecting a syntax error for the missing semicolon, got %v", syntax)
+	}
+
+	if len(published[2]) != 0 {
+		t.Errorf("Expecting no diagnostics once fixed, got %v", published[2])
+	}
+}
+
+func TestHoverShowsTyping(t *testing.T) {
+	var session session
+	session.open(program)
+	variable := session.request("textDocument/hover", position(4, 9))
+	constant := session.request("textDocument/hover", position(1, 6))
+	expression := session.request("textDocument/hover", position(4, 15))
+	nothing := session.request("textDocument/hover", position(3, 30))
+	statement := session.request("textDocument/hover", position(8, 3))
+
+	replies := session.run(t)
+
+	tests := []struct {
+		id       int
+		expected string
+	}{
+		{variable, "```expressive\nlet c: INT\n```"},
+		{constant, "```expressive\nconst b: STRING\n```"},
+		{expression, "```expressive\nINT\n```"},
+	}
+
+	for _, test := range tests {
+		var hover Hover
+		result(replies, test.id, &hover, t)
+
+		if hover.Contents.Value != test.expected {
+			t.Errorf("Expecting hover %q, got %q", test.expected, hover.Contents.Value)
+		}
+	}
+
+	for _, id := range []int{nothing, statement} {
+		if r := find(replies, id, t); string(r.Result) != "null" {
+			t.Errorf("Expecting no hover past the end of a line or on a statement, got %s", r.Result)
+		}
+	}
+}
+
+func TestDefinitionAndReferences(t *testing.T) {
+	var session session
+	session.open(program)
+	definition := session.request("textDocument/definition", position(5, 18))
+	all := session.request("textDocument/references", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"position":     map[string]interface{}{"line": 8, "character": 0},
+		"context":      map[string]interface{}{"includeDeclaration": true},
+	})
+	uses := session.request("textDocument/references", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"position":     map[string]interface{}{"line": 0, "character": 4},
+		"context":      map[string]interface{}{"includeDeclaration": false},
+	})
+
+	replies := session.run(t)
+
+	var location Location
+	result(replies, definition, &location, t)
+
+	if location.URI != uri || location.Range != (Range{Position{4, 8}, Position{4, 9}}) {
+		t.Errorf("Expecting c to be declared at 4:8, got %v", location)
+	}
+
+	var locations []Location
+	result(replies, all, &locations, t)
+
+	if fmt.Sprint(locations) != fmt.Sprintf("[{%v {{0 4} {0 5}}} {%v {{3 4} {3 5}}} {%v {{4 12} {4 13}}} {%v {{8 0} {8 1}}}]", uri, uri, uri, uri) {
+		t.Errorf("Expecting the references of a, got %v", locations)
+	}
+
+	result(replies, uses, &locations, t)
+
+	if len(locations) != 3 {
+		t.Errorf("Expecting the uses of a without its declaration, got %v", locations)
+	}
+}
+
+func TestRename(t *testing.T) {
+	var session session
+	session.open(program)
+	rename := session.request("textDocument/rename", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"position":     map[string]interface{}{"line": 4, "character": 8},
+		"newName":      "sum",
+	})
+	invalid := session.request("textDocument/rename", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"position":     map[string]interface{}{"line": 4, "character": 8},
+		"newName":      "while",
+	})
+
+	replies := session.run(t)
+
+	var edit WorkspaceEdit
+	result(replies, rename, &edit, t)
+
+	expected := []TextEdit{{Range{Position{4, 8}, Position{4, 9}}, "sum"}, {Range{Position{5, 18}, Position{5, 19}}, "sum"}}
+
+	if fmt.Sprint(edit.Changes[uri]) != fmt.Sprint(expected) {
+		t.Errorf("Expecting edits %v, got %v", expected, edit.Changes)
+	}
+
+	if r := find(replies, invalid, t); r.Error == nil || r.Error.Code != invalidParams {
+		t.Errorf("Expecting renaming to a keyword to fail, got %s", r.Result)
+	}
+}
+
+func TestRenameConflicts(t *testing.T) {
+	tests := []struct {
+		src      string
+		line     int
+		newName  string
+		conflict bool
+	}{
+		// b is bound in the scope of the declaration
+		{"let b = 1;\nlet é = 2;\nprint \"%d\", b + é;\n", 1, "b", true},
+		// é would capture the use of the outer b
+		{"let b = 1;\nif (true) {\n    let é = 2;\n    print \"%d\", b + é;\n}\n", 2, "b", true},
+		// the use of é would refer to the inner b
+		{"let é = 1;\nif (true) {\n    let b = 2;\n    print \"%d\", b + é;\n}\n", 0, "b", true},
+		// the inner b is not used within the scope of é, and shadows it
+		{"let é = 1;\nprint \"%d\", é;\nif (true) {\n    let b = 2;\n    print \"%d\", b;\n}\n", 0, "b", false},
+		// b is declared after the block of é, and not used in it
+		{"if (true) {\n    let é = 2;\n    print \"%d\", é;\n}\nlet b = 1;\nprint \"%d\", b;\n", 1, "b", false},
+		// puts cannot be shadowed
+		{"extern func puts(s: string) -> int;\nif (true) {\n    let é = \"x\";\n    puts(é);\n}\n", 2, "puts", true},
+	}
+
+	for _, test := range tests {
+		var session session
+		session.open(test.src)
+
+		line := strings.Split(test.src, "\n")[test.line]
+		character := len(utf16.Encode([]rune(line[:strings.Index(line, "é")])))
+
+		id := session.request("textDocument/rename", map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri},
+			"position":     map[string]interface{}{"line": test.line, "character": character},
+			"newName":      test.newName,
+		})
+
+		r := find(session.run(t), id, t)
+
+		if conflict := r.Error != nil && r.Error.Code == invalidParams; conflict != test.conflict {
+			t.Errorf("Expecting renaming é to %v in\n%v\nto conflict: %v, got %s and %v", test.newName, test.src, test.conflict, r.Result, r.Error)
+		}
+	}
+}
+
+func TestDocumentSymbols(t *testing.T) {
+	var session session
+	session.open(program)
+	id := session.request("textDocument/documentSymbol", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+	})
+
+	var symbols []DocumentSymbol
+	result(session.run(t), id, &symbols, t)
+
+	expected := []DocumentSymbol{
+		{"a", "INT", symbolKindVariable, Range{Position{0, 0}, Position{0, 15}}, Range{Position{0, 4}, Position{0, 5}}},
+		{"b", "STRING", symbolKindConstant, Range{Position{1, 0}, Position{1, 15}}, Range{Position{1, 6}, Position{1, 7}}},
+		{"c", "INT", symbolKindVariable, Range{Position{4, 4}, Position{4, 18}}, Range{Position{4, 8}, Position{4, 9}}},
+	}
+
+	if fmt.Sprint(symbols) != fmt.Sprint(expected) {
+		t.Errorf("Expecting symbols\n%v\ngot\n%v", expected, symbols)
+	}
+}
+
+func TestExternFunctions(t *testing.T) {
+	var session session
+	session.open("extern func puts(s: string) -> int;\nputs(\"hi\");\n")
+	hover := session.request("textDocument/hover", position(1, 1))
+	symbols := session.request("textDocument/documentSymbol", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+	})
+	completion := session.request("textDocument/completion", position(2, 0))
+
+	replies := session.run(t)
+
+	var hoverResult Hover
+	result(replies, hover, &hoverResult, t)
+
+	if expected := "```expressive\nextern func puts: FUNC(STRING) -> INT\n```"; hoverResult.Contents.Value != expected {
+		t.Errorf("Expecting hover %q, got %q", expected, hoverResult.Contents.Value)
+	}
+
+	var symbolsResult []DocumentSymbol
+	result(replies, symbols, &symbolsResult, t)
+
+	if len(symbolsResult) != 1 || symbolsResult[0].Name != "puts" || symbolsResult[0].Kind != symbolKindFunction {
+		t.Errorf("Expecting the function puts, got %v", symbolsResult)
+	}
+
+	var items []CompletionItem
+	result(replies, completion, &items, t)
+
+	functions := 0
+
+	for _, item := range items {
+		if item.Label == "puts" && item.Kind == completionKindFunction {
+			functions++
+		}
+	}
+
+	if functions != 1 {
+		t.Errorf("Expecting puts to be proposed as a function, got %v", items)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	var session session
+	session.open(program)
+	inBlock := session.request("textDocument/completion", position(5, 4))
+	atStart := session.request("textDocument/completion", position(0, 0))
+
+	// while a statement is typed, variables come from the last analysis without syntax errors
+	session.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri, "version": 2},
+		"contentChanges": []interface{}{map[string]interface{}{"text": program + "print "}},
+	})
+	editing := session.request("textDocument/completion", position(9, 6))
+
+	replies := session.run(t)
+
+	tests := []struct {
+		id       int
+		expected string
+	}{
+		{inBlock, "[c a b]"},
+		{atStart, "[]"},
+		{editing, "[a b]"},
+	}
+
+	for _, test := range tests {
+		var items []CompletionItem
+		result(replies, test.id, &items, t)
+
+		variables := make([]string, 0)
+		keywords := 0
+
+		for _, item := range items {
+			if item.Kind == completionKindKeyword {
+				keywords++
+			} else {
+				variables = append(variables, item.Label)
+			}
+		}
+
+		if fmt.Sprint(variables) != test.expected || keywords == 0 {
+			t.Errorf("Expecting keywords and variables %v, got %v", test.expected, items)
+		}
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	var session session
+	early := session.request("textDocument/hover", position(0, 0))
+	session.request("initialize", map[string]interface{}{})
+	unknown := session.request("workspace/symbol", map[string]interface{}{})
+
+	replies := session.run(t)
+
+	if r := find(replies, early, t); r.Error == nil || r.Error.Code != serverNotInitialized {
+		t.Errorf("Expecting a request before initialize to fail, got %s", r.Result)
+	}
+
+	if r := find(replies, unknown, t); r.Error == nil || r.Error.Code != methodNotFound {
+		t.Errorf("Expecting an unsupported method to fail, got %s", r.Result)
+	}
+
+	var input bytes.Buffer
+	writeMessage(&input, map[string]interface{}{"jsonrpc": "2.0", "method": "exit"})
+
+	if err := NewServer(&input, &bytes.Buffer{}).Serve(); err != ErrExitWithoutShutdown {
+		t.Errorf("Expecting exit without shutdown to fail, got %v", err)
+	}
+}
@@ -0,0 +1,488 @@
+// Package lsp serves the Language Server Protocol over JSON-RPC, so that editors report the
+// errors of expressive sources as they are edited, show the types of expressions, and find,
+// rename and complete variables. Open documents are analysed by the frontend each time they
+// change, and every request is answered from their latest analysis.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/input"
+	"github.com/carlcui/expressive/locator"
+	"github.com/carlcui/expressive/scanner"
+	"github.com/carlcui/expressive/symbolTable"
+	"github.com/carlcui/expressive/token"
+	"github.com/carlcui/expressive/typing"
+)
+
+// ErrExitWithoutShutdown is returned by Serve when the client asks the server to exit before
+// asking it to shut down
+var ErrExitWithoutShutdown = errors.New("exit notification received before shutdown request")
+
+// Server answers the requests of a client
+type Server struct {
+	reader *bufio.Reader
+	writer io.Writer
+
+	initialized bool
+	shutdown    bool
+	documents   map[string]*document
+}
+
+// NewServer creates a server reading messages from reader and writing to writer, e.g. stdin and
+// stdout
+func NewServer(reader io.Reader, writer io.Writer) *Server {
+	return &Server{
+		reader:    bufio.NewReader(reader),
+		writer:    writer,
+		documents: make(map[string]*document),
+	}
+}
+
+// Serve handles messages until the client asks the server to exit. It returns
+// ErrExitWithoutShutdown if the client did not shut the server down first, or the error reading
+// or writing a message.
+func (server *Server) Serve() error {
+	for {
+		content, err := readMessage(server.reader)
+
+		if err != nil {
+			return err
+		}
+
+		var msg message
+
+		if err := json.Unmarshal(content, &msg); err != nil {
+			if err := server.replyError(nil, &responseError{parseError, err.Error()}); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if msg.Method == "exit" {
+			if !server.shutdown {
+				return ErrExitWithoutShutdown
+			}
+
+			return nil
+		}
+
+		if err := server.handle(&msg); err != nil {
+			return err
+		}
+	}
+}
+
+// handle answers a request, or handles a notification
+func (server *Server) handle(msg *message) error {
+	if msg.ID == nil {
+		return server.notified(msg)
+	}
+
+	result, err := server.request(msg)
+
+	if err != nil {
+		return server.replyError(msg.ID, err)
+	}
+
+	return writeMessage(server.writer, response{"2.0", msg.ID, result})
+}
+
+func (server *Server) replyError(id *json.RawMessage, err *responseError) error {
+	return writeMessage(server.writer, errorResponse{"2.0", id, err})
+}
+
+func (server *Server) request(msg *message) (interface{}, *responseError) {
+	switch {
+	case msg.Method == "initialize":
+		server.initialized = true
+		return server.capabilities(), nil
+	case !server.initialized:
+		return nil, &responseError{serverNotInitialized, "the server is not initialized"}
+	case server.shutdown:
+		return nil, &responseError{invalidRequest, "the server is shut down"}
+	case msg.Method == "shutdown":
+		server.shutdown = true
+		return nil, nil
+	}
+
+	switch msg.Method {
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		return server.withParams(msg, &params, func() (interface{}, *responseError) {
+			return server.hover(&params), nil
+		})
+	case "textDocument/definition":
+		var params textDocumentPositionParams
+		return server.withParams(msg, &params, func() (interface{}, *responseError) {
+			return server.definition(&params), nil
+		})
+	case "textDocument/references":
+		var params referenceParams
+		return server.withParams(msg, &params, func() (interface{}, *responseError) {
+			return server.references(&params), nil
+		})
+	case "textDocument/rename":
+		var params renameParams
+		return server.withParams(msg, &params, func() (interface{}, *responseError) {
+			return server.rename(&params)
+		})
+	case "textDocument/documentSymbol":
+		var params documentSymbolParams
+		return server.withParams(msg, &params, func() (interface{}, *responseError) {
+			return server.documentSymbols(&params), nil
+		})
+	case "textDocument/completion":
+		var params textDocumentPositionParams
+		return server.withParams(msg, &params, func() (interface{}, *responseError) {
+			return server.completion(&params), nil
+		})
+	}
+
+	return nil, &responseError{methodNotFound, "unsupported method " + msg.Method}
+}
+
+// withParams decodes the params of msg into params, then answers with answer
+func (server *Server) withParams(msg *message, params interface{}, answer func() (interface{}, *responseError)) (interface{}, *responseError) {
+	if err := json.Unmarshal(msg.Params, params); err != nil {
+		return nil, &responseError{invalidParams, err.Error()}
+	}
+
+	return answer()
+}
+
+func (server *Server) capabilities() interface{} {
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"textDocumentSync":       textDocumentSyncFull,
+			"hoverProvider":          true,
+			"definitionProvider":     true,
+			"referencesProvider":     true,
+			"renameProvider":         true,
+			"documentSymbolProvider": true,
+			"completionProvider":     map[string]interface{}{},
+		},
+		"serverInfo": map[string]interface{}{"name": "expressive"},
+	}
+}
+
+// notified handles a notification. Unknown notifications are ignored, as is any notification
+// before the server is initialized.
+func (server *Server) notified(msg *message) error {
+	if !server.initialized {
+		return nil
+	}
+
+	switch msg.Method {
+	case "textDocument/didOpen":
+		var params didOpenParams
+
+		if json.Unmarshal(msg.Params, &params) != nil {
+			return nil
+		}
+
+		return server.update(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params didChangeParams
+
+		if json.Unmarshal(msg.Params, &params) != nil || len(params.ContentChanges) == 0 {
+			return nil
+		}
+
+		// the server syncs full documents, so the last change holds the whole text
+		return server.update(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+	case "textDocument/didClose":
+		var params didCloseParams
+
+		if json.Unmarshal(msg.Params, &params) != nil {
+			return nil
+		}
+
+		delete(server.documents, params.TextDocument.URI)
+
+		return server.publishDiagnostics(params.TextDocument.URI, make([]Diagnostic, 0))
+	}
+
+	return nil
+}
+
+// update analyses the text of a document, and publishes its diagnostics
+func (server *Server) update(uri string, text string) error {
+	doc, ok := server.documents[uri]
+
+	if !ok {
+		doc = &document{uri: uri}
+		server.documents[uri] = doc
+	}
+
+	doc.update(text)
+
+	return server.publishDiagnostics(uri, doc.diagnostics)
+}
+
+func (server *Server) publishDiagnostics(uri string, diagnostics []Diagnostic) error {
+	return writeMessage(server.writer, notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  publishDiagnosticsParams{uri, diagnostics},
+	})
+}
+
+// analysis returns the analysis of an open document without syntax errors, or nil
+func (server *Server) analysis(uri string) *analysis {
+	doc, ok := server.documents[uri]
+
+	if !ok {
+		return nil
+	}
+
+	return doc.analysis
+}
+
+// hover shows the typing of the innermost node at the position, with the name of a variable
+func (server *Server) hover(params *textDocumentPositionParams) interface{} {
+	analysis := server.analysis(params.TextDocument.URI)
+
+	if analysis == nil {
+		return nil
+	}
+
+	path := analysis.path(analysis.root, params.Position)
+
+	for i := len(path) - 2; i >= 0; i-- {
+		node := path[i].Syntax
+		nodeTyping := node.GetTyping()
+
+		// statements have no value to show
+		if nodeTyping == nil || nodeTyping.Equals(typing.VOID) {
+			continue
+		}
+
+		text := nodeTyping.String()
+
+		if identifier, ok := node.(*ast.IdentifierNode); ok {
+			text = declarationKeyword(identifier) + " " + identifier.Tok.Raw + ": " + text
+		}
+
+		return Hover{
+			Contents: MarkupContent{"markdown", "```expressive\n" + text + "\n```"},
+			Range:    analysis.branchRange(path[i]),
+		}
+	}
+
+	return nil
+}
+
+// definition locates the declaration of the variable at the position
+func (server *Server) definition(params *textDocumentPositionParams) interface{} {
+	analysis := server.analysis(params.TextDocument.URI)
+
+	if analysis == nil {
+		return nil
+	}
+
+	identifier, _ := analysis.identifierAt(params.Position)
+
+	if identifier == nil {
+		return nil
+	}
+
+	loc, ok := identifier.GetBinding().GetLocator().(*locator.FileLocation)
+
+	if !ok {
+		return nil
+	}
+
+	start := analysis.position(loc.Row, loc.Col)
+	end := analysis.position(loc.Row, loc.Col+len([]rune(identifier.Tok.Raw)))
+
+	return Location{params.TextDocument.URI, Range{start, end}}
+}
+
+// references locates the uses of the variable at the position
+func (server *Server) references(params *referenceParams) interface{} {
+	analysis := server.analysis(params.TextDocument.URI)
+
+	if analysis == nil {
+		return nil
+	}
+
+	identifier, _ := analysis.identifierAt(params.Position)
+
+	if identifier == nil {
+		return nil
+	}
+
+	locations := make([]Location, 0)
+
+	for _, reference := range analysis.references(identifier) {
+		if reference.IsBeingDeclared() && !params.Context.IncludeDeclaration {
+			continue
+		}
+
+		locations = append(locations, Location{params.TextDocument.URI, analysis.rangeOf(reference.Tok)})
+	}
+
+	return locations
+}
+
+// rename renames the variable at the position, in its declaration and its uses
+func (server *Server) rename(params *renameParams) (interface{}, *responseError) {
+	if !isIdentifier(params.NewName) {
+		return nil, &responseError{invalidParams, fmt.Sprintf("%q is not an identifier", params.NewName)}
+	}
+
+	analysis := server.analysis(params.TextDocument.URI)
+
+	if analysis == nil {
+		return nil, nil
+	}
+
+	identifier, _ := analysis.identifierAt(params.Position)
+
+	if identifier == nil {
+		return nil, nil
+	}
+
+	if analysis.renameConflicts(identifier, params.NewName) {
+		return nil, &responseError{invalidParams, fmt.Sprintf("%q is already declared where %q is used", params.NewName, identifier.Tok.Raw)}
+	}
+
+	edits := make([]TextEdit, 0)
+
+	for _, reference := range analysis.references(identifier) {
+		edits = append(edits, TextEdit{analysis.rangeOf(reference.Tok), params.NewName})
+	}
+
+	return WorkspaceEdit{map[string][]TextEdit{params.TextDocument.URI: edits}}, nil
+}
+
+// documentSymbols lists the variables, consts and extern functions declared in the document
+func (server *Server) documentSymbols(params *documentSymbolParams) interface{} {
+	analysis := server.analysis(params.TextDocument.URI)
+
+	if analysis == nil {
+		return nil
+	}
+
+	symbols := make([]DocumentSymbol, 0)
+
+	for _, identifier := range analysis.identifiers() {
+		declaration := analysis.root.Find(identifier.Parent)
+
+		if !identifier.IsBeingDeclared() || declaration == nil {
+			continue
+		}
+
+		symbol := DocumentSymbol{
+			Name:           identifier.Tok.Raw,
+			Kind:           symbolKindVariable,
+			Range:          analysis.branchRange(declaration),
+			SelectionRange: analysis.rangeOf(identifier.Tok),
+		}
+
+		if _, isFunction := identifier.GetTyping().(*typing.FunctionType); isFunction {
+			symbol.Kind = symbolKindFunction
+		} else if identifier.GetBinding() != nil && !identifier.GetBinding().IsVariable {
+			symbol.Kind = symbolKindConstant
+		}
+
+		if identifier.GetTyping() != nil {
+			symbol.Detail = identifier.GetTyping().String()
+		}
+
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols
+}
+
+// completion proposes the keywords, and the variables declared before the position in its scope
+// and in the scopes enclosing it
+func (server *Server) completion(params *textDocumentPositionParams) interface{} {
+	items := make([]CompletionItem, 0)
+
+	keywords := make([]string, 0)
+
+	for keyword := range token.GetKeywordsMapping() {
+		keywords = append(keywords, keyword)
+	}
+
+	sort.Strings(keywords)
+
+	for _, keyword := range keywords {
+		items = append(items, CompletionItem{Label: keyword, Kind: completionKindKeyword})
+	}
+
+	doc, ok := server.documents[params.TextDocument.URI]
+
+	if !ok || doc.lastAnalysis == nil {
+		return items
+	}
+
+	analysis := doc.lastAnalysis
+	seen := make(map[string]bool)
+
+	for scope := analysis.scopeAt(analysis.root, params.Position); scope != nil; scope = scope.BaseScope {
+		for _, identifier := range scope.Identifiers() {
+			binding := scope.FindBinding(identifier)
+
+			if seen[identifier] || !analysis.declaredBefore(binding, params.Position) {
+				continue
+			}
+
+			seen[identifier] = true
+
+			item := CompletionItem{Label: identifier, Kind: completionKindVariable, Detail: binding.GetTyping().String()}
+
+			if _, isFunction := binding.GetTyping().(*typing.FunctionType); isFunction {
+				item.Kind = completionKindFunction
+			} else if !binding.IsVariable {
+				item.Kind = completionKindConstant
+			}
+
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// declaredBefore tells whether binding is declared before pos
+func (analysis *analysis) declaredBefore(binding *symbolTable.Binding, pos Position) bool {
+	loc, ok := binding.GetLocator().(*locator.FileLocation)
+
+	return ok && before(analysis.position(loc.Row, loc.Col), pos)
+}
+
+func declarationKeyword(identifier *ast.IdentifierNode) string {
+	if _, isFunction := identifier.GetTyping().(*typing.FunctionType); isFunction {
+		return "extern func"
+	}
+
+	if identifier.GetBinding() != nil && !identifier.GetBinding().IsVariable {
+		return "const"
+	}
+
+	return "let"
+}
+
+// isIdentifier tells whether name scans as a single identifier
+func isIdentifier(name string) bool {
+	var stringInput input.StringInput
+	stringInput.Init(name)
+
+	var s scanner.ExpressiveScanner
+	s.Init(&stringInput)
+
+	tok := s.Next()
+
+	return tok.TokenType == token.IDENTIFIER && tok.Raw == name && s.Next().TokenType == token.EOF
+}
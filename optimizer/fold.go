@@ -0,0 +1,189 @@
+package optimizer
+
+import (
+	"math"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/builtin"
+	"github.com/carlcui/expressive/signature"
+	"github.com/carlcui/expressive/typing"
+)
+
+// value of a literal node: int32, float64, bool, char, or string
+type value interface{}
+
+// char is the value of a character literal, distinct from int32
+type char rune
+
+// literalValue returns the value of node, or false if node is not a literal
+func literalValue(node ast.Node) (value, bool) {
+	switch literal := node.(type) {
+	case *ast.IntegerNode:
+		return int32(literal.Val), true
+	case *ast.FloatNode:
+		return float64(literal.Val), true
+	case *ast.BooleanNode:
+		return literal.Val, true
+	case *ast.CharacterNode:
+		return char(literal.Val), true
+	case *ast.StringNode:
+		return literal.Val, true
+	default:
+		return nil, false
+	}
+}
+
+// newLiteral creates a literal node of the given value, at the location of original. It returns
+// false if the value cannot be a literal: a float literal holds a float32, so a result that is
+// not exactly a float32 would print differently than when computed at runtime.
+func newLiteral(val value, original ast.Node) (ast.Node, bool) {
+	base := ast.CreateBaseNode(original.GetToken(), original.GetParent())
+
+	var literal ast.Node
+
+	switch val := val.(type) {
+	case int32:
+		literal = &ast.IntegerNode{BaseNode: base, Val: int(val)}
+	case float64:
+		if math.IsInf(val, 0) || math.IsNaN(val) || float64(float32(val)) != val {
+			return nil, false
+		}
+
+		literal = &ast.FloatNode{BaseNode: base, Val: float32(val)}
+	case bool:
+		literal = &ast.BooleanNode{BaseNode: base, Val: val}
+	case char:
+		literal = &ast.CharacterNode{BaseNode: base, Val: rune(val)}
+	case string:
+		literal = &ast.StringNode{BaseNode: base, Val: val}
+	default:
+		return nil, false
+	}
+
+	literal.SetTyping(original.GetTyping())
+
+	return literal, true
+}
+
+// fold applies an operator to constant operands the way the generated code does at runtime. An
+// int overflow or a division by zero is left to fail at runtime, which it does with runtime
+// checks. It returns false if the operation cannot be folded.
+func fold(operator signature.Operator, operandTyping typing.Typing, operands ...value) (value, bool) {
+	switch operator {
+	case signature.LOGIC_NOT:
+		return !operands[0].(bool), true
+	case signature.NEGATE:
+		switch operand := operands[0].(type) {
+		case int32:
+			return exactInt(-int64(operand))
+		case float64:
+			return -operand, true
+		}
+	}
+
+	switch lhs := operands[0].(type) {
+	case int32:
+		return foldInt(operator, lhs, operands[1].(int32))
+	case float64:
+		return foldFloat(operator, lhs, operands[1].(float64))
+	case bool:
+		switch operator {
+		case signature.LOGIC_AND:
+			return lhs && operands[1].(bool), true
+		case signature.LOGIC_OR:
+			return lhs || operands[1].(bool), true
+		}
+
+		return foldEquality(operator, lhs == operands[1].(bool))
+	case char:
+		return foldEquality(operator, lhs == operands[1].(char))
+	case string:
+		if operator == signature.ADD && operandTyping == typing.STRING {
+			return lhs + operands[1].(string), true
+		}
+
+		return foldEquality(operator, lhs == operands[1].(string))
+	}
+
+	return nil, false
+}
+
+func foldInt(operator signature.Operator, lhs int32, rhs int32) (value, bool) {
+	switch operator {
+	case signature.ADD:
+		return exactInt(int64(lhs) + int64(rhs))
+	case signature.SUBTRACT:
+		return exactInt(int64(lhs) - int64(rhs))
+	case signature.MULTIPLY:
+		return exactInt(int64(lhs) * int64(rhs))
+	case signature.DIVIDE:
+		if rhs == 0 {
+			return nil, false
+		}
+
+		return exactInt(int64(lhs) / int64(rhs))
+	case signature.MODULO:
+		if rhs == 0 || (lhs == math.MinInt32 && rhs == -1) {
+			return nil, false
+		}
+
+		return lhs % rhs, true
+	case signature.EXPONENTIATE:
+		return builtin.PowInt(lhs, rhs), true
+	case signature.GREATER:
+		return lhs > rhs, true
+	case signature.GREATER_OR_EQUAL:
+		return lhs >= rhs, true
+	case signature.LESS:
+		return lhs < rhs, true
+	case signature.LESS_OR_EQUAL:
+		return lhs <= rhs, true
+	}
+
+	return foldEquality(operator, lhs == rhs)
+}
+
+// exactInt returns result as an int, or false if it overflows
+func exactInt(result int64) (value, bool) {
+	if result < math.MinInt32 || result > math.MaxInt32 {
+		return nil, false
+	}
+
+	return int32(result), true
+}
+
+func foldFloat(operator signature.Operator, lhs float64, rhs float64) (value, bool) {
+	switch operator {
+	case signature.ADD:
+		return lhs + rhs, true
+	case signature.SUBTRACT:
+		return lhs - rhs, true
+	case signature.MULTIPLY:
+		return lhs * rhs, true
+	case signature.DIVIDE:
+		return lhs / rhs, true
+	case signature.EXPONENTIATE:
+		return math.Pow(lhs, rhs), true
+	case signature.GREATER:
+		return lhs > rhs, true
+	case signature.GREATER_OR_EQUAL:
+		return lhs >= rhs, true
+	case signature.LESS:
+		return lhs < rhs, true
+	case signature.LESS_OR_EQUAL:
+		return lhs <= rhs, true
+	}
+
+	return foldEquality(operator, lhs == rhs)
+}
+
+func foldEquality(operator signature.Operator, equal bool) (value, bool) {
+	switch operator {
+	case signature.SHALLOW_EQUAL, signature.DEEP_EQUAL:
+		return equal, true
+	case signature.SHALLOW_NOT_EQUAL, signature.DEEP_NOT_EQUAL:
+		return !equal, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,16 @@
+package optimizer
+
+import (
+	"github.com/carlcui/expressive/ast"
+)
+
+// Optimize an analysed program in place: constant expressions are folded, consts initialized
+// with a literal are replaced by their value, and branches and loops that never run are
+// removed. The program prints the same, and fails at runtime at the same point.
+func Optimize(node ast.Node) {
+	var optimizer Optimizer
+	optimizer.Init()
+
+	program := node.(*ast.ProgramNode)
+	program.Chilren = optimizer.stmts(program.Chilren, program)
+}
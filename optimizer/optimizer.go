@@ -0,0 +1,336 @@
+package optimizer
+
+import (
+	"fmt"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/signature"
+	"github.com/carlcui/expressive/symbolTable"
+	"github.com/carlcui/expressive/typing"
+)
+
+// Optimizer rewrites an analysed ast into a simpler one printing the same. Expressions have no
+// side effects besides failing at runtime and calling functions, so an expression never
+// evaluated can be dropped, but one that is evaluated is only replaced by its value when it
+// cannot fail. Calls are never replaced.
+type Optimizer struct {
+	constants map[*symbolTable.Binding]ast.Node // literal values of consts
+}
+
+func (optimizer *Optimizer) Init() {
+	optimizer.constants = make(map[*symbolTable.Binding]ast.Node)
+}
+
+// stmts optimizes the statements of a block or program, now children of parent
+func (optimizer *Optimizer) stmts(stmts []ast.Node, parent ast.Node) []ast.Node {
+	optimized := make([]ast.Node, 0, len(stmts))
+
+	for _, stmt := range stmts {
+		for _, replacement := range optimizer.stmt(stmt) {
+			replacement.SetParent(parent)
+			optimized = append(optimized, replacement)
+		}
+	}
+
+	return optimized
+}
+
+// stmt optimizes a statement into the statements replacing it, possibly none
+func (optimizer *Optimizer) stmt(node ast.Node) []ast.Node {
+	switch stmt := node.(type) {
+	case *ast.BlockNode:
+		stmt.Stmts = optimizer.stmts(stmt.Stmts, stmt)
+	case *ast.VariableDeclarationNode:
+		if stmt.Expr == nil {
+			break
+		}
+
+		stmt.Expr = optimizer.child(stmt.Expr, stmt)
+
+		identifier := stmt.Identifier.(*ast.IdentifierNode)
+
+		if _, isLiteral := literalValue(stmt.Expr); isLiteral && !identifier.GetBinding().IsVariable {
+			// every use of the const is replaced by its value
+			optimizer.constants[identifier.GetBinding()] = stmt.Expr
+			return nil
+		}
+	case *ast.AssignmentNode:
+		stmt.RHS = optimizer.child(stmt.RHS, stmt)
+	case *ast.IncDecNode:
+	case *ast.PrintNode:
+		stmt.StringExpr = optimizer.child(stmt.StringExpr, stmt)
+
+		for i, arg := range stmt.Args {
+			stmt.Args[i] = optimizer.child(arg, stmt)
+		}
+	case *ast.CallNode:
+		optimizer.expr(stmt)
+	case *ast.IfStmtNode:
+		return optimizer.ifStmt(stmt)
+	case *ast.WhileStmtNode:
+		stmt.ConditionExpr = optimizer.child(stmt.ConditionExpr, stmt)
+
+		if condition, ok := literalValue(stmt.ConditionExpr); ok && !condition.(bool) {
+			return nil
+		}
+
+		optimizer.stmt(stmt.Block)
+	case *ast.ForStmtNode:
+		if stmt.InitializationStmt != nil {
+			stmt.InitializationStmt = optimizer.simpleStmt(stmt.InitializationStmt, stmt)
+		}
+
+		if stmt.ConditionExpr != nil {
+			stmt.ConditionExpr = optimizer.child(stmt.ConditionExpr, stmt)
+		}
+
+		if stmt.IterationStmt != nil {
+			stmt.IterationStmt = optimizer.simpleStmt(stmt.IterationStmt, stmt)
+		}
+
+		optimizer.stmt(stmt.Block)
+	case *ast.SwitchStmtNode:
+		return optimizer.switchStmt(stmt)
+	case *ast.BreakNode, *ast.ExternNode:
+	default:
+		panic(fmt.Sprintf("%v: unexpected statement %T", node.GetLocation(), node))
+	}
+
+	return []ast.Node{node}
+}
+
+// simpleStmt optimizes the initialization or iteration statement of a for, which is nil once
+// optimized away
+func (optimizer *Optimizer) simpleStmt(node ast.Node, parent ast.Node) ast.Node {
+	replacements := optimizer.stmt(node)
+
+	if len(replacements) == 0 {
+		return nil
+	}
+
+	replacements[0].SetParent(parent)
+
+	return replacements[0]
+}
+
+// ifStmt drops the arms whose condition is false, and the arms after one whose condition is
+// true, which becomes the else block
+func (optimizer *Optimizer) ifStmt(node *ast.IfStmtNode) []ast.Node {
+	conditionExprs := node.ConditionExprs
+	conditionBlocks := node.ConditionBlocks
+
+	node.ConditionExprs = nil
+	node.ConditionBlocks = nil
+
+	for i, conditionExpr := range conditionExprs {
+		conditionExpr = optimizer.child(conditionExpr, node)
+		optimizer.stmt(conditionBlocks[i])
+
+		condition, isLiteral := literalValue(conditionExpr)
+
+		if !isLiteral {
+			node.AddCondition(conditionExpr, conditionBlocks[i])
+			continue
+		}
+
+		if condition.(bool) {
+			node.ElseBlock = conditionBlocks[i]
+			node.ElseBlock.SetParent(node)
+
+			return optimizer.elseOnly(node)
+		}
+	}
+
+	if node.ElseBlock != nil {
+		optimizer.stmt(node.ElseBlock)
+	}
+
+	return optimizer.elseOnly(node)
+}
+
+// elseOnly replaces an if statement left without conditions by its else block
+func (optimizer *Optimizer) elseOnly(node *ast.IfStmtNode) []ast.Node {
+	if len(node.ConditionExprs) > 0 {
+		return []ast.Node{node}
+	}
+
+	if node.ElseBlock == nil {
+		return nil
+	}
+
+	return []ast.Node{node.ElseBlock}
+}
+
+// switchStmt drops the arms that can never be entered: their case cannot match the test, or a
+// previous case always matches, and the previous block cannot fall through into them
+func (optimizer *Optimizer) switchStmt(node *ast.SwitchStmtNode) []ast.Node {
+	node.TestExpr = optimizer.child(node.TestExpr, node)
+	test, constantTest := literalValue(node.TestExpr)
+
+	caseExprs := node.CaseExprs
+	caseBlocks := node.CaseBlocks
+
+	node.CaseExprs = nil
+	node.CaseBlocks = nil
+
+	matched := false   // a previous case always matches, so no later case is evaluated
+	fallsIn := false   // the previous block may fall through into the next one
+	emptyKept := false // an empty block was kept, so the next one is entered on its match
+
+	for i, caseExpr := range caseExprs {
+		caseExpr = optimizer.child(caseExpr, node)
+		block := caseBlocks[i]
+		optimizer.stmt(block)
+
+		mayMatch := !matched
+
+		if c, constantCase := literalValue(caseExpr); constantTest && constantCase && !matched {
+			equal, _ := fold(signature.SHALLOW_EQUAL, node.TestExpr.GetTyping(), test, c)
+			mayMatch = equal.(bool)
+			matched = mayMatch
+		}
+
+		if !mayMatch && !fallsIn && !emptyKept {
+			continue
+		}
+
+		node.AppendCaseExpr(caseExpr)
+		node.AppendCaseBlock(block)
+
+		if block.(*ast.BlockNode).IsEmptyBlock() {
+			emptyKept = emptyKept || mayMatch
+		} else {
+			fallsIn = !breaksOut(block, node)
+			emptyKept = false
+		}
+	}
+
+	if node.DefaultBlock != nil {
+		if matched && !fallsIn && !emptyKept {
+			node.DefaultBlock = nil
+		} else {
+			optimizer.stmt(node.DefaultBlock)
+		}
+	}
+
+	if constantTest && len(node.CaseExprs) == 0 && node.IsEmptyDefaultBlock() {
+		return nil
+	}
+
+	return []ast.Node{node}
+}
+
+// breaksOut tells whether block always ends with a break out of stmt
+func breaksOut(block ast.Node, stmt ast.Node) bool {
+	stmts := block.(*ast.BlockNode).Stmts
+
+	if len(stmts) == 0 {
+		return false
+	}
+
+	breakNode, ok := stmts[len(stmts)-1].(*ast.BreakNode)
+
+	return ok && breakNode.FindNearestValidStatementNode() == stmt
+}
+
+// child optimizes an expression, now a child of parent
+func (optimizer *Optimizer) child(node ast.Node, parent ast.Node) ast.Node {
+	optimized := optimizer.expr(node)
+	optimized.SetParent(parent)
+
+	return optimized
+}
+
+// expr optimizes an expression into the expression replacing it
+func (optimizer *Optimizer) expr(node ast.Node) ast.Node {
+	switch expr := node.(type) {
+	case *ast.IntegerNode, *ast.FloatNode, *ast.BooleanNode, *ast.CharacterNode, *ast.StringNode:
+		return node
+	case *ast.IdentifierNode:
+		constant, ok := optimizer.constants[expr.GetBinding()]
+
+		if !ok {
+			return node
+		}
+
+		val, _ := literalValue(constant)
+		literal, _ := newLiteral(val, node)
+
+		return literal
+	case *ast.UnaryOperatorNode:
+		expr.Expr = optimizer.child(expr.Expr, expr)
+
+		// !!x is x
+		if inner, ok := expr.Expr.(*ast.UnaryOperatorNode); ok && expr.Operator == signature.LOGIC_NOT && inner.Operator == signature.LOGIC_NOT {
+			return inner.Expr
+		}
+
+		return optimizer.foldOperation(node, expr.Operator, expr.Expr.GetTyping(), expr.Expr)
+	case *ast.BinaryOperatorNode:
+		expr.Lhs = optimizer.child(expr.Lhs, expr)
+		expr.Rhs = optimizer.child(expr.Rhs, expr)
+
+		// the right-hand side of && and || is only evaluated when the left-hand side does not
+		// decide the result
+		if lhs, ok := literalValue(expr.Lhs); ok && (expr.Operator == signature.LOGIC_AND || expr.Operator == signature.LOGIC_OR) {
+			if lhs.(bool) == (expr.Operator == signature.LOGIC_OR) {
+				return expr.Lhs
+			}
+
+			return expr.Rhs
+		}
+
+		return optimizer.foldOperation(node, expr.Operator, expr.Lhs.GetTyping(), expr.Lhs, expr.Rhs)
+	case *ast.TernaryOperatorNode:
+		expr.Expr1 = optimizer.child(expr.Expr1, expr)
+		expr.Expr2 = optimizer.child(expr.Expr2, expr)
+		expr.Expr3 = optimizer.child(expr.Expr3, expr)
+
+		if condition, ok := literalValue(expr.Expr1); ok {
+			if condition.(bool) {
+				return expr.Expr2
+			}
+
+			return expr.Expr3
+		}
+
+		return node
+	case *ast.CallNode:
+		for i, arg := range expr.Args {
+			expr.Args[i] = optimizer.child(arg, expr)
+		}
+
+		return node
+	default:
+		panic(fmt.Sprintf("%v: unexpected expression %T", node.GetLocation(), node))
+	}
+}
+
+// foldOperation replaces an operation on literals by its result
+func (optimizer *Optimizer) foldOperation(node ast.Node, operator signature.Operator, operandTyping typing.Typing, operands ...ast.Node) ast.Node {
+	values := make([]value, len(operands))
+
+	for i, operand := range operands {
+		val, ok := literalValue(operand)
+
+		if !ok {
+			return node
+		}
+
+		values[i] = val
+	}
+
+	result, ok := fold(operator, operandTyping, values...)
+
+	if !ok {
+		return node
+	}
+
+	literal, ok := newLiteral(result, node)
+
+	if !ok {
+		return node
+	}
+
+	return literal
+}
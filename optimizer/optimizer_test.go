@@ -0,0 +1,106 @@
+package optimizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/internal/testutil"
+	"github.com/carlcui/expressive/interp"
+	"github.com/carlcui/expressive/logger"
+)
+
+const testDir = "./testFiles"
+
+func run(root ast.Node) (string, []string) {
+	var out bytes.Buffer
+	var buffer logger.Buffer
+
+	interp.Run(root, &out, &buffer)
+
+	return out.String(), buffer.Messages()
+}
+
+// countNodes counts the nodes of root of the given type, as named in its json
+func countNodes(root ast.Node, nodeType string, t *testing.T) int {
+	encoded, err := json.Marshal(root)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.Count(string(encoded), `"NodeType":"`+nodeType+`"`)
+}
+
+func TestOutputUnchanged(t *testing.T) {
+	for _, dirName := range []string{"../e2e", "../interp/testFiles", testDir} {
+		files, err := filepath.Glob(filepath.Join(dirName, "*.exp"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, file := range files {
+			fileName := filepath.Base(file)
+
+			expectedOut, expectedMessages := run(testutil.AnalyzeFile(dirName, fileName, t))
+
+			root := testutil.AnalyzeFile(dirName, fileName, t)
+			Optimize(root)
+
+			out, messages := run(root)
+
+			if out != expectedOut {
+				t.Errorf("File %v: expecting optimized output\n%v\nbut got\n%v", file, expectedOut, out)
+			}
+
+			if !reflect.DeepEqual(messages, expectedMessages) {
+				t.Errorf("File %v: expecting optimized messages %v, got %v", file, expectedMessages, messages)
+			}
+		}
+	}
+}
+
+func TestFolding(t *testing.T) {
+	root := testutil.AnalyzeFile(testDir, "folding.exp", t)
+	Optimize(root)
+
+	expectedCounts := map[string]int{
+		"binary operator":      6, // 2147483647 + 1, 0.1 + 0.2, x > 2 || verbose, x == 3 and x < 10
+		"unary operator":       1, // -(-2147483648)
+		"ternary operator":     0,
+		"if statement":         2,
+		"while statement":      0,
+		"variable declaration": 1, // consts are removed
+	}
+
+	for nodeType, expectedCount := range expectedCounts {
+		if count := countNodes(root, nodeType, t); count != expectedCount {
+			t.Errorf("Expecting %v %v node(s) once optimized, got %v", expectedCount, nodeType, count)
+		}
+	}
+}
+
+func TestSwitchArms(t *testing.T) {
+	root := testutil.AnalyzeFile(testDir, "switch.exp", t)
+	before := countNodes(root, "block", t)
+
+	Optimize(root)
+
+	if after := countNodes(root, "block", t); after >= before {
+		t.Errorf("Expecting arms of constant switches to be removed, got %v blocks out of %v", after, before)
+	}
+}
+
+func TestRuntimeErrorKept(t *testing.T) {
+	root := testutil.AnalyzeFile(testDir, "runtime_error.exp", t)
+	Optimize(root)
+
+	if count := countNodes(root, "binary operator", t); count != 1 {
+		t.Errorf("Expecting the division by zero not to be folded, got %v binary operator(s)", count)
+	}
+}
@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/cst"
+	"github.com/carlcui/expressive/token"
+)
+
+// cstBuilder builds the concrete syntax tree of the tokens the parser reads. Parse functions
+// mark the token an ast node starts at, and finish the node once its last token is read; as a
+// node finishes after the nodes it is parsed from, the branches finished within its tokens are
+// its children.
+type cstBuilder struct {
+	tokens   []*token.Token // read so far, the last one being the current token
+	branches []branch       // finished, not yet the child of another branch
+}
+
+// branch of the cst, parsed from the tokens from start up to end
+type branch struct {
+	node       *cst.Node
+	start, end int
+}
+
+// ParseCST parses the program like Parse, and builds its concrete syntax tree, whose root branch
+// holds the program node. To reprint the source exactly, scanner must keep trivia, as a
+// lossless ExpressiveScanner does.
+func (parser *Parser) ParseCST() *cst.Node {
+	parser.cst = &cstBuilder{tokens: make([]*token.Token, 0), branches: make([]branch, 0)}
+
+	program := parser.Parse()
+
+	// tokens following a syntax error are left unread
+	for parser.cur.TokenType != token.EOF {
+		parser.read()
+	}
+
+	builder := parser.cst
+	parser.cst = nil
+
+	return builder.finish(program, 0, len(builder.tokens))
+}
+
+// record the token read as the current token
+func (builder *cstBuilder) record(tok *token.Token) {
+	if len(builder.tokens) > 0 && builder.tokens[len(builder.tokens)-1].TokenType == token.EOF {
+		return // EOF is read again when expected
+	}
+
+	builder.tokens = append(builder.tokens, tok)
+}
+
+// mark returns where a node starting at the current token starts
+func (parser *Parser) mark() int {
+	if parser.cst == nil {
+		return 0
+	}
+
+	return len(parser.cst.tokens) - 1
+}
+
+// finish makes node a branch from start up to the current token, and returns node
+func (parser *Parser) finish(start int, node ast.Node) ast.Node {
+	if parser.cst != nil {
+		parser.cst.finish(node, start, len(parser.cst.tokens)-1)
+	}
+
+	return node
+}
+
+// finish makes a branch of syntax from start up to end. Finishing an ast node again, e.g. a
+// statement once its semicolon is read, widens its branch.
+func (builder *cstBuilder) finish(syntax ast.Node, start int, end int) *cst.Node {
+	first := len(builder.branches)
+
+	for first > 0 && builder.branches[first-1].start >= start {
+		first--
+	}
+
+	children := make([]*cst.Node, 0)
+	next := start
+
+	for _, child := range builder.branches[first:] {
+		children = append(children, builder.leaves(next, child.start)...)
+
+		if child.node.Syntax == syntax {
+			children = append(children, child.node.Children...)
+		} else {
+			children = append(children, child.node)
+		}
+
+		next = child.end
+	}
+
+	children = append(children, builder.leaves(next, end)...)
+
+	node := cst.NewBranch(syntax, children)
+
+	builder.branches = append(builder.branches[:first], branch{node, start, end})
+
+	return node
+}
+
+// leaves returns the leaves of the tokens from start up to end
+func (builder *cstBuilder) leaves(start int, end int) []*cst.Node {
+	leaves := make([]*cst.Node, 0, end-start)
+
+	for _, tok := range builder.tokens[start:end] {
+		leaves = append(leaves, cst.NewLeaf(tok))
+	}
+
+	return leaves
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/cst"
+	"github.com/carlcui/expressive/input"
+	"github.com/carlcui/expressive/logger"
+	"github.com/carlcui/expressive/scanner"
+)
+
+func parseSource(src string, lossless bool) (ast.Node, *cst.Node) {
+	var stringInput input.StringInput
+	stringInput.Init(src)
+
+	var s scanner.ExpressiveScanner
+
+	if lossless {
+		s.InitLossless(&stringInput)
+	} else {
+		s.Init(&stringInput)
+	}
+
+	var parser Parser
+	parser.Init(&s, &logger.Buffer{})
+
+	if !lossless {
+		return parser.Parse(), nil
+	}
+
+	root := parser.ParseCST()
+
+	return root.Syntax, root
+}
+
+func marshal(node ast.Node, t *testing.T) string {
+	encoded, err := json.Marshal(node)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(encoded)
+}
+
+func TestCSTReprintsSource(t *testing.T) {
+	files, err := filepath.Glob("../e2e/*.exp")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sources := []string{
+		"// leading comment\nlet a = -1; /* trailing */\n\n\tprint \"%d\\n\", (a + 2) * -a;  \n// at end",
+		"let a = ;\nprint 1 2 3 @ \"never read\"\n",
+		"if (true) { print \"x\"; } else if (false) {} else { a++; }",
+		"switch (1) { case 1: break; default: }",
+		"let a = 1;\r\nprint \"%d\", a; // crlf\r\n/* block\r\n */\r\n",
+		"\uFEFF// byte order mark\nlet a = 1;",
+		"\uFEFF",
+		"",
+	}
+
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sources = append(sources, string(src))
+	}
+
+	for _, src := range sources {
+		if _, root := parseSource(src, true); root.String() != src {
+			t.Errorf("Expecting the cst to reprint\n%q\ngot\n%q", src, root.String())
+		}
+	}
+}
+
+func TestCSTDerivesAst(t *testing.T) {
+	src := "let x: int = 1 + 2 * -3;\nwhile (x < 10) { x += 1; } // done"
+
+	expected, _ := parseSource(src, false)
+	program, root := parseSource(src, true)
+
+	if _, ok := program.(*ast.ProgramNode); !ok || marshal(program, t) != marshal(expected, t) {
+		t.Errorf("Expecting the cst to hold the ast parsed without it, got %v", marshal(program, t))
+	}
+
+	whileStmt := program.(*ast.ProgramNode).Chilren[1]
+
+	if branch := root.Find(whileStmt); branch == nil || branch.String() != "\nwhile (x < 10) { x += 1; }" {
+		t.Errorf("Expecting the branch of the while statement to span it, got %v", branch)
+	}
+
+	declaration := root.Find(program.(*ast.ProgramNode).Chilren[0])
+
+	if declaration == nil || declaration.String() != "let x: int = 1 + 2 * -3;" || declaration.Children[len(declaration.Children)-1].Token.Raw != ";" {
+		t.Errorf("Expecting the branch of the declaration to end with its semicolon, got %v", declaration)
+	}
+}
@@ -17,6 +17,8 @@ type Parser struct {
 
 	cur  *token.Token
 	prev *token.Token
+
+	cst *cstBuilder // nil unless a cst is built
 }
 
 // Init initializes a new parser with given scanner
@@ -31,7 +33,21 @@ func (parser *Parser) Parse() ast.Node {
 	return parser.parseProgram()
 }
 
+// ParseExpression parses a source made of a single expression, e.g. an expression entered in a
+// repl
+func (parser *Parser) ParseExpression() ast.Node {
+	parser.read()
+
+	expr := parser.parseExpr()
+
+	parser.expect(token.EOF)
+
+	return expr
+}
+
 func (parser *Parser) parseProgram() ast.Node {
+	start := parser.mark()
+
 	var node ast.ProgramNode
 	node.Init(parser.cur)
 
@@ -49,10 +65,11 @@ func (parser *Parser) parseProgram() ast.Node {
 
 	parser.expect(token.EOF)
 
-	return &node
+	return parser.finish(start, &node)
 }
 
 func (parser *Parser) parseBlock() ast.Node {
+	start := parser.mark()
 
 	var node ast.BlockNode
 	node.BaseNode = ast.CreateBaseNode(parser.cur, nil)
@@ -69,15 +86,17 @@ func (parser *Parser) parseBlock() ast.Node {
 
 	node.Stmts = stmts
 
-	return &node
+	return parser.finish(start, &node)
 }
 
 func (parser *Parser) parseBlockWithBraces() ast.Node {
+	start := parser.mark()
+
 	parser.expect(token.LEFT_CURLY_BRACE)
 	node := parser.parseBlock()
 	parser.expect(token.RIGHT_CURLY_BRACE)
 
-	return node
+	return parser.finish(start, node)
 }
 
 // Stmts
@@ -91,6 +110,8 @@ func (parser *Parser) parseStmt() ast.Node {
 		return parser.syntaxErrorNode("statement")
 	}
 
+	start := parser.mark()
+
 	var node ast.Node
 
 	if parser.isStmtWithSemiStart(parser.cur) {
@@ -104,13 +125,14 @@ func (parser *Parser) parseStmt() ast.Node {
 		panic("parseStmt: not a stmt")
 	}
 
-	return node
+	return parser.finish(start, node)
 }
 
 func (parser *Parser) isStmtWithSemiStart(tok *token.Token) bool {
 	return parser.isVariableDeclarationStmtStart(tok) ||
 		parser.isPrintStmtStart(tok) ||
 		parser.isBreakStmtStart(tok) ||
+		parser.isExternStmtStart(tok) ||
 		parser.isStmtStartWithExprStart(tok)
 }
 
@@ -119,6 +141,8 @@ func (parser *Parser) parseStmtWithSemi() ast.Node {
 		return parser.syntaxErrorNode("statement with semi")
 	}
 
+	start := parser.mark()
+
 	var node ast.Node
 
 	if parser.isVariableDeclarationStmtStart(parser.cur) {
@@ -127,11 +151,13 @@ func (parser *Parser) parseStmtWithSemi() ast.Node {
 		node = parser.parsePrintStmt()
 	} else if parser.isBreakStmtStart(parser.cur) {
 		node = parser.parseBreakStmt()
+	} else if parser.isExternStmtStart(parser.cur) {
+		node = parser.parseExternStmt()
 	} else if parser.isStmtStartWithExprStart(parser.cur) {
 		node = parser.parseStmtsStartWithExpr()
 	}
 
-	return node
+	return parser.finish(start, node)
 }
 
 func (parser *Parser) isStmtWithoutSemiStart(tok *token.Token) bool {
@@ -226,12 +252,20 @@ func (parser *Parser) parseStmtsStartWithExpr() ast.Node {
 		return parser.parseAssignmentStmt(leftMostToken, lhs)
 	case parser.isIncrementDecrementOperator(parser.cur):
 		return parser.parseIncrementDecrementStmt(leftMostToken, lhs)
+	case isCall(lhs):
+		return lhs
 	default:
 		return parser.syntaxErrorNode(parseErrorMsg)
 	}
 
 }
 
+func isCall(node ast.Node) bool {
+	_, ok := node.(*ast.CallNode)
+
+	return ok
+}
+
 func (parser *Parser) isAssignmentOperators(tok *token.Token) bool {
 	tokenType := tok.TokenType
 
@@ -522,6 +556,71 @@ func (parser *Parser) parsePrintStmt() ast.Node {
 	return &node
 }
 
+func (parser *Parser) isExternStmtStart(tok *token.Token) bool {
+	return tok.TokenType == token.EXTERN
+}
+
+// parseExternStmt parses the declaration of a function defined outside of the program, like
+// `extern func printf(format: string, ...) -> int`
+func (parser *Parser) parseExternStmt() ast.Node {
+	if !parser.isExternStmtStart(parser.cur) {
+		return parser.syntaxErrorNode("extern declaration")
+	}
+
+	var node ast.ExternNode
+	node.BaseNode = ast.CreateBaseNode(parser.cur, nil)
+
+	parser.expect(token.EXTERN)
+	parser.expect(token.FUNC)
+
+	identifier := parser.parseIdentifier()
+	identifier.SetParent(&node)
+
+	node.Identifier = identifier
+
+	parser.expect(token.LEFT_PAREN)
+
+	if parser.cur.TokenType != token.RIGHT_PAREN {
+		parser.parseParam(&node)
+
+		for parser.cur.TokenType == token.COMMA && !node.Variadic {
+			parser.read()
+
+			parser.parseParam(&node)
+		}
+	}
+
+	parser.expect(token.RIGHT_PAREN)
+
+	if parser.cur.TokenType == token.ARROW {
+		parser.read()
+
+		resultType := parser.parseTypeLiteral()
+		resultType.SetParent(&node)
+
+		node.ResultType = resultType
+	}
+
+	return &node
+}
+
+// parseParam parses a parameter of an extern declaration, like `format: string`, or the `...`
+// ending the parameters of a variadic function
+func (parser *Parser) parseParam(node *ast.ExternNode) {
+	if parser.cur.TokenType == token.ELLIPSIS {
+		parser.read()
+
+		node.Variadic = true
+		return
+	}
+
+	name := parser.parseIdentifier()
+
+	parser.expect(token.COLON)
+
+	node.AddParam(name, parser.parseTypeLiteral())
+}
+
 // Exprs
 
 func (parser *Parser) isExprStart(tok *token.Token) bool {
@@ -545,6 +644,8 @@ func (parser *Parser) parseExprTernaryIfElse() ast.Node {
 		return parser.syntaxErrorNode("ternary if else expression")
 	}
 
+	start := parser.mark()
+
 	expr1 := parser.parseExprOr()
 
 	if parser.cur.TokenType == token.QUESTION_MARK {
@@ -558,7 +659,7 @@ func (parser *Parser) parseExprTernaryIfElse() ast.Node {
 
 		expr3 := parser.parseExprOr()
 
-		expr1 = ast.CreateTernaryOperatorNode(cur, signature.IF_ELSE, expr1, expr2, expr3)
+		expr1 = parser.finish(start, ast.CreateTernaryOperatorNode(cur, signature.IF_ELSE, expr1, expr2, expr3))
 	}
 
 	return expr1
@@ -573,6 +674,8 @@ func (parser *Parser) parseExprOr() ast.Node {
 		return parser.syntaxErrorNode("logical or expression")
 	}
 
+	start := parser.mark()
+
 	lhs := parser.parseExprAnd()
 
 	for parser.cur.TokenType == token.LOR {
@@ -582,7 +685,7 @@ func (parser *Parser) parseExprOr() ast.Node {
 
 		rhs := parser.parseExprAnd()
 
-		lhs = ast.CreateBinaryOperatorNode(cur, signature.LOGIC_OR, lhs, rhs)
+		lhs = parser.finish(start, ast.CreateBinaryOperatorNode(cur, signature.LOGIC_OR, lhs, rhs))
 	}
 	return lhs
 }
@@ -596,6 +699,8 @@ func (parser *Parser) parseExprAnd() ast.Node {
 		return parser.syntaxErrorNode("logical and expression")
 	}
 
+	start := parser.mark()
+
 	lhs := parser.parseExprComp()
 
 	for parser.cur.TokenType == token.LAND {
@@ -605,7 +710,7 @@ func (parser *Parser) parseExprAnd() ast.Node {
 
 		rhs := parser.parseExprComp()
 
-		lhs = ast.CreateBinaryOperatorNode(cur, signature.GetOperator(cur), lhs, rhs)
+		lhs = parser.finish(start, ast.CreateBinaryOperatorNode(cur, signature.GetOperator(cur), lhs, rhs))
 	}
 
 	return lhs
@@ -633,6 +738,8 @@ func (parser *Parser) parseExprComp() ast.Node {
 		return parser.syntaxErrorNode("comparison expression")
 	}
 
+	start := parser.mark()
+
 	lhs := parser.parseExprAdd()
 
 	for parser.isExprCompOperator(parser.cur) {
@@ -642,7 +749,7 @@ func (parser *Parser) parseExprComp() ast.Node {
 
 		rhs := parser.parseExprAdd()
 
-		lhs = ast.CreateBinaryOperatorNode(cur, signature.GetOperator(cur), lhs, rhs)
+		lhs = parser.finish(start, ast.CreateBinaryOperatorNode(cur, signature.GetOperator(cur), lhs, rhs))
 	}
 
 	return lhs
@@ -663,6 +770,8 @@ func (parser *Parser) parseExprAdd() ast.Node {
 		return parser.syntaxErrorNode("addition expression")
 	}
 
+	start := parser.mark()
+
 	lhs := parser.parseExprMul()
 
 	for parser.isExprAddOperator(parser.cur) {
@@ -672,14 +781,14 @@ func (parser *Parser) parseExprAdd() ast.Node {
 
 		rhs := parser.parseExprMul()
 
-		lhs = ast.CreateBinaryOperatorNode(cur, signature.GetOperator(cur), lhs, rhs)
+		lhs = parser.finish(start, ast.CreateBinaryOperatorNode(cur, signature.GetOperator(cur), lhs, rhs))
 	}
 
 	return lhs
 }
 
 func (parser *Parser) isExprMulStart(tok *token.Token) bool {
-	return parser.isExprNotStart(tok)
+	return parser.isExprUnaryStart(tok)
 }
 
 func (parser *Parser) isExprMulOperator(tok *token.Token) bool {
@@ -691,45 +800,72 @@ func (parser *Parser) parseExprMul() ast.Node {
 		return parser.syntaxErrorNode("multiplication expression")
 	}
 
-	lhs := parser.parseExprNot()
+	start := parser.mark()
+
+	lhs := parser.parseExprUnary()
 
 	for parser.isExprMulOperator(parser.cur) {
 		cur := parser.cur
 
 		parser.read()
 
-		rhs := parser.parseExprNot()
+		rhs := parser.parseExprUnary()
 
-		lhs = ast.CreateBinaryOperatorNode(cur, signature.GetOperator(cur), lhs, rhs)
+		lhs = parser.finish(start, ast.CreateBinaryOperatorNode(cur, signature.GetOperator(cur), lhs, rhs))
 	}
 
 	return lhs
 }
 
-func (parser *Parser) isExprNotStart(tok *token.Token) bool {
-	return tok.TokenType == token.LNOT || parser.isExprFinalStart(tok)
+func (parser *Parser) isExprUnaryStart(tok *token.Token) bool {
+	return tok.TokenType == token.LNOT || tok.TokenType == token.SUB || parser.isExprFinalStart(tok)
 }
 
-func (parser *Parser) parseExprNot() ast.Node {
-	if !parser.isExprNotStart(parser.cur) {
-		return parser.syntaxErrorNode("logic not expression")
+func (parser *Parser) parseExprUnary() ast.Node {
+	if !parser.isExprUnaryStart(parser.cur) {
+		return parser.syntaxErrorNode("unary expression")
 	}
 
-	if parser.cur.TokenType == token.LNOT {
+	if parser.cur.TokenType == token.LNOT || parser.cur.TokenType == token.SUB {
+		start := parser.mark()
 		currentToken := parser.cur
 
 		parser.read()
 
-		expr := parser.parseExprNot()
+		if currentToken.TokenType == token.SUB && parser.isNumberLiteralStart(parser.cur) {
+			return parser.finish(start, parser.parseNegativeLiteral(currentToken))
+		}
+
+		expr := parser.parseExprUnary()
 
-		node := ast.CreateUnaryOperatorNode(currentToken, signature.GetOperator(currentToken), expr)
+		node := ast.CreateUnaryOperatorNode(currentToken, signature.GetUnaryOperator(currentToken), expr)
 
-		return node
+		return parser.finish(start, node)
 	}
 
 	return parser.parseExprFinal()
 }
 
+func (parser *Parser) isNumberLiteralStart(tok *token.Token) bool {
+	return parser.isIntegerLiteralStart(tok) || parser.isFLoatLiteralStart(tok)
+}
+
+// parseNegativeLiteral parses a number literal negated by minus as a single negative literal, so
+// that the lowest int, whose magnitude does not fit in int, remains expressible
+func (parser *Parser) parseNegativeLiteral(minus *token.Token) ast.Node {
+	literal := *parser.cur
+	literal.Raw = minus.Raw + literal.Raw
+	literal.Locator = minus.Locator
+
+	parser.cur = &literal
+
+	if literal.TokenType == token.INT_LITERAL {
+		return parser.parserInt()
+	}
+
+	return parser.parseFloat()
+}
+
 func (parser *Parser) isExprFinalStart(tok *token.Token) bool {
 	return parser.isExprParenStart(tok) || parser.isLiteralStart(tok)
 }
@@ -743,7 +879,44 @@ func (parser *Parser) parseExprFinal() ast.Node {
 		return parser.parseExprParen()
 	}
 
-	return parser.parseLiteral()
+	start := parser.mark()
+
+	literal := parser.parseLiteral()
+
+	if _, ok := literal.(*ast.IdentifierNode); ok && parser.isCallStart(parser.cur) {
+		return parser.parseCall(start, literal)
+	}
+
+	return literal
+}
+
+func (parser *Parser) isCallStart(tok *token.Token) bool {
+	return tok.TokenType == token.LEFT_PAREN
+}
+
+// parseCall parses the arguments of a call of callee, which starts at start
+func (parser *Parser) parseCall(start int, callee ast.Node) ast.Node {
+	if !parser.isCallStart(parser.cur) {
+		return parser.syntaxErrorNode("function call")
+	}
+
+	node := ast.CreateCallNode(callee)
+
+	parser.expect(token.LEFT_PAREN)
+
+	if parser.cur.TokenType != token.RIGHT_PAREN {
+		node.AddArg(parser.parseExpr())
+
+		for parser.cur.TokenType == token.COMMA {
+			parser.read()
+
+			node.AddArg(parser.parseExpr())
+		}
+	}
+
+	parser.expect(token.RIGHT_PAREN)
+
+	return parser.finish(start, node)
 }
 
 func (parser *Parser) isExprParenStart(tok *token.Token) bool {
@@ -777,12 +950,14 @@ func (parser *Parser) parseTypeLiteral() ast.Node {
 		return parser.syntaxErrorNode("type literal")
 	}
 
+	start := parser.mark()
+
 	var node ast.TypeLiteralNode
 	node.BaseNode = ast.CreateBaseNode(parser.cur, nil)
 
 	parser.read()
 
-	return &node
+	return parser.finish(start, &node)
 }
 
 func (parser *Parser) parseLiteral() ast.Node {
@@ -810,12 +985,21 @@ func (parser *Parser) parserInt() ast.Node {
 		return parser.syntaxErrorNode("int")
 	}
 
+	start := parser.mark()
+
+	if parser.cur.Error != "" {
+		return parser.malformedNode("int", parser.cur.Error)
+	}
+
 	var node ast.IntegerNode
-	node.Init(parser.cur)
+
+	if err := node.Init(parser.cur); err != nil {
+		return parser.malformedNode("int", err.Error())
+	}
 
 	parser.read()
 
-	return &node
+	return parser.finish(start, &node)
 }
 
 func (parser *Parser) parseFloat() ast.Node {
@@ -823,12 +1007,21 @@ func (parser *Parser) parseFloat() ast.Node {
 		return parser.syntaxErrorNode("float")
 	}
 
+	start := parser.mark()
+
+	if parser.cur.Error != "" {
+		return parser.malformedNode("float", parser.cur.Error)
+	}
+
 	var node ast.FloatNode
-	node.Init(parser.cur)
+
+	if err := node.Init(parser.cur); err != nil {
+		return parser.malformedNode("float", err.Error())
+	}
 
 	parser.read()
 
-	return &node
+	return parser.finish(start, &node)
 }
 
 func (parser *Parser) parseString() ast.Node {
@@ -836,12 +1029,18 @@ func (parser *Parser) parseString() ast.Node {
 		return parser.syntaxErrorNode("string")
 	}
 
+	start := parser.mark()
+
+	if parser.cur.Error != "" {
+		return parser.malformedNode("string", parser.cur.Error)
+	}
+
 	var node ast.StringNode
 	node.Init(parser.cur)
 
 	parser.read()
 
-	return &node
+	return parser.finish(start, &node)
 }
 
 func (parser *Parser) parseCharacter() ast.Node {
@@ -849,12 +1048,18 @@ func (parser *Parser) parseCharacter() ast.Node {
 		return parser.syntaxErrorNode("character")
 	}
 
+	start := parser.mark()
+
+	if parser.cur.Error != "" {
+		return parser.malformedNode("character", parser.cur.Error)
+	}
+
 	var node ast.CharacterNode
 	node.Init(parser.cur)
 
 	parser.read()
 
-	return &node
+	return parser.finish(start, &node)
 }
 
 func (parser *Parser) parseBool() ast.Node {
@@ -862,12 +1067,14 @@ func (parser *Parser) parseBool() ast.Node {
 		return parser.syntaxErrorNode("boolean")
 	}
 
+	start := parser.mark()
+
 	var node ast.BooleanNode
 	node.Init(parser.cur)
 
 	parser.read()
 
-	return &node
+	return parser.finish(start, &node)
 }
 
 func (parser *Parser) parseIdentifier() ast.Node {
@@ -875,11 +1082,13 @@ func (parser *Parser) parseIdentifier() ast.Node {
 		return parser.syntaxErrorNode("identifier")
 	}
 
+	start := parser.mark()
+
 	node := ast.IdentifierNode{BaseNode: ast.CreateBaseNode(parser.cur, nil)}
 
 	parser.read()
 
-	return &node
+	return parser.finish(start, &node)
 }
 
 func (parser *Parser) syntaxErrorNode(expected string) ast.Node {
@@ -893,6 +1102,22 @@ func (parser *Parser) syntaxErrorNode(expected string) ast.Node {
 	return &node
 }
 
+// malformedNode reports why the current token is not the literal expected, and skips it
+func (parser *Parser) malformedNode(expected string, message string) ast.Node {
+	var node ast.ErrorNode
+	node.BaseNode = ast.CreateBaseNode(parser.cur, nil)
+
+	node.Expected = expected
+
+	parser.logger.Log(parser.cur.GetErrorLocation(), message)
+
+	start := parser.mark()
+
+	parser.read()
+
+	return parser.finish(start, &node)
+}
+
 func (parser *Parser) isLiteralStart(tok *token.Token) bool {
 	return parser.isIntegerLiteralStart(tok) ||
 		parser.isFLoatLiteralStart(tok) ||
@@ -935,6 +1160,10 @@ func (parser *Parser) read() {
 	}
 
 	parser.cur = next
+
+	if parser.cst != nil {
+		parser.cst.record(next)
+	}
 }
 
 func (parser *Parser) expect(tokenTypes ...token.Type) {
@@ -1,9 +1,11 @@
 package parser
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/signature"
 	"github.com/carlcui/expressive/token"
 )
 
@@ -87,15 +89,23 @@ func TestParseIntegerLiteral(t *testing.T) {
 	toks := []*token.Token{
 		{TokenType: token.INT_LITERAL, Raw: "123"},
 		{TokenType: token.INT_LITERAL, Raw: "0"},
-		{TokenType: token.INT_LITERAL, Raw: "-1"},
-		{TokenType: token.INT_LITERAL, Raw: "-0"},
+		{TokenType: token.INT_LITERAL, Raw: "0x1F"},
+		{TokenType: token.INT_LITERAL, Raw: "0b1010"},
+		{TokenType: token.INT_LITERAL, Raw: "0o17"},
+		{TokenType: token.INT_LITERAL, Raw: "017"},
+		{TokenType: token.INT_LITERAL, Raw: "1_000_000"},
+		{TokenType: token.INT_LITERAL, Raw: "2147483647"},
 	}
 
 	expectedVals := []int{
 		123,
 		0,
-		-1,
-		0,
+		31,
+		10,
+		15,
+		17,
+		1000000,
+		2147483647,
 	}
 
 	for index, tok := range toks {
@@ -111,23 +121,121 @@ func TestParseIntegerLiteral(t *testing.T) {
 	}
 }
 
+func TestParseIntegerLiteralOutOfRange(t *testing.T) {
+	exprs := [][]*token.Token{
+		{{TokenType: token.INT_LITERAL, Raw: "2147483648"}},
+		{{TokenType: token.SUB, Raw: "-"}, {TokenType: token.INT_LITERAL, Raw: "2147483649"}},
+		{{TokenType: token.INT_LITERAL, Raw: "0x1_0000_0000"}},
+	}
+
+	for _, toks := range exprs {
+		parser := initParserWithMockTokens(toks)
+
+		if node, ok := parser.parseExpr().(*ast.ErrorNode); !ok || parser.logger.ErrorsCount() != 1 {
+			reportTestError("Expecting a range error", node, t)
+		}
+	}
+}
+
+func TestParseNegativeLiteral(t *testing.T) {
+	tests := []struct {
+		literal  *token.Token
+		expected interface{}
+	}{
+		{&token.Token{TokenType: token.INT_LITERAL, Raw: "1"}, -1},
+		{&token.Token{TokenType: token.INT_LITERAL, Raw: "0"}, 0},
+		{&token.Token{TokenType: token.INT_LITERAL, Raw: "0x1F"}, -31},
+		{&token.Token{TokenType: token.INT_LITERAL, Raw: "2147483648"}, -2147483648},
+		{&token.Token{TokenType: token.FLOAT_LITERAL, Raw: "1.0"}, float32(-1)},
+		{&token.Token{TokenType: token.FLOAT_LITERAL, Raw: "123.5"}, float32(-123.5)},
+		{&token.Token{TokenType: token.FLOAT_LITERAL, Raw: "0.0"}, float32(0)},
+	}
+
+	for _, test := range tests {
+		parser := initParserWithMockTokens([]*token.Token{{TokenType: token.SUB, Raw: "-"}, test.literal})
+
+		node := parser.parseExpr()
+
+		var val interface{}
+
+		switch literal := node.(type) {
+		case *ast.IntegerNode:
+			val = literal.Val
+		case *ast.FloatNode:
+			val = literal.Val
+		}
+
+		if val != test.expected || parser.logger.ErrorsCount() > 0 {
+			reportTestError(fmt.Sprintf("Expecting -%v to be the negative literal %v", test.literal.Raw, test.expected), node, t)
+		}
+	}
+}
+
+func TestParseUnaryMinus(t *testing.T) {
+	subtraction := initParserWithMockTokens([]*token.Token{
+		{TokenType: token.IDENTIFIER, Raw: "a"},
+		{TokenType: token.SUB, Raw: "-"},
+		{TokenType: token.INT_LITERAL, Raw: "1"},
+	})
+
+	if node, ok := subtraction.parseExpr().(*ast.BinaryOperatorNode); !ok || node.Operator != signature.SUBTRACT {
+		reportTestError("Expecting a-1 to be a subtraction", node, t)
+	}
+
+	negation := initParserWithMockTokens([]*token.Token{
+		{TokenType: token.SUB, Raw: "-"},
+		{TokenType: token.IDENTIFIER, Raw: "a"},
+		{TokenType: token.MUL, Raw: "*"},
+		{TokenType: token.INT_LITERAL, Raw: "2"},
+	})
+
+	if node, ok := negation.parseExpr().(*ast.BinaryOperatorNode); !ok || node.Lhs.(*ast.UnaryOperatorNode).Operator != signature.NEGATE {
+		reportTestError("Expecting -a*2 to multiply the negation of a", node, t)
+	}
+
+	doubleNegation := initParserWithMockTokens([]*token.Token{
+		{TokenType: token.SUB, Raw: "-"},
+		{TokenType: token.SUB, Raw: "-"},
+		{TokenType: token.INT_LITERAL, Raw: "1"},
+	})
+
+	if node, ok := doubleNegation.parseExpr().(*ast.UnaryOperatorNode); !ok || node.Operator != signature.NEGATE || node.Expr.(*ast.IntegerNode).Val != -1 {
+		reportTestError("Expecting - -1 to negate the literal -1", node, t)
+	}
+}
+
+func TestParseMalformedLiteral(t *testing.T) {
+	toks := []*token.Token{
+		{TokenType: token.INT_LITERAL, Raw: "0x", Error: "hexadecimal literal has no digits"},
+		{TokenType: token.SEMI},
+	}
+
+	parser := initParserWithMockTokens(toks)
+
+	if node, ok := parser.parserInt().(*ast.ErrorNode); !ok || parser.logger.ErrorsCount() != 1 || parser.cur.TokenType != token.SEMI {
+		reportTestError("Expecting the error of the token to be reported once, and the token skipped", node, t)
+	}
+}
+
 func TestParseFloatLiteral(t *testing.T) {
 	toks := []*token.Token{
 		{TokenType: token.FLOAT_LITERAL, Raw: "123.123"},
 		{TokenType: token.FLOAT_LITERAL, Raw: "123.0"},
-		{TokenType: token.FLOAT_LITERAL, Raw: "-1.0"},
-		{TokenType: token.FLOAT_LITERAL, Raw: "-123.5"},
-		{TokenType: token.FLOAT_LITERAL, Raw: "-0.6"},
-		{TokenType: token.FLOAT_LITERAL, Raw: "-0.0"},
+		{TokenType: token.FLOAT_LITERAL, Raw: "0.6"},
+		{TokenType: token.FLOAT_LITERAL, Raw: "0.0"},
+		{TokenType: token.FLOAT_LITERAL, Raw: ".5"},
+		{TokenType: token.FLOAT_LITERAL, Raw: "2.5e3"},
+		{TokenType: token.FLOAT_LITERAL, Raw: "1_000.5"},
 	}
 
 	expectedVals := []float32{
 		123.123,
 		123,
-		-1,
-		-123.5,
-		-0.6,
+		0.6,
 		0,
+		0.5,
+		2500,
+		1000.5,
 	}
 
 	for index, tok := range toks {
@@ -224,18 +332,24 @@ func TestParseStringLiteral(t *testing.T) {
 		{TokenType: token.STRING_LITERAL, Raw: "\"\\t\""},
 		{TokenType: token.STRING_LITERAL, Raw: "\"\\0\""},
 		{TokenType: token.STRING_LITERAL, Raw: "\"\\\\\""},
+		{TokenType: token.STRING_LITERAL, Raw: "\"\\\\n\""},
+		{TokenType: token.STRING_LITERAL, Raw: "\"\\x41\\r\""},
+		{TokenType: token.STRING_LITERAL, Raw: "\"\\u{1F600}\""},
 	}
 
 	expectedVals := []string{
 		"abc",
 		"",
 		"  ",
-		"\\\"",
-		"\\'",
+		"\"",
+		"'",
+		"\n",
+		"\t",
+		"\x00",
+		"\\",
 		"\\n",
-		"\\t",
-		"\\0",
-		"\\\\",
+		"A\r",
+		"😀",
 	}
 
 	for index, tok := range toks {
@@ -1136,3 +1250,148 @@ func TestSwitchStmtWithEmpty(t *testing.T) {
 
 	parseWithMockTokens(toks, shouldHaveError(t))
 }
+
+func TestCallStmt(t *testing.T) {
+	/*
+		log(f(), 1 + x);
+	*/
+	toks := []*token.Token{
+		{TokenType: token.IDENTIFIER, Raw: "log"},
+		{TokenType: token.LEFT_PAREN},
+		{TokenType: token.IDENTIFIER, Raw: "f"},
+		{TokenType: token.LEFT_PAREN},
+		{TokenType: token.RIGHT_PAREN},
+		{TokenType: token.COMMA},
+		{TokenType: token.INT_LITERAL, Raw: "1"},
+		{TokenType: token.ADD},
+		{TokenType: token.IDENTIFIER, Raw: "x"},
+		{TokenType: token.RIGHT_PAREN},
+		{TokenType: token.SEMI},
+		{TokenType: token.EOF},
+	}
+
+	root := parseWithMockTokens(toks, shouldHaveNoError(t))
+
+	call, ok := root.(*ast.ProgramNode).Chilren[0].(*ast.CallNode)
+
+	if !ok || call.Name() != "log" || len(call.Args) != 2 || !call.IsStatement() {
+		t.Fatalf("expected a call statement of log with 2 arguments, got %s", ast.SerializeAst(root))
+	}
+
+	inner, ok := call.Args[0].(*ast.CallNode)
+
+	if !ok || inner.Name() != "f" || len(inner.Args) != 0 || inner.IsStatement() {
+		t.Errorf("expected a call of f without arguments, got %s", ast.SerializeAst(call.Args[0]))
+	}
+
+	if _, ok := call.Args[1].(*ast.BinaryOperatorNode); !ok {
+		t.Errorf("expected an addition, got %s", ast.SerializeAst(call.Args[1]))
+	}
+}
+
+func TestCallOfParenthesizedExpression(t *testing.T) {
+	/*
+		let a = (f)(1);
+	*/
+	toks := []*token.Token{
+		{TokenType: token.LET},
+		{TokenType: token.IDENTIFIER, Raw: "a"},
+		{TokenType: token.ASSIGN},
+		{TokenType: token.LEFT_PAREN},
+		{TokenType: token.IDENTIFIER, Raw: "f"},
+		{TokenType: token.RIGHT_PAREN},
+		{TokenType: token.LEFT_PAREN},
+		{TokenType: token.INT_LITERAL, Raw: "1"},
+		{TokenType: token.RIGHT_PAREN},
+		{TokenType: token.SEMI},
+		{TokenType: token.EOF},
+	}
+
+	parseWithMockTokens(toks, shouldHaveError(t))
+}
+
+func TestExternStmt(t *testing.T) {
+	/*
+		extern func printf(format: string, ...) -> int;
+	*/
+	toks := []*token.Token{
+		{TokenType: token.EXTERN},
+		{TokenType: token.FUNC},
+		{TokenType: token.IDENTIFIER, Raw: "printf"},
+		{TokenType: token.LEFT_PAREN},
+		{TokenType: token.IDENTIFIER, Raw: "format"},
+		{TokenType: token.COLON},
+		{TokenType: token.STRING_KEYWORD},
+		{TokenType: token.COMMA},
+		{TokenType: token.ELLIPSIS},
+		{TokenType: token.RIGHT_PAREN},
+		{TokenType: token.ARROW},
+		{TokenType: token.INT_KEYWORD},
+		{TokenType: token.SEMI},
+		{TokenType: token.EOF},
+	}
+
+	root := parseWithMockTokens(toks, shouldHaveNoError(t))
+
+	extern, ok := root.(*ast.ProgramNode).Chilren[0].(*ast.ExternNode)
+
+	if !ok || extern.Name() != "printf" || len(extern.ParamTypes) != 1 || !extern.Variadic || extern.ResultType == nil {
+		t.Fatalf("expected a variadic extern printf with 1 parameter and a result, got %s", ast.SerializeAst(root))
+	}
+
+	if extern.ParamNames[0].GetToken().Raw != "format" || extern.ParamTypes[0].GetToken().TokenType != token.STRING_KEYWORD {
+		t.Errorf("expected the parameter format: string, got %s", ast.SerializeAst(extern))
+	}
+}
+
+func TestExternStmtWithoutResult(t *testing.T) {
+	/*
+		extern func srand(seed: int, other: int);
+	*/
+	toks := []*token.Token{
+		{TokenType: token.EXTERN},
+		{TokenType: token.FUNC},
+		{TokenType: token.IDENTIFIER, Raw: "srand"},
+		{TokenType: token.LEFT_PAREN},
+		{TokenType: token.IDENTIFIER, Raw: "seed"},
+		{TokenType: token.COLON},
+		{TokenType: token.INT_KEYWORD},
+		{TokenType: token.COMMA},
+		{TokenType: token.IDENTIFIER, Raw: "other"},
+		{TokenType: token.COLON},
+		{TokenType: token.INT_KEYWORD},
+		{TokenType: token.RIGHT_PAREN},
+		{TokenType: token.SEMI},
+		{TokenType: token.EOF},
+	}
+
+	root := parseWithMockTokens(toks, shouldHaveNoError(t))
+
+	extern, ok := root.(*ast.ProgramNode).Chilren[0].(*ast.ExternNode)
+
+	if !ok || len(extern.ParamTypes) != 2 || extern.Variadic || extern.ResultType != nil {
+		t.Errorf("expected an extern srand with 2 parameters and no result, got %s", ast.SerializeAst(root))
+	}
+}
+
+func TestExternStmtWithParamAfterEllipsis(t *testing.T) {
+	/*
+		extern func f(..., a: int);
+	*/
+	toks := []*token.Token{
+		{TokenType: token.EXTERN},
+		{TokenType: token.FUNC},
+		{TokenType: token.IDENTIFIER, Raw: "f"},
+		{TokenType: token.LEFT_PAREN},
+		{TokenType: token.ELLIPSIS},
+		{TokenType: token.COMMA},
+		{TokenType: token.IDENTIFIER, Raw: "a"},
+		{TokenType: token.COLON},
+		{TokenType: token.INT_KEYWORD},
+		{TokenType: token.RIGHT_PAREN},
+		{TokenType: token.SEMI},
+		{TokenType: token.EOF},
+	}
+
+	parseWithMockTokens(toks, shouldHaveError(t))
+}
@@ -0,0 +1,326 @@
+// Package repl evaluates expressive statements and expressions as they are entered. Entries
+// share a global scope, so that the variables declared by an entry are seen by the following
+// ones; an entry that fails declares nothing.
+package repl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/codegen"
+	"github.com/carlcui/expressive/input"
+	"github.com/carlcui/expressive/internal/frontend"
+	"github.com/carlcui/expressive/interp"
+	"github.com/carlcui/expressive/logger"
+	"github.com/carlcui/expressive/parser"
+	"github.com/carlcui/expressive/scanner"
+	"github.com/carlcui/expressive/semanticAnalyser"
+	"github.com/carlcui/expressive/symbolTable"
+	"github.com/carlcui/expressive/token"
+	"github.com/carlcui/expressive/typing"
+)
+
+const (
+	prompt             = "> "
+	continuationPrompt = "... "
+
+	// sourceName locates errors in entries
+	sourceName = "<repl>"
+)
+
+const commandsUsage = ":type <expr>, :ast <entry>, :ir or :reset"
+
+// Session evaluates entries, writing what they print, their results and their errors to out
+type Session struct {
+	out io.Writer
+
+	scope       *symbolTable.Scope
+	interpreter interp.Interpreter
+	program     []string                      // the statements entered without error, making up the program of :ir
+	unassigned  map[*symbolTable.Binding]bool // variables declared without initializers, not assigned yet
+
+	pending []string // lines of an entry whose braces are not balanced yet
+}
+
+// NewSession creates a session without variables
+func NewSession(out io.Writer) *Session {
+	session := &Session{out: out}
+	session.Reset()
+
+	return session
+}
+
+// Reset forgets the variables and the statements entered so far
+func (session *Session) Reset() {
+	session.scope = symbolTable.CreateScope(nil)
+	session.interpreter.Init(session.out)
+	session.program = nil
+	session.unassigned = nil
+	session.pending = nil
+}
+
+// Run prompts for entries and evaluates them until in ends. An entry spans several lines while
+// its braces are not balanced.
+func (session *Session) Run(in io.Reader) error {
+	lines := bufio.NewScanner(in)
+
+	for {
+		if len(session.pending) == 0 {
+			fmt.Fprint(session.out, prompt)
+		} else {
+			fmt.Fprint(session.out, continuationPrompt)
+		}
+
+		if !lines.Scan() {
+			break
+		}
+
+		session.Line(lines.Text())
+	}
+
+	fmt.Fprintln(session.out)
+
+	// an entry left unbalanced is evaluated, to report what it misses
+	if len(session.pending) > 0 {
+		session.evaluate(strings.Join(session.pending, "\n"))
+		session.pending = nil
+	}
+
+	return lines.Err()
+}
+
+// Line reads a line of input, and evaluates the entry it completes
+func (session *Session) Line(line string) {
+	if len(session.pending) == 0 && strings.HasPrefix(strings.TrimSpace(line), ":") {
+		session.command(strings.TrimSpace(line))
+		return
+	}
+
+	session.pending = append(session.pending, line)
+	entry := strings.Join(session.pending, "\n")
+
+	if unbalancedBraces(entry) {
+		return
+	}
+
+	session.pending = nil
+
+	if strings.TrimSpace(entry) != "" {
+		session.evaluate(entry)
+	}
+}
+
+// command runs a meta-command, e.g. ":type a + 1"
+func (session *Session) command(line string) {
+	name := line
+	argument := ""
+
+	if space := strings.IndexAny(line, " \t"); space >= 0 {
+		name = line[:space]
+		argument = strings.TrimSpace(line[space:])
+	}
+
+	switch name {
+	case ":type":
+		var frontendLogger logger.Buffer
+
+		expr := parseExpression(argument, &frontendLogger)
+
+		if session.analyzeAside(expr, &frontendLogger) {
+			fmt.Fprintln(session.out, expr.GetTyping())
+		}
+	case ":ast":
+		var frontendLogger logger.Buffer
+
+		node, _ := parseEntry(argument, &frontendLogger)
+
+		if session.analyzeAside(node, &frontendLogger) {
+			fmt.Fprintf(session.out, "%s\n", ast.SerializeAst(node))
+		}
+	case ":ir":
+		session.printIr()
+	case ":reset":
+		session.Reset()
+	default:
+		fmt.Fprintf(session.out, "unknown command %v, expecting %v\n", name, commandsUsage)
+	}
+}
+
+// analyzeAside analyses node in a scope of its own, seeing the variables of the session without
+// declaring any, and reports the errors logged. It returns false if there is any.
+func (session *Session) analyzeAside(node ast.Node, frontendLogger *logger.Buffer) bool {
+	if frontendLogger.ErrorsCount() == 0 {
+		semanticAnalyser.AnalyzeInScope(node, session.scope.CreateSubScope(), frontendLogger)
+	}
+
+	frontendLogger.Flush(session.out)
+
+	return frontendLogger.ErrorsCount() == 0
+}
+
+// evaluate runs the statements of entry, or prints the value of entry if it is an expression
+func (session *Session) evaluate(entry string) {
+	var frontendLogger logger.Buffer
+
+	defer frontendLogger.Flush(session.out)
+
+	node, source := parseEntry(entry, &frontendLogger)
+
+	if frontendLogger.ErrorsCount() > 0 {
+		return
+	}
+
+	declared := session.scope.Identifiers()
+
+	unassigned := semanticAnalyser.AnalyzeEntry(node, session.scope, session.unassigned, &frontendLogger)
+
+	if frontendLogger.ErrorsCount() > 0 {
+		session.undeclare(declared)
+		return
+	}
+
+	if _, ok := node.(*ast.ProgramNode); !ok {
+		value, ok := session.interpreter.Eval(node, &frontendLogger)
+
+		if ok {
+			fmt.Fprintf(session.out, "%v: %v\n", formatValue(value, node.GetTyping()), node.GetTyping())
+			session.unassigned = unassigned
+		}
+
+		return
+	}
+
+	if !session.interpreter.Exec(node, &frontendLogger) {
+		session.undeclare(declared)
+		return
+	}
+
+	session.program = append(session.program, source)
+	session.unassigned = unassigned
+}
+
+// undeclare removes the variables declared in the global scope since declared were
+func (session *Session) undeclare(declared []string) {
+	kept := make(map[string]bool)
+
+	for _, identifier := range declared {
+		kept[identifier] = true
+	}
+
+	for _, identifier := range session.scope.Identifiers() {
+		if !kept[identifier] {
+			session.scope.RemoveBinding(identifier)
+		}
+	}
+}
+
+// printIr prints the llvm IR of the statements entered so far, as a program
+func (session *Session) printIr() {
+	var frontendLogger logger.Buffer
+
+	defer frontendLogger.Flush(session.out)
+
+	var reader input.Reader
+	reader.Init(strings.NewReader(strings.Join(session.program, "\n")), sourceName, &frontendLogger)
+
+	root := frontend.Analyze(&reader, nil, &frontendLogger)
+
+	if frontendLogger.ErrorsCount() > 0 {
+		return
+	}
+
+	irCode := codegen.Generate(root, &frontendLogger)
+
+	if frontendLogger.ErrorsCount() == 0 {
+		fmt.Fprint(session.out, irCode)
+	}
+}
+
+// parseEntry parses entry as an expression if it is one, or else as statements. The final
+// semicolon of a statement may be left out. It returns the node parsed, and the source of the
+// statements.
+func parseEntry(entry string, frontendLogger logger.Logger) (ast.Node, string) {
+	var expressionLogger logger.Buffer
+
+	if expr := parseExpression(entry, &expressionLogger); expressionLogger.ErrorsCount() == 0 {
+		return expr, entry
+	}
+
+	trimmed := strings.TrimSpace(entry)
+
+	if !strings.HasSuffix(trimmed, ";") && !strings.HasSuffix(trimmed, "}") {
+		var completedLogger logger.Buffer
+
+		completed := entry + ";"
+
+		if program := newParser(completed, &completedLogger).Parse(); completedLogger.ErrorsCount() == 0 {
+			return program, completed
+		}
+	}
+
+	return newParser(entry, frontendLogger).Parse(), entry
+}
+
+func parseExpression(src string, frontendLogger logger.Logger) ast.Node {
+	return newParser(src, frontendLogger).ParseExpression()
+}
+
+func newParser(src string, frontendLogger logger.Logger) *parser.Parser {
+	var reader input.Reader
+	reader.Init(strings.NewReader(src), sourceName, frontendLogger)
+
+	var s scanner.ExpressiveScanner
+	s.Init(&reader)
+
+	var p parser.Parser
+	p.Init(&s, frontendLogger)
+
+	return &p
+}
+
+// unbalancedBraces tells whether src opens more braces than it closes, ignoring those in
+// literals and comments
+func unbalancedBraces(src string) bool {
+	var reader input.Reader
+	reader.Init(strings.NewReader(src), sourceName, nil)
+
+	var s scanner.ExpressiveScanner
+	s.Init(&reader)
+
+	depth := 0
+
+	for tok := s.Next(); tok.TokenType != token.EOF; tok = s.Next() {
+		switch tok.TokenType {
+		case token.LEFT_CURLY_BRACE:
+			depth++
+		case token.RIGHT_CURLY_BRACE:
+			depth--
+		}
+	}
+
+	return depth > 0
+}
+
+// formatValue writes value like a literal of its type
+func formatValue(value interp.Value, valueTyping typing.Typing) string {
+	switch v := value.(type) {
+	case int32:
+		return strconv.Itoa(int(v))
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		if valueTyping.Equals(typing.CHAR) && len([]rune(v)) == 1 {
+			return strconv.QuoteRune([]rune(v)[0])
+		}
+
+		return strconv.Quote(v)
+	}
+
+	return fmt.Sprint(value)
+}
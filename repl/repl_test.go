@@ -0,0 +1,139 @@
+package repl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// run enters lines in a new session, and returns its output without prompts
+func run(lines ...string) string {
+	var out bytes.Buffer
+
+	session := NewSession(&out)
+
+	for _, line := range lines {
+		session.Line(line)
+	}
+
+	return out.String()
+}
+
+func TestEntries(t *testing.T) {
+	tests := []struct {
+		lines    []string
+		expected string
+	}{
+		{[]string{"1 + 2 * 3"}, "7: INT\n"},
+		{[]string{"let a = 5;", "a * 2", "a > 2 ? \"big\" : \"small\""}, "10: INT\n\"big\": STRING\n"},
+		{[]string{"let x = 1.5", "x", "'c'", "!true"}, "1.5: FLOAT\n'c': CHAR\nfalse: BOOL\n"},
+		{[]string{"let a = 1;", "a += 2;", "a++", "a"}, "4: INT\n"},
+		{[]string{"let a = 3;", "if (a > 2) {", "    print \"%d\\n\", a;", "}"}, "3\n"},
+		{[]string{"for (let i = 0; i < 2; i++) {", "if (i > 0) { print \"}\"; }", "}", "print \"\\n\";"}, "}\n"},
+	}
+
+	for _, test := range tests {
+		if output := run(test.lines...); output != test.expected {
+			t.Errorf("Expecting %q to output\n%q\ngot\n%q", test.lines, test.expected, output)
+		}
+	}
+}
+
+func TestFailedEntriesDeclareNothing(t *testing.T) {
+	output := run(
+		"let a = 1; let b = a + \"x\";",
+		"a",
+		"let c = 2; let d = c / 0;",
+		"c",
+		"let a = 7;",
+		"a",
+	)
+
+	expected := []string{
+		"file <repl>: row 0, column 21: +(Addition) does not support operation on [INT STRING]",
+		"file <repl>: row 0, column 0: variable \"a\" used before declared",
+		"file <repl>: row 0, column 21: runtime error: integer division by zero",
+		"file <repl>: row 0, column 0: variable \"c\" used before declared",
+		"7: INT",
+	}
+
+	if output != strings.Join(expected, "\n")+"\n" {
+		t.Errorf("Expecting\n%v\ngot\n%v", strings.Join(expected, "\n"), output)
+	}
+}
+
+func TestAssignmentAcrossEntries(t *testing.T) {
+	output := run(
+		"let x: int",
+		"x",
+		"x = 1 / 0;",
+		"x + 1",
+		"x = 3;",
+		"x",
+		":reset",
+		"let y: int; y = 2;",
+		"y",
+	)
+
+	expected := []string{
+		"file <repl>: row 0, column 0: variable \"x\" is used before being assigned",
+		"file <repl>: row 0, column 6: runtime error: integer division by zero",
+		"file <repl>: row 0, column 0: variable \"x\" is used before being assigned",
+		"3: INT",
+		"2: INT",
+	}
+
+	if output != strings.Join(expected, "\n")+"\n" {
+		t.Errorf("Expecting\n%v\ngot\n%v", strings.Join(expected, "\n"), output)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	output := run("let a = 2;", ":type a > 1", ":type b", ":ast -a", ":ir", ":reset", "a", ":quit")
+
+	for _, expected := range []string{
+		"BOOL\n",
+		"variable \"b\" used before declared\n",
+		"\"NodeType\": \"unary operator\"",
+		"define i32 @main()",
+		"store i32 2",
+		"variable \"a\" used before declared\n",
+		"unknown command :quit, expecting :type <expr>, :ast <entry>, :ir or :reset\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expecting output to contain %q, got\n%v", expected, output)
+		}
+	}
+
+	if strings.Contains(output, "a: INT") {
+		t.Errorf("Expecting :type and :ast not to evaluate, got\n%v", output)
+	}
+}
+
+func TestRunPrompts(t *testing.T) {
+	var out bytes.Buffer
+
+	if err := NewSession(&out).Run(strings.NewReader("while (false) {\n}\n1\nif (true) {\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "> ... > 1: INT\n> ... \nfile <repl>: row 0, column 11: expected [}]\n"
+
+	if out.String() != expected {
+		t.Errorf("Expecting\n%q\ngot\n%q", expected, out.String())
+	}
+}
+
+func TestExternFunctions(t *testing.T) {
+	output := run("extern func abs(x: int) -> int;", ":type abs(-2)", ":ir", "abs(-2)")
+
+	for _, expected := range []string{
+		"INT\n",
+		"declare i32 @abs(i32 %0)",
+		"runtime error: extern function abs cannot be called by the interpreter\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expecting output to contain %q, got\n%v", expected, output)
+		}
+	}
+}
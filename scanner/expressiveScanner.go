@@ -1,7 +1,9 @@
 package scanner
 
 import (
+	"fmt"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/carlcui/expressive/input"
 	"github.com/carlcui/expressive/locator"
@@ -14,18 +16,66 @@ type ExpressiveScanner struct {
 
 	cur    string          // current string buffer
 	curLoc locator.Locator // current location
+
+	lossless bool // whitespace and comments are kept as the trivia of the token they precede
 }
 
 // Init initializes scanner, setting current string buffer to empty string
 func (scanner *ExpressiveScanner) Init(input input.Input) {
 	scanner.input = input
 	scanner.cur = ""
+	scanner.curLoc = nil
+	scanner.lossless = false
+}
+
+// InitLossless initializes a scanner that keeps the whitespace and comments preceding a token as
+// its trivia, instead of skipping whitespace and returning comments, so that the source can be
+// reprinted exactly from the tokens. The carriage returns of "\r\n" line endings and a leading
+// byte order mark are kept as whitespace if source is an input.Lossless.
+func (scanner *ExpressiveScanner) InitLossless(source input.Input) {
+	scanner.Init(source)
+	scanner.lossless = true
+
+	if lossless, ok := source.(input.Lossless); ok {
+		lossless.ReadLossless()
+	}
 }
 
 // Next returns the next valid token, or ILLEGAL if parsing failed
 func (scanner *ExpressiveScanner) Next() *token.Token {
-	scanner.skipWhitespaces()
+	if !scanner.lossless {
+		scanner.skipWhitespaces()
+
+		return scanner.next()
+	}
+
+	trivia := make([]*token.Token, 0)
+
+	if scanner.curLoc == nil { // the first token, which a byte order mark may precede
+		if byteOrderMark := scanner.parseByteOrderMark(); byteOrderMark != nil {
+			trivia = append(trivia, byteOrderMark)
+		}
+	}
+
+	for {
+		if whitespace := scanner.parseWhitespaces(); whitespace != nil {
+			trivia = append(trivia, whitespace)
+		}
+
+		tok := scanner.next()
+
+		if tok.TokenType != token.COMMENT {
+			tok.Trivia = trivia
 
+			return tok
+		}
+
+		trivia = append(trivia, tok)
+	}
+}
+
+// next returns the token starting at the current character
+func (scanner *ExpressiveScanner) next() *token.Token {
 	scanner.curLoc = scanner.input.CurLoc()
 
 	tok := token.EOFToken(scanner.curLoc)
@@ -42,7 +92,7 @@ func (scanner *ExpressiveScanner) Next() *token.Token {
 		}
 
 		switch {
-		case isDigit(ch) || isMinus(ch):
+		case isDigit(ch) || (isDot(ch) && !scanner.input.IsEOF() && isDigit(scanner.input.Peek())):
 			tok = scanner.parseNumber(ch)
 			break
 		case isIdentifierStart(ch):
@@ -106,46 +156,167 @@ func (scanner *ExpressiveScanner) tryParseComment(cur rune) *token.Token {
 /*
 	a number is either:
 
-	1. an integer: -?[0-9]+
-	2. a float: -?[0-9]+.[0-9]+
+	1. an integer: decimal|0[xX]hexadecimal|0[bB]binary|0[oO]octal
+	2. a float: (decimal.decimal?|.decimal|decimal)exponent?, with a dot or an exponent
+
+	digits may be separated by _, as in 1_000_000, and exponent := [eE][+-]?decimal
+
+	a number is unsigned: a minus before it is the negation operator
 
 */
 func (scanner *ExpressiveScanner) parseNumber(first rune) *token.Token {
 
-	if isMinus(first) {
-		if scanner.input.IsEOF() || !isDigit(scanner.input.Peek()) { // cannot be a negative number
-			return scanner.parseOperator()
+	if first == '0' && !scanner.input.IsEOF() {
+		if base, ok := bases[unicode.ToLower(scanner.input.Peek())]; ok {
+			return scanner.parsePrefixedInt(base)
 		}
 	}
 
+	tokenType := token.INT_LITERAL
+
+	if isDot(first) { // a float without integer part
+		tokenType = token.FLOAT_LITERAL
+	}
+
 	// parse int
-	scanner.appendConsequentDigits()
+	malformed := scanner.appendDigits(decimal, !isDot(first))
 
 	// parse float
-	if !scanner.input.IsEOF() && isDot(scanner.input.Peek()) {
+	if malformed == "" && tokenType == token.INT_LITERAL && !scanner.input.IsEOF() && isDot(scanner.input.Peek()) {
+		tokenType = token.FLOAT_LITERAL
 		scanner.cur += string(scanner.input.NextRune())
 
-		scanner.appendConsequentDigits()
+		malformed = scanner.appendDigits(decimal, false)
+	}
+
+	if malformed == "" && !scanner.input.IsEOF() && isExponent(scanner.input.Peek()) {
+		tokenType = token.FLOAT_LITERAL
+		malformed = scanner.appendExponent()
+	}
+
+	if malformed == "" {
+		malformed = scanner.appendInvalidSuffix(decimal)
+	}
+
+	if malformed != "" {
+		return token.MalformedToken(tokenType, scanner.cur, scanner.curLoc, malformed)
+	}
+
+	return &token.Token{TokenType: tokenType, Raw: scanner.cur, Locator: scanner.curLoc}
+}
+
+// base of an int literal, with the digits it accepts
+type base struct {
+	name    string
+	isDigit func(rune) bool
+}
+
+var decimal = base{"decimal", func(ch rune) bool { return '0' <= ch && ch <= '9' }}
+
+// bases of the int literals with a prefix, by the letter following 0
+var bases = map[rune]base{
+	'x': {"hexadecimal", func(ch rune) bool {
+		return decimal.isDigit(ch) || ('a' <= unicode.ToLower(ch) && unicode.ToLower(ch) <= 'f')
+	}},
+	'b': {"binary", func(ch rune) bool { return ch == '0' || ch == '1' }},
+	'o': {"octal", func(ch rune) bool { return '0' <= ch && ch <= '7' }},
+}
+
+// parsePrefixedInt parses the int following 0 and the prefix of its base
+func (scanner *ExpressiveScanner) parsePrefixedInt(base base) *token.Token {
+	scanner.cur += string(scanner.input.NextRune())
+	prefixed := len(scanner.cur)
+
+	malformed := scanner.appendDigits(base, false)
+	digits := len(scanner.cur) > prefixed
+	suffix := scanner.appendInvalidSuffix(base)
+
+	// a digit of another base is reported as invalid, whether or not it follows digits of base
+	if malformed == "" {
+		malformed = suffix
+	}
+
+	if malformed == "" && !digits {
+		malformed = base.name + " literal has no digits"
+	}
 
-		return &token.Token{TokenType: token.FLOAT_LITERAL, Raw: scanner.cur, Locator: scanner.curLoc}
+	if malformed != "" {
+		return token.MalformedToken(token.INT_LITERAL, scanner.cur, scanner.curLoc, malformed)
 	}
 
 	return &token.Token{TokenType: token.INT_LITERAL, Raw: scanner.cur, Locator: scanner.curLoc}
 }
 
-func (scanner *ExpressiveScanner) appendConsequentDigits() {
-	for !scanner.input.IsEOF() && isDigit(scanner.input.Peek()) {
+// appendDigits appends the digits of base that follow, and the underscores separating them.
+// afterDigit tells whether the last rune appended is a digit. It returns why the digits are
+// malformed, if they are.
+func (scanner *ExpressiveScanner) appendDigits(base base, afterDigit bool) string {
+	malformed := ""
+
+	for !scanner.input.IsEOF() && (base.isDigit(scanner.input.Peek()) || isUnderscore(scanner.input.Peek())) {
 		ch := scanner.input.NextRune()
 
+		if isUnderscore(ch) && !afterDigit && malformed == "" {
+			malformed = "'_' must separate successive digits"
+		}
+
+		afterDigit = !isUnderscore(ch)
 		scanner.cur += string(ch)
 	}
+
+	if !afterDigit && malformed == "" && isUnderscore(lastRune(scanner.cur)) {
+		malformed = "'_' must separate successive digits"
+	}
+
+	return malformed
+}
+
+func (scanner *ExpressiveScanner) appendExponent() string {
+	scanner.cur += string(scanner.input.NextRune())
+
+	if !scanner.input.IsEOF() && (isMinus(scanner.input.Peek()) || scanner.input.Peek() == '+') {
+		scanner.cur += string(scanner.input.NextRune())
+	}
+
+	if scanner.input.IsEOF() || !decimal.isDigit(scanner.input.Peek()) {
+		return "exponent has no digits"
+	}
+
+	return scanner.appendDigits(decimal, false)
+}
+
+// appendInvalidSuffix appends the letters and digits following a literal, which would
+// otherwise be scanned as another token, e.g. 2 in 0b102. It returns why they are invalid, if
+// there are any.
+func (scanner *ExpressiveScanner) appendInvalidSuffix(base base) string {
+	malformed := ""
+
+	for !scanner.input.IsEOF() && (isIdentifierStart(scanner.input.Peek()) || unicode.IsDigit(scanner.input.Peek())) {
+		ch := scanner.input.NextRune()
+
+		if malformed == "" && unicode.IsDigit(ch) {
+			malformed = fmt.Sprintf("invalid digit %q in %v literal", ch, base.name)
+		} else if malformed == "" {
+			malformed = fmt.Sprintf("invalid character %q in %v literal", ch, base.name)
+		}
+
+		scanner.cur += string(ch)
+	}
+
+	return malformed
+}
+
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+
+	return r
 }
 
 /*
 	identifier := [_a-zA-Z][_0-9a-zA-Z]*
 */
 func (scanner *ExpressiveScanner) parseIdentifier() *token.Token {
-	for !scanner.input.IsEOF() && !isWhitespace(scanner.input.Peek()) && (isIdentifierStart(scanner.input.Peek()) || isDigit(scanner.input.Peek())) {
+	for !scanner.input.IsEOF() && !isWhitespace(scanner.input.Peek()) && (isIdentifierStart(scanner.input.Peek()) || unicode.IsDigit(scanner.input.Peek())) {
 		scanner.cur += string(scanner.input.NextRune())
 	}
 
@@ -161,17 +332,18 @@ func (scanner *ExpressiveScanner) parseIdentifier() *token.Token {
 }
 
 /*
-	stringLiteral := "[^"^\n]*"
+	stringLiteral := "([^"^\n^\\]|\escape)*", with the escapes of token.DecodeEscape
 */
 func (scanner *ExpressiveScanner) parseStringLiteral() *token.Token {
 	loc := scanner.curLoc
 
-	for !scanner.input.IsEOF() && !isReturn(scanner.input.Peek()) && !isDoubleQuote(scanner.input.Peek()) {
+	malformed := ""
+	var malformedLoc locator.Locator
 
-		ok := scanner.tryParseEscapeControlSequence()
+	for !scanner.input.IsEOF() && !isReturn(scanner.input.Peek()) && !isDoubleQuote(scanner.input.Peek()) {
 
-		if !ok {
-			return token.IllegalToken(scanner.cur, loc)
+		if err, errLoc := scanner.appendCharacter(); err != "" && malformed == "" {
+			malformed, malformedLoc = err, errLoc
 		}
 	}
 
@@ -182,11 +354,15 @@ func (scanner *ExpressiveScanner) parseStringLiteral() *token.Token {
 
 	scanner.cur += string(scanner.input.NextRune()) // "
 
+	if malformed != "" {
+		return scanner.malformedEscape(token.STRING_LITERAL, loc, malformed, malformedLoc)
+	}
+
 	return &token.Token{TokenType: token.STRING_LITERAL, Raw: scanner.cur, Locator: loc}
 }
 
 /*
-	charLiteral := '[^'^\n]|(\asciiEscapeControl)'
+	charLiteral := '([^'^\n^\\]|\escape)'
 */
 func (scanner *ExpressiveScanner) parseCharacterLiteral() *token.Token {
 	loc := scanner.curLoc
@@ -195,11 +371,7 @@ func (scanner *ExpressiveScanner) parseCharacterLiteral() *token.Token {
 		return token.IllegalToken(scanner.cur, loc)
 	}
 
-	ok := scanner.tryParseEscapeControlSequence()
-
-	if !ok {
-		return token.IllegalToken(scanner.cur, loc)
-	}
+	malformed, malformedLoc := scanner.appendCharacter()
 
 	if scanner.input.IsEOF() || !isSingleQuote(scanner.input.Peek()) {
 		return token.IllegalToken(scanner.cur, loc)
@@ -207,6 +379,10 @@ func (scanner *ExpressiveScanner) parseCharacterLiteral() *token.Token {
 
 	scanner.cur += string(scanner.input.NextRune())
 
+	if malformed != "" {
+		return scanner.malformedEscape(token.CHAR_LITERAL, loc, malformed, malformedLoc)
+	}
+
 	return &token.Token{TokenType: token.CHAR_LITERAL, Raw: scanner.cur, Locator: loc}
 }
 
@@ -235,24 +411,100 @@ func (scanner *ExpressiveScanner) skipWhitespaces() {
 	}
 }
 
-func (scanner *ExpressiveScanner) tryParseEscapeControlSequence() bool {
+// parseWhitespaces returns the whitespace at the current character as a token, or nil if there is
+// none
+func (scanner *ExpressiveScanner) parseWhitespaces() *token.Token {
+	loc := scanner.input.CurLoc()
+	whitespace := ""
+
+	for !scanner.input.IsEOF() && isWhitespace(scanner.input.Peek()) {
+		whitespace += string(scanner.input.NextRune())
+	}
+
+	if whitespace == "" {
+		return nil
+	}
+
+	return &token.Token{TokenType: token.WHITESPACE, Raw: whitespace, Locator: loc}
+}
+
+// parseByteOrderMark returns the byte order mark at the current character as whitespace, or nil
+// if there is none
+func (scanner *ExpressiveScanner) parseByteOrderMark() *token.Token {
+	loc := scanner.input.CurLoc()
+
+	if scanner.input.IsEOF() || scanner.input.Peek() != input.ByteOrderMark {
+		return nil
+	}
+
+	return &token.Token{TokenType: token.WHITESPACE, Raw: string(scanner.input.NextRune()), Locator: loc}
+}
+
+// appendCharacter appends the next character of a string or character literal, or its escape
+// sequence. It returns why the escape sequence is invalid, and where it starts, if it is.
+func (scanner *ExpressiveScanner) appendCharacter() (string, locator.Locator) {
+	loc := scanner.input.CurLoc()
+
 	cur := scanner.input.NextRune()
 	scanner.cur += string(cur)
 
-	if isBackSlash(cur) {
-		if scanner.input.IsEOF() || !isControlSequenceCharacter(scanner.input.Peek()) {
-			return false
+	if !isBackSlash(cur) {
+		return "", nil
+	}
+
+	if _, _, err := token.DecodeEscape(scanner.appendEscapeSequence()); err != "" {
+		return err, loc
+	}
+
+	return "", nil
+}
+
+// appendEscapeSequence appends and returns the escape sequence following a backslash, which ends
+// with the line: \x is followed by hexadecimal digits, and \u by hexadecimal digits in braces
+func (scanner *ExpressiveScanner) appendEscapeSequence() string {
+	start := len(scanner.cur)
+
+	if scanner.input.IsEOF() || isReturn(scanner.input.Peek()) {
+		return ""
+	}
+
+	first := scanner.input.NextRune()
+	scanner.cur += string(first)
+
+	switch first {
+	case 'x':
+		for i := 0; i < 2 && !scanner.input.IsEOF() && token.IsHexDigit(scanner.input.Peek()); i++ {
+			scanner.cur += string(scanner.input.NextRune())
+		}
+	case 'u':
+		if scanner.input.IsEOF() || scanner.input.Peek() != '{' {
+			break
 		}
 
 		scanner.cur += string(scanner.input.NextRune())
+
+		for !scanner.input.IsEOF() && token.IsHexDigit(scanner.input.Peek()) {
+			scanner.cur += string(scanner.input.NextRune())
+		}
+
+		if !scanner.input.IsEOF() && scanner.input.Peek() == '}' {
+			scanner.cur += string(scanner.input.NextRune())
+		}
 	}
 
-	return true
+	return scanner.cur[start:]
+}
+
+func (scanner *ExpressiveScanner) malformedEscape(tokenType token.Type, loc locator.Locator, malformed string, malformedLoc locator.Locator) *token.Token {
+	tok := token.MalformedToken(tokenType, scanner.cur, loc, malformed)
+	tok.ErrorLocator = malformedLoc
+
+	return tok
 }
 
 // char helpers
 func isDigit(ch rune) bool {
-	return unicode.IsDigit(ch)
+	return decimal.isDigit(ch)
 }
 
 func isWhitespace(ch rune) bool {
@@ -287,9 +539,8 @@ func isAsterisk(ch rune) bool {
 	return ch == '*'
 }
 
-// implemented partially
-func isControlSequenceCharacter(ch rune) bool {
-	return ch == 'n' || ch == '0' || ch == 't' || ch == '\'' || ch == '"' || ch == '\\'
+func isExponent(ch rune) bool {
+	return ch == 'e' || ch == 'E'
 }
 
 func isReturn(ch rune) bool {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/carlcui/expressive/input"
+	"github.com/carlcui/expressive/logger"
 	"github.com/carlcui/expressive/token"
 )
 
@@ -21,7 +22,10 @@ func TestWithRealFiles(t *testing.T) {
 		fileName := file.Name()
 
 		var fileInput input.File
-		fileInput.Init(dirName, fileName)
+
+		if err := fileInput.Init(dirName, fileName, &logger.StdError{}); err != nil {
+			panic(err)
+		}
 
 		var s ExpressiveScanner
 		s.Init(&fileInput)
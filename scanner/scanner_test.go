@@ -2,6 +2,7 @@ package scanner
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/carlcui/expressive/input"
@@ -25,8 +26,12 @@ func TestScanInteger(t *testing.T) {
 		"123",
 		"456",
 		"0",
-		"-5",
-		"-0",
+		"0x1F",
+		"0XfF",
+		"0b1010",
+		"0o17",
+		"1_000_000",
+		"0x7fff_ffff",
 	}
 
 	for _, actual := range actuals {
@@ -41,8 +46,11 @@ func TestScanFloat(t *testing.T) {
 		"0.3",
 		"000.2",
 		"0123.5",
-		"-5.5",
-		"-0.123",
+		".5",
+		"1e-9",
+		"2.5E+3",
+		"5.e3",
+		"1_000.000_1",
 	}
 
 	for _, actual := range actuals {
@@ -50,6 +58,51 @@ func TestScanFloat(t *testing.T) {
 	}
 }
 
+func TestScanMinusBeforeNumber(t *testing.T) {
+	testScanningTokens("a-1 -2.5 --3", []token.Token{
+		{TokenType: token.IDENTIFIER, Raw: "a"},
+		{TokenType: token.SUB, Raw: "-"},
+		{TokenType: token.INT_LITERAL, Raw: "1"},
+		{TokenType: token.SUB, Raw: "-"},
+		{TokenType: token.FLOAT_LITERAL, Raw: "2.5"},
+		{TokenType: token.DECREMENT, Raw: "--"},
+		{TokenType: token.INT_LITERAL, Raw: "3"},
+	}, t)
+}
+
+func TestScanMalformedNumber(t *testing.T) {
+	errors := map[string]string{
+		"0x":     "hexadecimal literal has no digits",
+		"0b102":  "invalid digit '2' in binary literal",
+		"0b":     "binary literal has no digits",
+		"0b2":    "invalid digit '2' in binary literal",
+		"0o8":    "invalid digit '8' in octal literal",
+		"0o78":   "invalid digit '8' in octal literal",
+		"0xg":    "invalid character 'g' in hexadecimal literal",
+		"1__000": "'_' must separate successive digits",
+		"1_":     "'_' must separate successive digits",
+		"1._5":   "'_' must separate successive digits",
+		"1e":     "exponent has no digits",
+		"1e+":    "exponent has no digits",
+		"12abc":  "invalid character 'a' in decimal literal",
+		"0xfg":   "invalid character 'g' in hexadecimal literal",
+		"1٣":     "invalid digit '٣' in decimal literal",
+	}
+
+	for raw, expected := range errors {
+		var input input.StringInput
+
+		input.Init(raw)
+
+		var scanner ExpressiveScanner
+		scanner.Init(&input)
+
+		if tok := scanner.Next(); tok.Raw != raw || tok.Error != expected {
+			t.Errorf("Scanning %v: expecting %q, got %v with error %q", raw, expected, tok, tok.Error)
+		}
+	}
+}
+
 func TestScanStringLiteralSuccess(t *testing.T) {
 	actuals := []string{
 		"\"abc\"",
@@ -61,6 +114,9 @@ func TestScanStringLiteralSuccess(t *testing.T) {
 		"\"\\t\"",
 		"\"\\0\"",
 		"\"\\\\\"",
+		"\"\\r\"",
+		"\"\\x41\\x7f\"",
+		"\"\\u{1F600}\\u{0}\"",
 	}
 
 	for _, actual := range actuals {
@@ -68,6 +124,35 @@ func TestScanStringLiteralSuccess(t *testing.T) {
 	}
 }
 
+func TestScanInvalidEscape(t *testing.T) {
+	errors := map[string]string{
+		`"ab \q"`:        `at 4: unknown escape sequence \q`,
+		`"\x4"`:          `at 1: \x escape must have 2 hexadecimal digits, as in \x41`,
+		`"\x80"`:         `at 1: \x80 escape is not ASCII, up to \x7F; escape Unicode characters as in \u{80}`,
+		`"\u1F600"`:      `at 1: \u escape must have hexadecimal digits in braces, as in \u{1F600}`,
+		`"\u{1F600"`:     `at 1: \u escape must have hexadecimal digits in braces, as in \u{1F600}`,
+		`"\u{}"`:         `at 1: \u escape must have 1 to 6 hexadecimal digits`,
+		`"\u{D800}"`:     `at 1: \u{D800} is not a Unicode character`,
+		`"ok\n\n \z \y"`: `at 8: unknown escape sequence \z`,
+		`'\x'`:           `at 1: \x escape must have 2 hexadecimal digits, as in \x41`,
+	}
+
+	for raw, expected := range errors {
+		var input input.StringInput
+
+		input.Init(raw)
+
+		var scanner ExpressiveScanner
+		scanner.Init(&input)
+
+		tok := scanner.Next()
+
+		if actual := tok.GetErrorLocation() + ": " + tok.Error; tok.Raw != raw || actual != expected {
+			t.Errorf("Scanning %v: expecting %v, got %v with error %v", raw, expected, tok, actual)
+		}
+	}
+}
+
 func TestScanCharacterLiteralSuccess(t *testing.T) {
 	actuals := []string{
 		"'a'",
@@ -117,6 +202,39 @@ func TestScanSingleComment(t *testing.T) {
 	}
 }
 
+func TestScanLosslessTrivia(t *testing.T) {
+	var input input.StringInput
+	input.Init("let a  = 1; // one\n/* end */\n")
+
+	var scanner ExpressiveScanner
+	scanner.InitLossless(&input)
+
+	text := ""
+	trivia := make([]string, 0)
+
+	for tok := scanner.Next(); ; tok = scanner.Next() {
+		for _, t := range tok.Trivia {
+			trivia = append(trivia, t.Raw)
+		}
+
+		text += tok.FullText()
+
+		if tok.TokenType == token.EOF {
+			break
+		}
+	}
+
+	if text != "let a  = 1; // one\n/* end */\n" {
+		t.Errorf("Expecting the tokens to reprint the source, got %q", text)
+	}
+
+	expected := []string{" ", "  ", " ", " ", "// one", "\n", "/* end */", "\n"}
+
+	if strings.Join(trivia, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expecting trivia %q, got %q", expected, trivia)
+	}
+}
+
 func testScanningOneToken(stringInput string, expected string, tokenType token.Type, t *testing.T) {
 	var input input.StringInput
 
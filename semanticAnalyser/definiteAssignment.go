@@ -0,0 +1,299 @@
+package semanticAnalyser
+
+import (
+	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/logger"
+	"github.com/carlcui/expressive/signature"
+	"github.com/carlcui/expressive/symbolTable"
+)
+
+// assignedSet is the set of variables definitely assigned at a point of the program. An
+// unreachable point, e.g. right after a break, has every variable assigned.
+type assignedSet struct {
+	unreachable bool
+	bindings    map[*symbolTable.Binding]bool
+}
+
+func newAssignedSet() *assignedSet {
+	return &assignedSet{bindings: make(map[*symbolTable.Binding]bool)}
+}
+
+func unreachableSet() *assignedSet {
+	return &assignedSet{unreachable: true}
+}
+
+func (set *assignedSet) copy() *assignedSet {
+	if set.unreachable {
+		return unreachableSet()
+	}
+
+	result := newAssignedSet()
+
+	for binding := range set.bindings {
+		result.bindings[binding] = true
+	}
+
+	return result
+}
+
+func (set *assignedSet) assign(binding *symbolTable.Binding) {
+	if !set.unreachable {
+		set.bindings[binding] = true
+	}
+}
+
+func (set *assignedSet) isAssigned(binding *symbolTable.Binding) bool {
+	return set.unreachable || set.bindings[binding]
+}
+
+// merge returns the variables assigned on both paths joining at a point
+func merge(set1 *assignedSet, set2 *assignedSet) *assignedSet {
+	if set1.unreachable {
+		return set2.copy()
+	}
+
+	if set2.unreachable {
+		return set1.copy()
+	}
+
+	result := newAssignedSet()
+
+	for binding := range set1.bindings {
+		if set2.bindings[binding] {
+			result.bindings[binding] = true
+		}
+	}
+
+	return result
+}
+
+// definiteAssignmentChecker reports variables declared without initializers that are read
+// before being assigned on some path. Assignments only add to the set of assigned variables,
+// so walking each loop body once is enough to reach a fixed point.
+type definiteAssignmentChecker struct {
+	logger    logger.Logger
+	unchecked map[*symbolTable.Binding]bool // bindings declared without initializers
+	breaks    []*[]*assignedSet             // sets at each break, one list per enclosing breakable statement
+}
+
+// CheckDefiniteAssignment runs over a program that has been analysed without error
+func CheckDefiniteAssignment(node ast.Node, logger logger.Logger) {
+	checkDefiniteAssignment(node, nil, logger)
+}
+
+// checkDefiniteAssignment checks node where the variables of unassigned, declared before it
+// without initializers, are not assigned yet. It returns the variables declared without
+// initializers that are still unassigned after node.
+func checkDefiniteAssignment(node ast.Node, unassigned map[*symbolTable.Binding]bool, logger logger.Logger) map[*symbolTable.Binding]bool {
+	checker := definiteAssignmentChecker{
+		logger:    logger,
+		unchecked: make(map[*symbolTable.Binding]bool),
+	}
+
+	for binding := range unassigned {
+		checker.unchecked[binding] = true
+	}
+
+	set := checker.checkStmt(node, newAssignedSet())
+	result := make(map[*symbolTable.Binding]bool)
+
+	for binding := range checker.unchecked {
+		if !set.isAssigned(binding) {
+			result[binding] = true
+		}
+	}
+
+	return result
+}
+
+// checkStmt returns the variables assigned after the statement, given those assigned before it
+func (checker *definiteAssignmentChecker) checkStmt(node ast.Node, set *assignedSet) *assignedSet {
+	switch stmt := node.(type) {
+	case nil:
+		return set
+	case *ast.ProgramNode:
+		for _, child := range stmt.Chilren {
+			set = checker.checkStmt(child, set)
+		}
+
+		return set
+	case *ast.BlockNode:
+		for _, child := range stmt.Stmts {
+			set = checker.checkStmt(child, set)
+		}
+
+		return set
+	case *ast.VariableDeclarationNode:
+		binding := stmt.Identifier.(*ast.IdentifierNode).GetBinding()
+
+		if stmt.Expr == nil {
+			checker.unchecked[binding] = true
+			return set
+		}
+
+		checker.checkExpr(stmt.Expr, set)
+		set.assign(binding)
+
+		return set
+	case *ast.AssignmentNode:
+		// a compound assignment reads the variable first
+		if stmt.Operator != signature.VOID_OPERATOR {
+			checker.checkExpr(stmt.LHS, set)
+		}
+
+		checker.checkExpr(stmt.RHS, set)
+
+		if identifier, ok := stmt.LHS.(*ast.IdentifierNode); ok {
+			set.assign(identifier.GetBinding())
+		}
+
+		return set
+	case *ast.IncDecNode:
+		checker.checkExpr(stmt.LHS, set)
+		return set
+	case *ast.PrintNode:
+		checker.checkExpr(stmt.StringExpr, set)
+
+		for _, arg := range stmt.Args {
+			checker.checkExpr(arg, set)
+		}
+
+		return set
+	case *ast.IfStmtNode:
+		return checker.checkIfStmt(stmt, set)
+	case *ast.WhileStmtNode:
+		checker.checkExpr(stmt.ConditionExpr, set)
+
+		return checker.checkLoop(stmt.ConditionExpr, set, func(set *assignedSet) *assignedSet {
+			return checker.checkStmt(stmt.Block, set)
+		})
+	case *ast.ForStmtNode:
+		set = checker.checkStmt(stmt.InitializationStmt, set)
+		checker.checkExpr(stmt.ConditionExpr, set)
+
+		return checker.checkLoop(stmt.ConditionExpr, set, func(set *assignedSet) *assignedSet {
+			set = checker.checkStmt(stmt.Block, set)
+			return checker.checkStmt(stmt.IterationStmt, set)
+		})
+	case *ast.SwitchStmtNode:
+		return checker.checkSwitchStmt(stmt, set)
+	case *ast.BreakNode:
+		if len(checker.breaks) > 0 {
+			breaks := checker.breaks[len(checker.breaks)-1]
+			*breaks = append(*breaks, set.copy())
+		}
+
+		return unreachableSet()
+	default:
+		checker.checkExpr(node, set)
+		return set
+	}
+}
+
+func (checker *definiteAssignmentChecker) checkIfStmt(node *ast.IfStmtNode, set *assignedSet) *assignedSet {
+	result := unreachableSet()
+
+	for i, conditionExpr := range node.ConditionExprs {
+		checker.checkExpr(conditionExpr, set)
+
+		blockSet := checker.checkStmt(node.ConditionBlocks[i], set.copy())
+		result = merge(result, blockSet)
+	}
+
+	// without an else block, every condition may be false
+	elseSet := checker.checkStmt(node.ElseBlock, set.copy())
+
+	return merge(result, elseSet)
+}
+
+// checkLoop walks a loop body. A loop exits when its condition is false, which may be before
+// running the body at all, or at a break. A loop without condition, or with condition `true`,
+// only exits at a break.
+func (checker *definiteAssignmentChecker) checkLoop(conditionExpr ast.Node, set *assignedSet, checkBody func(set *assignedSet) *assignedSet) *assignedSet {
+	breaks := checker.pushBreaks()
+
+	checkBody(set.copy())
+
+	checker.popBreaks()
+
+	result := set.copy()
+
+	if literal, ok := conditionExpr.(*ast.BooleanNode); conditionExpr == nil || (ok && literal.Val) {
+		result = unreachableSet()
+	}
+
+	for _, breakSet := range *breaks {
+		result = merge(result, breakSet)
+	}
+
+	return result
+}
+
+// checkSwitchStmt walks the case blocks in order, as a case block that does not break falls
+// through to the next one
+func (checker *definiteAssignmentChecker) checkSwitchStmt(node *ast.SwitchStmtNode, set *assignedSet) *assignedSet {
+	checker.checkExpr(node.TestExpr, set)
+
+	for _, caseExpr := range node.CaseExprs {
+		checker.checkExpr(caseExpr, set)
+	}
+
+	breaks := checker.pushBreaks()
+
+	fallthroughSet := unreachableSet()
+
+	for _, caseBlock := range node.CaseBlocks {
+		fallthroughSet = checker.checkStmt(caseBlock, merge(set, fallthroughSet))
+	}
+
+	// without a default block, no case may match
+	result := merge(set, fallthroughSet)
+
+	if node.DefaultBlock != nil {
+		result = checker.checkStmt(node.DefaultBlock, result)
+	}
+
+	checker.popBreaks()
+
+	for _, breakSet := range *breaks {
+		result = merge(result, breakSet)
+	}
+
+	return result
+}
+
+func (checker *definiteAssignmentChecker) pushBreaks() *[]*assignedSet {
+	breaks := make([]*assignedSet, 0)
+	checker.breaks = append(checker.breaks, &breaks)
+
+	return &breaks
+}
+
+func (checker *definiteAssignmentChecker) popBreaks() {
+	checker.breaks = checker.breaks[:len(checker.breaks)-1]
+}
+
+// checkExpr reports variables read by an expression that may not have been assigned
+func (checker *definiteAssignmentChecker) checkExpr(node ast.Node, set *assignedSet) {
+	switch expr := node.(type) {
+	case *ast.IdentifierNode:
+		binding := expr.GetBinding()
+
+		if checker.unchecked[binding] && !set.isAssigned(binding) {
+			checker.logger.Log(expr.GetLocation(), "variable \""+expr.Tok.Raw+"\" is used before being assigned")
+		}
+	case *ast.UnaryOperatorNode:
+		checker.checkExpr(expr.Expr, set)
+	case *ast.BinaryOperatorNode:
+		checker.checkExpr(expr.Lhs, set)
+		checker.checkExpr(expr.Rhs, set)
+	case *ast.TernaryOperatorNode:
+		checker.checkExpr(expr.Expr1, set)
+		checker.checkExpr(expr.Expr2, set)
+		checker.checkExpr(expr.Expr3, set)
+	case *ast.CallNode:
+		for _, arg := range expr.Args {
+			checker.checkExpr(arg, set)
+		}
+	}
+}
@@ -2,12 +2,78 @@ package semanticAnalyser
 
 import (
 	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/host"
 	"github.com/carlcui/expressive/logger"
+	"github.com/carlcui/expressive/symbolTable"
 )
 
 func Analyze(node ast.Node, logger logger.Logger) {
+	AnalyzeInScope(node, nil, logger)
+}
+
+// AnalyzeWithHost analyses a program like Analyze, declaring the functions of the host in its
+// scope so that it can call them. Functions that cannot be declared are reported to logger.
+func AnalyzeWithHost(node ast.Node, functions []*host.Function, logger logger.Logger) {
+	analyze(node, nil, functions, nil, logger)
+}
+
+// AnalyzeInScope analyses node like Analyze, declaring its variables in scope instead of a new
+// program scope, so that it uses the variables declared there before, e.g. by the previous
+// entries of a repl. node is either a program or an expression.
+func AnalyzeInScope(node ast.Node, scope *symbolTable.Scope, logger logger.Logger) {
+	analyze(node, scope, nil, nil, logger)
+}
+
+// AnalyzeEntry analyses node like AnalyzeInScope, as an entry following others in scope: the
+// variables of unassigned, declared by the previous entries without initializers, are not
+// assigned yet. It returns the variables declared without initializers that are still
+// unassigned after node.
+func AnalyzeEntry(node ast.Node, scope *symbolTable.Scope, unassigned map[*symbolTable.Binding]bool, logger logger.Logger) map[*symbolTable.Binding]bool {
+	return analyze(node, scope, nil, unassigned, logger)
+}
+
+func analyze(node ast.Node, scope *symbolTable.Scope, functions []*host.Function, unassigned map[*symbolTable.Binding]bool, logger logger.Logger) map[*symbolTable.Binding]bool {
 	var visitor SemanticAnalysisVisitor
 	visitor.logger = logger
+	visitor.programScope = scope
+	visitor.functions = validFunctions(functions, logger)
+
+	if _, ok := node.(*ast.ProgramNode); !ok && scope != nil {
+		node.SetScope(scope)
+		visitor.declareFunctions(scope)
+	}
+
+	errorsCount := logger.ErrorsCount()
 
 	node.Accept(&visitor)
+
+	// bindings are only complete for a program without type errors
+	if logger.ErrorsCount() != errorsCount {
+		return unassigned
+	}
+
+	return checkDefiniteAssignment(node, unassigned, logger)
+}
+
+// validFunctions returns the functions that can be declared, reporting the others
+func validFunctions(functions []*host.Function, logger logger.Logger) []*host.Function {
+	valid := make([]*host.Function, 0, len(functions))
+	declared := make(map[string]bool)
+
+	for _, function := range functions {
+		if err := function.Validate(); err != nil {
+			logger.Log("", err.Error())
+			continue
+		}
+
+		if declared[function.Name] {
+			logger.Log("", "host function \""+function.Name+"\" is declared more than once")
+			continue
+		}
+
+		declared[function.Name] = true
+		valid = append(valid, function)
+	}
+
+	return valid
 }
@@ -6,21 +6,47 @@ import (
 	"testing"
 
 	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/host"
 	"github.com/carlcui/expressive/input"
 	"github.com/carlcui/expressive/logger"
 	"github.com/carlcui/expressive/parser"
 	"github.com/carlcui/expressive/scanner"
+	"github.com/carlcui/expressive/typing"
 )
 
+// hostFunctions are declared in the test programs
+var hostFunctions = []*host.Function{
+	newHostFunction("square", typing.INT, typing.INT),
+	newHostFunction("log", typing.VOID, typing.STRING),
+	newHostFunction("describe", typing.STRING, typing.INT, typing.FLOAT),
+	newHostFunction("now", typing.FLOAT),
+}
+
+func newHostFunction(name string, result typing.Typing, params ...typing.Typing) *host.Function {
+	return &host.Function{
+		Name:   name,
+		Params: params,
+		Result: result,
+		Call: func(args []host.Value) (host.Value, error) {
+			return nil, nil
+		},
+	}
+}
+
 func parseFile(dirName string, fileName string) ast.Node {
+	logger := newLogger()
+
 	var fileInput input.File
-	fileInput.Init(dirName, fileName)
+
+	if err := fileInput.Init(dirName, fileName, logger); err != nil {
+		panic(err)
+	}
 
 	var s scanner.ExpressiveScanner
 	s.Init(&fileInput)
 
 	var p parser.Parser
-	p.Init(&s, newLogger())
+	p.Init(&s, logger)
 
 	return p.Parse()
 }
@@ -35,7 +61,7 @@ func parseAndAnalyze(dirName string, fileName string, handleResult func(logger l
 
 	logger := newLogger()
 
-	Analyze(root, logger) // pass a new logger, assuming parsing is correct
+	AnalyzeWithHost(root, hostFunctions, logger) // pass a new logger, assuming parsing is correct
 
 	handleResult(logger)
 }
@@ -82,6 +108,43 @@ func TestAnalzingIncorrectPrograms(t *testing.T) {
 	}
 }
 
+func TestInvalidHostFunctions(t *testing.T) {
+	functions := []*host.Function{
+		newHostFunction("square", typing.INT, typing.INT),
+		newHostFunction("square", typing.FLOAT, typing.FLOAT),
+		newHostFunction("while", typing.VOID),
+		newHostFunction("nothing", typing.INT, typing.VOID),
+		{Name: "uncallable", Result: typing.VOID},
+	}
+
+	var buffer logger.Buffer
+
+	valid := validFunctions(functions, &buffer)
+
+	if len(valid) != 1 || valid[0] != functions[0] {
+		t.Errorf("expected only the first function to be valid, got %v", valid)
+	}
+
+	expected := []string{
+		`: host function "square" is declared more than once`,
+		`: host function "while": name is not an identifier`,
+		`: host function "nothing": parameter 1 has type VOID`,
+		`: host function "uncallable": Call is nil`,
+	}
+
+	messages := buffer.Messages()
+
+	if len(messages) != len(expected) {
+		t.Fatalf("expected %v errors, got %q", len(expected), messages)
+	}
+
+	for i, message := range messages {
+		if message != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], message)
+		}
+	}
+}
+
 func TestParticularFile(t *testing.T) {
 	t.Skip("for local debugging only")
 
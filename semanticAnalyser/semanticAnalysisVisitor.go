@@ -2,8 +2,10 @@ package semanticAnalyser
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/carlcui/expressive/ast"
+	"github.com/carlcui/expressive/host"
 	"github.com/carlcui/expressive/logger"
 	"github.com/carlcui/expressive/signature"
 	"github.com/carlcui/expressive/symbolTable"
@@ -13,13 +15,43 @@ import (
 
 // SemanticAnalysisVisitor is the general semantic analyser using visitor pattern
 type SemanticAnalysisVisitor struct {
-	logger logger.Logger
+	logger       logger.Logger
+	programScope *symbolTable.Scope // the scope of the program, created when nil
+	functions    []*host.Function   // the host functions declared in the program scope
+	signatures   signature.Mapping  // the signatures of the functions declared, by name
 }
 
 // VisitEnterProgramNode creates program scope
 func (visitor *SemanticAnalysisVisitor) VisitEnterProgramNode(node *ast.ProgramNode) {
-	scope := symbolTable.CreateScope(nil)
+	scope := visitor.programScope
+
+	if scope == nil {
+		scope = symbolTable.CreateScope(nil)
+	}
+
 	node.SetScope(scope)
+
+	visitor.declareFunctions(scope)
+}
+
+// declareFunctions declares the functions of the host in scope, and collects the signatures of
+// the functions of scope and the scopes enclosing it. They keep those declared by the programs
+// analysed in them before, e.g. the previous entries of a repl.
+func (visitor *SemanticAnalysisVisitor) declareFunctions(scope *symbolTable.Scope) {
+	for _, function := range visitor.functions {
+		binding := scope.CreateBindingCannotBeShadowed(function.Name, nil, function.Type())
+		binding.IsVariable = false
+	}
+
+	visitor.signatures = signature.NewMapping()
+
+	for ; scope != nil; scope = scope.BaseScope {
+		for _, name := range scope.Identifiers() {
+			if functionType, ok := scope.FindBinding(name).GetTyping().(*typing.FunctionType); ok {
+				visitor.signatures.Add(name, signature.CreateFunctionSignature(functionType))
+			}
+		}
+	}
 }
 
 // VisitLeaveProgramNode closes program scope
@@ -202,6 +234,128 @@ func (visitor *SemanticAnalysisVisitor) VisitLeavePrintNode(node *ast.PrintNode)
 	node.SetTyping(typing.VOID)
 }
 
+func (visitor *SemanticAnalysisVisitor) VisitEnterCallNode(node *ast.CallNode) {
+
+}
+
+// VisitLeaveCallNode resolves the function called, and checks the arguments against its
+// signature
+func (visitor *SemanticAnalysisVisitor) VisitLeaveCallNode(node *ast.CallNode) {
+	callee := node.Callee.(*ast.IdentifierNode)
+	name := node.Name()
+
+	binding := callee.FindVariableBinding()
+
+	if binding == nil {
+		node.SetTyping(typing.ERROR_TYPE)
+		visitor.log(callee.GetLocation(), "function \""+name+"\" is not declared")
+		return
+	}
+
+	functionType, ok := binding.GetTyping().(*typing.FunctionType)
+
+	if !ok {
+		node.SetTyping(typing.ERROR_TYPE)
+		visitor.log(callee.GetLocation(), "\""+name+"\" is not a function")
+		return
+	}
+
+	callee.SetTyping(functionType)
+	callee.SetBinding(binding)
+	binding.MarkRead()
+
+	argTypings := make([]typing.Typing, len(node.Args))
+
+	for i, arg := range node.Args {
+		argTypings[i] = arg.GetTyping()
+
+		// the error has already been reported
+		if argTypings[i] == typing.ERROR_TYPE {
+			node.SetTyping(typing.ERROR_TYPE)
+			return
+		}
+	}
+
+	resultTyping := visitor.signatures.ResultTyping(name, argTypings...)
+
+	if resultTyping == typing.ERROR_TYPE {
+		node.SetTyping(typing.ERROR_TYPE)
+		visitor.log(node.GetLocation(), "function \""+name+"\" takes "+typingList(functionType.Params, functionType.Variadic)+", but got "+typingList(argTypings, false))
+		return
+	}
+
+	if resultTyping == typing.VOID && !node.IsStatement() {
+		node.SetTyping(typing.ERROR_TYPE)
+		visitor.log(node.GetLocation(), "function \""+name+"\" returns no value")
+		return
+	}
+
+	node.SetTyping(resultTyping)
+}
+
+func (visitor *SemanticAnalysisVisitor) VisitEnterExternNode(node *ast.ExternNode) {
+
+}
+
+// VisitLeaveExternNode declares the function in the program scope, where calls following the
+// declaration are checked against its signature like those of the functions of the host
+func (visitor *SemanticAnalysisVisitor) VisitLeaveExternNode(node *ast.ExternNode) {
+	identifier := node.Identifier.(*ast.IdentifierNode)
+	name := node.Name()
+
+	if _, ok := node.Parent.(*ast.ProgramNode); !ok {
+		node.SetTyping(typing.ERROR_TYPE)
+		visitor.log(node.GetLocation(), "function \""+name+"\" must be declared at the top level of the program")
+		return
+	}
+
+	paramTypings := make([]typing.Typing, len(node.ParamTypes))
+	paramNames := make(map[string]bool)
+
+	for i, paramType := range node.ParamTypes {
+		paramTypings[i] = paramType.GetTyping()
+		paramName := node.ParamNames[i].GetToken().Raw
+
+		if paramNames[paramName] {
+			node.SetTyping(typing.ERROR_TYPE)
+			visitor.log(node.ParamNames[i].GetLocation(), "parameter \""+paramName+"\" has already been declared")
+			return
+		}
+
+		paramNames[paramName] = true
+	}
+
+	var resultTyping typing.Typing = typing.VOID
+
+	if node.ResultType != nil {
+		resultTyping = node.ResultType.GetTyping()
+	}
+
+	scope := node.GetLocalScope()
+
+	if scope.VariableDeclared(name) {
+		node.SetTyping(typing.ERROR_TYPE)
+		visitor.log(identifier.GetLocation(), "function \""+name+"\" has already been declared")
+		return
+	}
+
+	functionType := typing.NewFunctionType(resultTyping, paramTypings...)
+
+	if node.Variadic {
+		functionType = typing.NewVariadicFunctionType(resultTyping, paramTypings...)
+	}
+
+	binding := scope.CreateBindingCannotBeShadowed(name, identifier.Tok.Locator, functionType)
+	binding.IsVariable = false
+
+	identifier.SetTyping(functionType)
+	identifier.SetBinding(binding)
+
+	visitor.signatures.Add(name, signature.CreateFunctionSignature(functionType))
+
+	node.SetTyping(typing.VOID)
+}
+
 func (visitor *SemanticAnalysisVisitor) VisitEnterIfStmtNode(node *ast.IfStmtNode) {
 
 }
@@ -387,8 +541,18 @@ func (visitor *SemanticAnalysisVisitor) VisitIdentifierNode(node *ast.Identifier
 		return
 	}
 
+	if _, ok := binding.GetTyping().(*typing.FunctionType); ok {
+		node.SetTyping(typing.ERROR_TYPE)
+		visitor.log(node.GetLocation(), "function \""+node.Tok.Raw+"\" can only be called")
+		return
+	}
+
 	node.SetTyping(binding.GetTyping())
 	node.SetBinding(binding)
+
+	if !node.IsBeingAssigned() {
+		binding.MarkRead()
+	}
 }
 
 // VisitBooleanNode do something
@@ -429,6 +593,22 @@ func (visitor *SemanticAnalysisVisitor) TypeCheckError(location string, key inte
 	visitor.log(location, err.Error())
 }
 
+// typingList writes typings like the parameters of a function, e.g. "(INT, STRING)", or
+// "(STRING, ...)" if any number of typings may follow them
+func typingList(typings []typing.Typing, variadic bool) string {
+	names := make([]string, 0, len(typings)+1)
+
+	for _, t := range typings {
+		names = append(names, t.String())
+	}
+
+	if variadic {
+		names = append(names, "...")
+	}
+
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
 func (visitor *SemanticAnalysisVisitor) log(location string, message string) {
 	visitor.logger.Log(location, message)
 }
@@ -27,6 +27,10 @@ func addBuiltInSignatures() {
 		CreateSignature(typing.INT, typing.INT, typing.INT),
 		CreateSignature(typing.FLOAT, typing.FLOAT, typing.FLOAT),
 	}
+	keyToSignatures[NEGATE] = []*Signature{
+		CreateSignature(typing.INT, typing.INT),
+		CreateSignature(typing.FLOAT, typing.FLOAT),
+	}
 	keyToSignatures[LOGIC_AND] = []*Signature{
 		CreateSignature(typing.BOOL, typing.BOOL, typing.BOOL),
 	}
@@ -7,12 +7,13 @@ import (
 )
 
 type Signature struct {
-	Params []typing.Typing
-	Result typing.Typing
+	Params   []typing.Typing
+	Variadic bool // whether any number of params follow those of Params
+	Result   typing.Typing
 }
 
 func (signature *Signature) Accepts(params ...typing.Typing) bool {
-	if len(signature.Params) != len(params) {
+	if len(params) < len(signature.Params) || !signature.Variadic && len(params) != len(signature.Params) {
 		return false
 	}
 
@@ -43,9 +44,32 @@ func HasSignature(key interface{}, params ...typing.Typing) bool {
 }
 
 func ResultTyping(key interface{}, params ...typing.Typing) typing.Typing {
+	return keyToSignatures.ResultTyping(key, params...)
+}
+
+// NewMapping creates a mapping without signatures, e.g. for the functions of a program
+func NewMapping() Mapping {
+	return make(Mapping)
+}
+
+// Add makes key accept signature, after the signatures it already accepts
+func (mapping Mapping) Add(key interface{}, signature *Signature) {
+	checkValidKey(key)
+
+	mapping[key] = append(mapping[key], signature)
+}
+
+// HasSignature tells whether a signature of key accepts params
+func (mapping Mapping) HasSignature(key interface{}, params ...typing.Typing) bool {
+	return mapping.ResultTyping(key, params...) != typing.ERROR_TYPE
+}
+
+// ResultTyping returns the result of the first signature of key accepting params, or
+// ERROR_TYPE if none does
+func (mapping Mapping) ResultTyping(key interface{}, params ...typing.Typing) typing.Typing {
 	checkValidKey(key)
 
-	signatures, ok := keyToSignatures[key]
+	signatures, ok := mapping[key]
 
 	if !ok {
 		return typing.ERROR_TYPE
@@ -80,8 +104,18 @@ func CreateSignature(result typing.Typing, params ...typing.Typing) *Signature {
 	}
 }
 
+// CreateFunctionSignature creates the signature calls of a function of functionType are checked
+// against
+func CreateFunctionSignature(functionType *typing.FunctionType) *Signature {
+	return &Signature{
+		Params:   functionType.Params,
+		Variadic: functionType.Variadic,
+		Result:   functionType.Result,
+	}
+}
+
 func init() {
-	keyToSignatures = make(Mapping)
+	keyToSignatures = NewMapping()
 
 	addBuiltInSignatures()
 }
@@ -11,6 +11,7 @@ const (
 	DIVIDE
 	MODULO
 	EXPONENTIATE
+	NEGATE
 	LOGIC_AND
 	LOGIC_NOT
 	LOGIC_OR
@@ -34,6 +35,7 @@ var operatorStrings = [...]string{
 	DIVIDE:            "/(Division)",
 	MODULO:            "%(Modulus)",
 	EXPONENTIATE:      "^^(Exponentiation)",
+	NEGATE:            "-(Negation)",
 	LOGIC_AND:         "&&(Logical and)",
 	LOGIC_NOT:         "!(Logical not)",
 	LOGIC_OR:          "||(Logical or)",
@@ -98,3 +100,15 @@ func GetOperator(tok *token.Token) Operator {
 		return ERROR_OPERATOR
 	}
 }
+
+// GetUnaryOperator returns the operator of a token prefixing an operand
+func GetUnaryOperator(tok *token.Token) Operator {
+	switch tok.TokenType {
+	case token.SUB:
+		return NEGATE
+	case token.LNOT:
+		return LOGIC_NOT
+	default:
+		return ERROR_OPERATOR
+	}
+}
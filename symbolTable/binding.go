@@ -11,16 +11,32 @@ type Binding struct {
 	CanBeShadowed bool
 	locator       locator.Locator
 	typing        typing.Typing
+	reads         int // number of times the value is read, counted by semantic analysis
 }
 
 func CreateBinding(locator locator.Locator, typing typing.Typing) *Binding {
-	return &Binding{true, true, locator, typing}
+	return &Binding{true, true, locator, typing, 0}
 }
 
 func CreateBindingCannotBeShadowed(locator locator.Locator, typing typing.Typing) *Binding {
-	return &Binding{true, false, locator, typing}
+	return &Binding{true, false, locator, typing, 0}
 }
 
 func (binding *Binding) GetTyping() typing.Typing {
 	return binding.typing
 }
+
+// MarkRead records that the value bound is read
+func (binding *Binding) MarkRead() {
+	binding.reads++
+}
+
+// IsRead tells if the value bound is ever read. Assigning to it, including compound assignment
+// and increment or decrement, does not count as reading it.
+func (binding *Binding) IsRead() bool {
+	return binding.reads > 0
+}
+
+func (binding *Binding) GetLocator() locator.Locator {
+	return binding.locator
+}
@@ -1,13 +1,16 @@
 package symbolTable
 
 import (
+	"sort"
 	"strconv"
+	"sync/atomic"
 
 	"github.com/carlcui/expressive/locator"
 	"github.com/carlcui/expressive/typing"
 )
 
-var nextScopeIndex = 0
+// nextScopeIndex is incremented atomically, so that programs can be analysed concurrently
+var nextScopeIndex int64 = -1
 
 // Scope is where variables live and can be referenced
 type Scope struct {
@@ -25,9 +28,7 @@ func CreateScope(baseScope *Scope) *Scope {
 
 	scope.symbolTable = &symbolTable
 	scope.BaseScope = baseScope
-	scope.scopeIndex = nextScopeIndex
-
-	nextScopeIndex++
+	scope.scopeIndex = int(atomic.AddInt64(&nextScopeIndex, 1))
 
 	return &scope
 }
@@ -54,10 +55,30 @@ func (scope *Scope) CreateBindingCannotBeShadowed(identifier string, locator loc
 	return binding
 }
 
+// RemoveBinding removes the binding of identifier from the scope, e.g. to undo a declaration
+// that is not kept
+func (scope *Scope) RemoveBinding(identifier string) {
+	scope.symbolTable.Uninstall(identifier)
+}
+
 func (scope *Scope) FindBinding(identifier string) *Binding {
 	return scope.symbolTable.Lookup(identifier)
 }
 
+// Identifiers returns the identifiers declared in the scope, without those of its base scopes,
+// in alphabetical order
+func (scope *Scope) Identifiers() []string {
+	identifiers := make([]string, 0, len(*scope.symbolTable))
+
+	for identifier := range *scope.symbolTable {
+		identifiers = append(identifiers, identifier)
+	}
+
+	sort.Strings(identifiers)
+
+	return identifiers
+}
+
 func (scope *Scope) VariableDeclared(identifier string) bool {
 	return scope.FindBinding(identifier) != nil
 }
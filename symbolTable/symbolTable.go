@@ -11,6 +11,10 @@ func (symbolTable *SymbolTable) Install(identifier string, binding *Binding) {
 	(*symbolTable)[identifier] = binding
 }
 
+func (symbolTable *SymbolTable) Uninstall(identifier string) {
+	delete(*symbolTable, identifier)
+}
+
 func (symbolTable *SymbolTable) Lookup(identifier string) *Binding {
 	binding, ok := (*symbolTable)[identifier]
 
@@ -14,9 +14,9 @@ for file in ./e2e/*.exp; do
     filename=$(basename $file)
     filenameWithoutExt="${filename%.*}"
 
-    expressive -d ./e2e -f $filename --outDir ./dist
+    expressive build -d ./e2e -f $filename --outDir ./dist
 
-    result=$($LLI_PATH ./dist/$filenameWithoutExt.s)
+    result=$($LLI_PATH ./dist/$filenameWithoutExt.ll)
 
     for expectedFile in ./e2e/*.txt; do
 
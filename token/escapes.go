@@ -0,0 +1,120 @@
+package token
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// escapes of a single character following a backslash in string and character literals. Besides
+// them, a literal may escape:
+//
+//	\xNN    the ASCII character of hexadecimal code NN, up to \x7F
+//	\u{N}   the Unicode character of hexadecimal code point N, of 1 to 6 digits, e.g. \u{1F600}
+var escapes = map[rune]rune{
+	'n':  '\n', // new line
+	't':  '\t', // tab
+	'r':  '\r', // carriage return
+	'0':  0,    // null character
+	'\\': '\\',
+	'"':  '"',
+	'\'': '\'',
+}
+
+// IsHexDigit tells whether ch is a hexadecimal digit
+func IsHexDigit(ch rune) bool {
+	return ('0' <= ch && ch <= '9') || ('a' <= ch && ch <= 'f') || ('A' <= ch && ch <= 'F')
+}
+
+// DecodeEscape decodes the escape sequence at the start of s, following its backslash. It
+// returns the character escaped, the length of the sequence in bytes, and why the sequence is
+// invalid, if it is.
+func DecodeEscape(s string) (rune, int, string) {
+	first, size := utf8.DecodeRuneInString(s)
+
+	if size == 0 {
+		return 0, 0, "escape sequence has no character"
+	}
+
+	if ch, ok := escapes[first]; ok {
+		return ch, size, ""
+	}
+
+	switch first {
+	case 'x':
+		if length := hexDigitsLength(s, size); length < 3 {
+			return 0, length, `\x escape must have 2 hexadecimal digits, as in \x41`
+		}
+
+		code, _ := strconv.ParseUint(s[1:3], 16, 8)
+
+		if code > utf8.RuneSelf-1 {
+			return 0, 3, fmt.Sprintf(`\x%v escape is not ASCII, up to \x7F; escape Unicode characters as in \u{%X}`, s[1:3], code)
+		}
+
+		return rune(code), 3, ""
+	case 'u':
+		length := hexDigitsLength(s, size+1)
+
+		if !strings.HasPrefix(s[size:], "{") || !strings.HasPrefix(s[length:], "}") {
+			return 0, length, `\u escape must have hexadecimal digits in braces, as in \u{1F600}`
+		}
+
+		digits := s[size+1 : length]
+
+		if len(digits) < 1 || len(digits) > 6 {
+			return 0, length + 1, `\u escape must have 1 to 6 hexadecimal digits`
+		}
+
+		code, _ := strconv.ParseUint(digits, 16, 32)
+
+		if !utf8.ValidRune(rune(code)) {
+			return 0, length + 1, fmt.Sprintf(`\u{%v} is not a Unicode character`, digits)
+		}
+
+		return rune(code), length + 1, ""
+	}
+
+	return 0, size, fmt.Sprintf(`unknown escape sequence \%c`, first)
+}
+
+// hexDigitsLength returns the length of s once the hexadecimal digits following start are added
+func hexDigitsLength(s string, start int) int {
+	end := start
+
+	if end > len(s) {
+		return len(s)
+	}
+
+	for end < len(s) && IsHexDigit(rune(s[end])) {
+		end++
+	}
+
+	return end
+}
+
+// Unescape decodes the escape sequences of the content of a literal, which the scanner checked
+func Unescape(content string) string {
+	var decoded strings.Builder
+
+	for i := 0; i < len(content); {
+		if content[i] != '\\' {
+			ch, size := utf8.DecodeRuneInString(content[i:])
+			decoded.WriteRune(ch)
+			i += size
+			continue
+		}
+
+		ch, size, err := DecodeEscape(content[i+1:])
+
+		if err != "" {
+			panic("invalid escape sequence in " + content + ": " + err)
+		}
+
+		decoded.WriteRune(ch)
+		i += 1 + size
+	}
+
+	return decoded.String()
+}
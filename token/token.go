@@ -11,6 +11,12 @@ type Token struct {
 	TokenType Type
 	Raw       string
 	Locator   locator.Locator
+	Error     string `json:",omitempty"` // why a literal is malformed, if it is
+	// ErrorLocator locates the error within the literal, e.g. an invalid escape sequence, if it
+	// is not at the start of the literal
+	ErrorLocator locator.Locator `json:"-"`
+	// Trivia are the whitespace and comments preceding the token, which a lossless scanner keeps
+	Trivia []*Token `json:"-"`
 }
 
 func (tok *Token) String() string {
@@ -25,11 +31,40 @@ func (tok *Token) GetLocation() string {
 	return tok.Locator.Locate()
 }
 
+// FullText returns the source text of the token, trivia included
+func (tok *Token) FullText() string {
+	var text strings.Builder
+
+	for _, trivia := range tok.Trivia {
+		text.WriteString(trivia.Raw)
+	}
+
+	text.WriteString(tok.Raw)
+
+	return text.String()
+}
+
+// GetErrorLocation returns the location of the error of a malformed literal
+func (tok *Token) GetErrorLocation() string {
+	if tok.ErrorLocator == nil {
+		return tok.GetLocation()
+	}
+
+	return tok.ErrorLocator.Locate()
+}
+
 // IllegalToken is a factory for generating a default illegal token
 func IllegalToken(raw string, locator locator.Locator) *Token {
 	return &Token{TokenType: ILLEGAL, Raw: raw, Locator: locator}
 }
 
+// MalformedToken is a factory for a literal of the given type that is not well formed, e.g. a
+// hexadecimal literal without digits. Keeping the type lets the parser report error instead
+// of failing to parse what follows.
+func MalformedToken(tokenType Type, raw string, locator locator.Locator, error string) *Token {
+	return &Token{TokenType: tokenType, Raw: raw, Locator: locator, Error: error}
+}
+
 // EOFToken is a factory for generating a default EOF token
 func EOFToken(locator locator.Locator) *Token {
 	return &Token{TokenType: EOF, Raw: "", Locator: locator}
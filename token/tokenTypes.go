@@ -10,6 +10,7 @@ const (
 	ILLEGAL Type = iota
 	EOF
 	COMMENT
+	WHITESPACE
 
 	LITERAL
 	IDENTIFIER
@@ -65,6 +66,9 @@ const (
 	SEMI // SEMI: semi-colon (;)
 	COLON
 	COMMA
+
+	ARROW    // result of a function (->)
+	ELLIPSIS // any number of arguments (...)
 	operatorEnd
 
 	keywordStart
@@ -94,13 +98,17 @@ const (
 	FALSE
 
 	PRINT
+
+	EXTERN
+	FUNC
 	keywordEnd
 )
 
 var tokens = [...]string{
-	ILLEGAL: "ILLEGAL",
-	EOF:     "EOF",
-	COMMENT: "COMMENT",
+	ILLEGAL:    "ILLEGAL",
+	EOF:        "EOF",
+	COMMENT:    "COMMENT",
+	WHITESPACE: "WHITESPACE",
 
 	LITERAL:         "LITERAL",
 	IDENTIFIER:      "IDENTIFIER",
@@ -153,6 +161,9 @@ var tokens = [...]string{
 	COLON: ":",
 	COMMA: ",",
 
+	ARROW:    "->",
+	ELLIPSIS: "...",
+
 	LET:   "let",
 	CONST: "const",
 
@@ -178,6 +189,9 @@ var tokens = [...]string{
 	FALSE: "false",
 
 	PRINT: "print",
+
+	EXTERN: "extern",
+	FUNC:   "func",
 }
 
 func (tokenType Type) String() string {
@@ -0,0 +1,88 @@
+package typing
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+// FunctionType is the type of a function called by name, e.g. a host function or an extern.
+// Functions are not values: they can only be called.
+type FunctionType struct {
+	Params   []Typing
+	Variadic bool   // whether any number of arguments follow those of Params, as with printf
+	Result   Typing // VOID for a function returning nothing
+}
+
+// NewFunctionType is a factory
+func NewFunctionType(result Typing, params ...Typing) *FunctionType {
+	return &FunctionType{Params: params, Result: result}
+}
+
+// NewVariadicFunctionType is a factory of the type of a function taking any number of arguments
+// after params
+func NewVariadicFunctionType(result Typing, params ...Typing) *FunctionType {
+	return &FunctionType{Params: params, Variadic: true, Result: result}
+}
+
+func (functionType *FunctionType) Equals(typing Typing) bool {
+	functionType2, ok := typing.(*FunctionType)
+
+	if !ok || len(functionType2.Params) != len(functionType.Params) || functionType2.Variadic != functionType.Variadic ||
+		!functionType2.Result.Equals(functionType.Result) {
+		return false
+	}
+
+	for i, param := range functionType.Params {
+		if !param.Equals(functionType2.Params[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Size is the size of a pointer to the function
+func (functionType *FunctionType) Size() int {
+	return 8
+}
+
+// IrType is a pointer to the llvm function type
+func (functionType *FunctionType) IrType() types.Type {
+	return types.NewPointer(functionType.IrFuncType())
+}
+
+// IrFuncType is the llvm function type, e.g. of the declaration of the function
+func (functionType *FunctionType) IrFuncType() *types.FuncType {
+	params := make([]types.Type, len(functionType.Params))
+
+	for i, param := range functionType.Params {
+		params[i] = param.IrType()
+	}
+
+	funcType := types.NewFunc(functionType.Result.IrType(), params...)
+	funcType.Variadic = functionType.Variadic
+
+	return funcType
+}
+
+// String writes the type like "FUNC(INT, STRING) -> BOOL", or "FUNC(STRING, ...) -> INT" for a
+// variadic function
+func (functionType *FunctionType) String() string {
+	params := make([]string, 0, len(functionType.Params)+1)
+
+	for _, param := range functionType.Params {
+		params = append(params, param.String())
+	}
+
+	if functionType.Variadic {
+		params = append(params, "...")
+	}
+
+	return "FUNC(" + strings.Join(params, ", ") + ") -> " + functionType.Result.String()
+}
+
+func (functionType *FunctionType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(functionType.String())
+}